@@ -0,0 +1,336 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+// Attempt describes an attempt to be made by Execute.
+type Attempt struct {
+	// Iteration is the iteration number of the attempt; the first attempt
+	// is iteration zero.
+	Iteration uint
+
+	// Iterations is the maximum number of attempts the execution may make.
+	Iterations uint
+
+	// Started is the time at which the execution began.
+	Started time.Time
+
+	// Last is the error returned by the previous attempt, or nil if there
+	// was none.
+	Last error
+
+	// Wait is the waiting period that preceded the attempt (before any time
+	// scaling): the warmup period for the first attempt. This is only set
+	// for an Attempt obtained from AttemptFromContext.
+	Wait time.Duration
+
+	// Deadline is the time by which the attempt must complete; that is,
+	// the earlier of the deadline imposed by WithAttemptTimeout and that of
+	// the Context given to Execute. It is zero if there is neither. This is
+	// only set for an Attempt obtained from AttemptFromContext, allowing a
+	// Func to pass tighter deadlines on to its own downstream calls.
+	Deadline time.Time
+
+	// r is the executing Rerun, used to calculate the remaining schedule.
+	r *Rerun
+
+	// cost is the weight of the attempt (see WithCost).
+	cost float64
+}
+
+// Remaining returns the number of attempts that may still follow the
+// receiver, or Forever if the execution is unlimited.
+func (a Attempt) Remaining() uint {
+	if a.Iterations == Forever {
+		return Forever
+	}
+
+	if a.Iteration >= a.Iterations {
+		return 0
+	}
+	return a.Iterations - a.Iteration - 1
+}
+
+// IsLast reports whether the receiver is the final attempt that may be made;
+// a Func might use this to try a more expensive, "last ditch" variant of
+// its operation.
+func (a Attempt) IsLast() bool {
+	return a.Remaining() == 0
+}
+
+// RemainingWait returns the total of the waiting periods scheduled before
+// each of the attempts that may still follow the receiver (as scaled by any
+// time scale factor). This is exact for a Deterministic Algorithm; for any
+// other, it is an estimate obtained by calling Wait for each remaining
+// iteration. Waiting periods requested using RetryAfter are not included.
+// For an unlimited execution (see Forever), the largest possible Duration is
+// returned.
+func (a Attempt) RemainingWait() time.Duration {
+	if a.r == nil {
+		return 0
+	}
+
+	if a.Iterations == Forever {
+		return math.MaxInt64
+	}
+
+	r := a.r.estimator()
+
+	var d time.Duration
+	for i := a.Iteration + 1; i < a.Iterations; i++ {
+		d = addSaturating(d, r.delay(r.wait(i)))
+	}
+
+	return d
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the Attempt being made when ctx is the Context
+// passed by Execute to a ContextFunc or hook, and false otherwise.
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	a, ok := ctx.Value(attemptKey{}).(Attempt)
+	return a, ok
+}
+
+// WithAttemptTimeout returns a pointer to its receiver after configuring
+// Execute to make each call to its Func under a Context having a deadline no
+// more than d after the attempt begins. An attempt failing after exceeding
+// this deadline -- while the Context given to Execute remains live -- is
+// retried as if the Func had returned ErrDoRetry. A zero d disables attempt
+// timeouts while a negative d causes subsequent calls to the receiver's Err
+// method to return an error wrapping ErrNegativeDuration.
+//
+// The deadline is measured by the receiver's Clock (see WithClock), so a fake
+// Clock such as the reruntest package's FakeClock controls attempt timeouts
+// as well as waiting periods.
+//
+// Only a ContextFunc (see WithContextFunction) can observe the deadline; a
+// Func ignoring it cannot be interrupted -- unless the receiver is also
+// configured using WithAbandonHungAttempts.
+func (r Rerun) WithAttemptTimeout(d time.Duration) *Rerun {
+	r.attemptTimeout = d
+	return &r
+}
+
+// WithAbandonHungAttempts returns a pointer to its receiver after configuring
+// Execute to stop waiting for any attempt that overruns its attempt timeout
+// (see WithAttemptTimeout), so that a Func which hangs -- perhaps because it
+// cannot observe its Context -- does not block Execute indefinitely. Each
+// call to the Func is then made from a goroutine of its own. An abandoned
+// call is left running but its result is discarded and the attempt is
+// retried as if it had timed out; likewise, Execute stops waiting for an
+// attempt once the Context given to Execute becomes done.
+//
+// Since an abandoned call may still be running when the next attempt begins
+// (or after Execute returns), the Func must be safe for concurrent use and
+// should not communicate its results through shared variables; Do and
+// DoContext handle this correctly. If panic recovery is disabled (see
+// WithoutPanicRecovery), a panicking Func crashes the program. This option
+// has no effect without an attempt timeout.
+func (r Rerun) WithAbandonHungAttempts() *Rerun {
+	r.abandonHung = true
+	return &r
+}
+
+// attemptContext returns two Contexts derived from ctx for the given Attempt:
+// vctx carries the Attempt (for hooks) and actx additionally carries its
+// timeout (for the Func). The returned CancelFunc must be called once the
+// attempt is complete.
+func (r Rerun) attemptContext(ctx context.Context, a Attempt) (vctx, actx context.Context, cancel context.CancelFunc) {
+	actx, cancel = ctx, func() {}
+	switch {
+	case r.attemptTimeout <= 0:
+	case r.clock == nil:
+		actx, cancel = context.WithTimeout(ctx, r.attemptTimeout)
+	default:
+		actx, cancel = withClockTimeout(ctx, r.clock, r.attemptTimeout)
+	}
+
+	if dl, ok := actx.Deadline(); ok {
+		a.Deadline = dl
+	}
+
+	vctx = context.WithValue(ctx, attemptKey{}, a)
+	if actx != ctx {
+		actx = context.WithValue(actx, attemptKey{}, a)
+	} else {
+		actx = vctx
+	}
+
+	return vctx, actx, cancel
+}
+
+// AlgorithmCtx is an optional interface that may be implemented by an
+// Algorithm needing more than an iteration number to determine its waiting
+// periods; for example, to consult the Context's deadline, values carried by
+// the Context such as tracing baggage, or some external coordinator. When
+// the Algorithm attached to a Rerun implements AlgorithmCtx, Execute calls
+// WaitContext in place of Wait.
+//
+// Methods that plan a schedule without executing it (such as LatestGiveUp or
+// a precomputed schedule from a Deterministic Algorithm) still call Wait.
+//
+// Algorithms wrapping others (such as those returned by Min, Max, Cap,
+// Floor, ImmediateRetries and WithJitter) implement both AlgorithmCtx and
+// AlgorithmErr by calling the same method of each Algorithm they wrap; when
+// Execute uses one of them, the Context reaches any wrapped AlgorithmCtx and
+// errors are returned from any wrapped AlgorithmErr alike.
+type AlgorithmCtx interface {
+	Algorithm
+
+	// WaitContext returns the waiting period to be imposed before the
+	// given Attempt. ctx is derived from the Context given to Execute.
+	WaitContext(ctx context.Context, a Attempt) time.Duration
+}
+
+// AlgorithmErr is an optional interface that may be implemented by an
+// Algorithm which, rather than encoding failures as negative durations, can
+// report why it is unable to provide a waiting period -- e.g. because the next
+// retry cannot be scheduled outside of a blackout window or its calculation
+// would overflow. When the Algorithm attached to a Rerun implements
+// AlgorithmErr (but not AlgorithmCtx), Execute calls WaitErr in place of Wait
+// and returns any error it reports immediately.
+type AlgorithmErr interface {
+	Algorithm
+
+	// WaitErr returns the waiting period to be imposed before retry n or
+	// an error explaining why no retry should be made.
+	WaitErr(n uint) (time.Duration, error)
+}
+
+// Resetter is an optional interface that may be implemented by a stateful
+// Algorithm -- one whose waiting periods depend upon those it returned
+// before, such as DecorrelatedJitter. Since a single Algorithm may be shared
+// by many concurrent calls to Execute, Execute calls Reset once as each
+// execution begins and uses the Algorithm returned for that execution
+// alone. Reset must not modify its receiver.
+//
+// Algorithms wrapping others (such as those returned by Min, Max, Cap,
+// Floor, ImmediateRetries and WithJitter) implement Resetter by resetting each
+// Algorithm they wrap.
+type Resetter interface {
+	Algorithm
+
+	// Reset returns an equivalent Algorithm holding new, initial state.
+	Reset() Algorithm
+}
+
+// reset returns the Algorithm to be used by a single execution of algo; see
+// Resetter.
+func reset(algo Algorithm) Algorithm {
+	if rs, ok := algo.(Resetter); ok {
+		return rs.Reset()
+	}
+	return algo
+}
+
+// estimator returns a copy of the receiver whose Algorithm holds state of its
+// own (see Resetter) so that waiting periods may be estimated without
+// disturbing those of any execution in progress.
+func (r Rerun) estimator() Rerun {
+	r.algorithm = reset(r.algorithm)
+	return r
+}
+
+// waitContext returns the waiting period Execute should impose before the
+// given Attempt, preferring the receiver's AlgorithmCtx or AlgorithmErr (if
+// implemented) over its precomputed schedule or Algorithm.
+func (r Rerun) waitContext(ctx context.Context, a Attempt) (time.Duration, error) {
+	switch algo := r.algorithm.(type) {
+	case wrapper:
+		if !dynamic(algo) {
+			return r.wait(a.Iteration), nil
+		}
+		return algo.waitUsing(a.Iteration, attemptWait(ctx, a))
+	case AlgorithmCtx:
+		return algo.WaitContext(ctx, a), nil
+	case AlgorithmErr:
+		return algo.WaitErr(a.Iteration)
+	default:
+		return r.wait(a.Iteration), nil
+	}
+}
+
+// waitFunc returns the waiting period imposed by algo before retry n, or an
+// error explaining why no retry should be made.
+type waitFunc func(algo Algorithm, n uint) (time.Duration, error)
+
+// wrapper is implemented by the Algorithms wrapping others (such as those
+// returned by Min, Max, Cap, Floor, ImmediateRetries and WithJitter) so that
+// each of their Wait, WaitContext and WaitErr methods -- and Execute -- may
+// obtain the waiting periods of the wrapped Algorithms in the same manner.
+type wrapper interface {
+	Algorithm
+
+	// wrapped returns the Algorithms wrapped by the receiver.
+	wrapped() []Algorithm
+
+	// waitUsing returns the waiting period before retry n, obtaining those
+	// of the wrapped Algorithms from wait.
+	waitUsing(n uint, wait waitFunc) (time.Duration, error)
+}
+
+// dynamic reports whether algo, or any Algorithm it wraps, implements
+// AlgorithmCtx or AlgorithmErr.
+func dynamic(algo Algorithm) bool {
+	switch algo := algo.(type) {
+	case wrapper:
+		return slices.ContainsFunc(algo.wrapped(), dynamic)
+	case AlgorithmCtx, AlgorithmErr:
+		return true
+	default:
+		return false
+	}
+}
+
+// plainWait is a waitFunc calling only Wait.
+func plainWait(algo Algorithm, n uint) (time.Duration, error) {
+	return algo.Wait(n), nil
+}
+
+// contextWait returns a waitFunc calling the WaitContext method of any
+// AlgorithmCtx (with a copy of a describing retry n) and Wait otherwise.
+func contextWait(ctx context.Context, a Attempt) waitFunc {
+	return func(algo Algorithm, n uint) (time.Duration, error) {
+		if ac, ok := algo.(AlgorithmCtx); ok {
+			a.Iteration = n
+			return ac.WaitContext(ctx, a), nil
+		}
+		return algo.Wait(n), nil
+	}
+}
+
+// errWait is a waitFunc calling the WaitErr method of any AlgorithmErr and
+// Wait otherwise.
+func errWait(algo Algorithm, n uint) (time.Duration, error) {
+	if ae, ok := algo.(AlgorithmErr); ok {
+		return ae.WaitErr(n)
+	}
+	return algo.Wait(n), nil
+}
+
+// attemptWait returns a waitFunc obtaining waiting periods as Execute does:
+// through the waitUsing method of a wrapper, the WaitContext method of an
+// AlgorithmCtx, the WaitErr method of an AlgorithmErr or else Wait.
+func attemptWait(ctx context.Context, a Attempt) waitFunc {
+	var wait waitFunc
+	wait = func(algo Algorithm, n uint) (time.Duration, error) {
+		switch algo := algo.(type) {
+		case wrapper:
+			return algo.waitUsing(n, wait)
+		case AlgorithmCtx:
+			a.Iteration = n
+			return algo.WaitContext(ctx, a), nil
+		default:
+			return errWait(algo, n)
+		}
+	}
+	return wait
+}
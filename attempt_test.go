@@ -0,0 +1,312 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// deadlineDelay is an AlgorithmCtx that waits for half the time remaining
+// before the Context's deadline.
+type deadlineDelay struct {
+	FixedDelay
+	attempts []Attempt
+}
+
+func (dd *deadlineDelay) WaitContext(ctx context.Context, a Attempt) time.Duration {
+	dd.attempts = append(dd.attempts, a)
+	if dl, ok := ctx.Deadline(); ok {
+		return time.Until(dl) / 2
+	}
+	return time.Duration(dd.FixedDelay)
+}
+
+func TestAlgorithmCtx(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errFailed := errors.New("failed")
+		dd := &deadlineDelay{FixedDelay: FixedDelay(time.Hour)}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		err := New(3).WithAlgorithm(dd).WithFunction(func(uint) error {
+			return ErrDoRetry.Wrap(errFailed)
+		}).Execute(ctx)
+
+		if !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if got, want := time.Since(start), 45*time.Second; got != want {
+			t.Errorf("Execute took %v; wanted %v", got, want)
+		}
+
+		if len(dd.attempts) != 2 {
+			t.Fatalf("WaitContext called %d times; wanted 2", len(dd.attempts))
+		}
+
+		for i, a := range dd.attempts {
+			if a.Iteration != uint(i+1) || a.Iterations != 3 || !a.Started.Equal(start) || !errors.Is(a.Last, errFailed) {
+				t.Errorf("attempt %d: %+v", i, a)
+			}
+		}
+	})
+}
+
+// blackoutDelay is an AlgorithmErr refusing to schedule retries beyond the
+// second.
+type blackoutDelay struct{ FixedDelay }
+
+var errBlackout = errors.New("cannot schedule within blackout window")
+
+func (bd blackoutDelay) WaitErr(n uint) (time.Duration, error) {
+	if n > 2 {
+		return 0, errBlackout
+	}
+	return bd.Wait(n), nil
+}
+
+func TestAlgorithmErr(t *testing.T) {
+	var calls int
+
+	err := New(5).WithAlgorithm(blackoutDelay{}).WithFunction(func(uint) error {
+		calls++
+		return ErrDoRetry
+	}).Execute(context.Background())
+
+	if err != errBlackout || calls != 3 {
+		t.Errorf("Execute() == %v after %d calls; wanted %v after 3", err, calls, errBlackout)
+	}
+}
+
+func TestAttemptTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		r := New(3).WithAlgorithm(FixedDelay(time.Second)).WithAttemptTimeout(10 * time.Second)
+
+		err := r.WithContextFunction(func(ctx context.Context, i uint) error {
+			a, ok := AttemptFromContext(ctx)
+			if !ok || a.Iteration != i {
+				t.Fatalf("AttemptFromContext() == (%+v, %v) in attempt %d", a, ok, i)
+			}
+
+			if want := time.Now().Add(10 * time.Second); !a.Deadline.Equal(want) {
+				t.Errorf("attempt %d: Deadline == %v; wanted %v", i, a.Deadline, want)
+			}
+
+			if i < 2 {
+				<-ctx.Done() // hang until the attempt times out
+				return ctx.Err()
+			}
+			return nil
+		}).Execute(ctx)
+
+		if err != nil {
+			t.Errorf("Execute() == %v; wanted nil", err)
+		}
+
+		if got, want := time.Since(start), 22*time.Second; got != want {
+			t.Errorf("Execute took %v; wanted %v", got, want)
+		}
+	})
+
+	if err := New(2).WithAttemptTimeout(-time.Second).Err(); !errors.Is(err, ErrNegativeDuration) {
+		t.Errorf("Err() == %v; wanted %v", err, ErrNegativeDuration)
+	}
+}
+
+func TestAbandonHungAttempts(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+
+		r := New(3).WithAlgorithm(FixedDelay(time.Second)).WithAttemptTimeout(10 * time.Second).WithAbandonHungAttempts()
+
+		// The first two calls hang, ignoring their Contexts, and then
+		// "succeed" once released -- after the third has succeeded.
+		start := time.Now()
+		v, err := Do(context.Background(), r, func(i uint) (uint, error) {
+			if i < 2 {
+				<-release
+			}
+			return i, nil
+		})
+
+		if err != nil || v != 2 {
+			t.Errorf("Do() == %d, %v; wanted 2, <nil>", v, err)
+		}
+
+		if got, want := time.Since(start), 22*time.Second; got != want {
+			t.Errorf("Do took %v; wanted %v", got, want)
+		}
+
+		close(release)
+		synctest.Wait()
+
+		// Without a timeout, the option has no effect.
+		var calls int
+		err = New(2).WithAbandonHungAttempts().WithFunction(func(uint) error {
+			calls++
+			time.Sleep(time.Hour)
+			return nil
+		}).Execute(context.Background())
+
+		if err != nil || calls != 1 {
+			t.Errorf("Execute() == %v after %d calls; wanted <nil> after 1", err, calls)
+		}
+	})
+}
+
+func TestAttemptRemaining(t *testing.T) {
+	type remaining struct {
+		attempts uint
+		wait     time.Duration
+		last     bool
+	}
+
+	synctest.Test(t, func(t *testing.T) {
+		var got []remaining
+
+		r := New(4).
+			WithAlgorithm(LinearDelay{Base: time.Second, SlopeDuration: time.Second}).
+			WithContextFunction(func(ctx context.Context, _ uint) error {
+				a, _ := AttemptFromContext(ctx)
+				got = append(got, remaining{a.Remaining(), a.RemainingWait(), a.IsLast()})
+				return ErrDoRetry
+			})
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		want := []remaining{
+			{3, 6 * time.Second, false},
+			{2, 5 * time.Second, false},
+			{1, 3 * time.Second, false},
+			{0, 0, true},
+		}
+
+		if !slices.Equal(got, want) {
+			t.Errorf("remaining == %v; wanted %v", got, want)
+		}
+	})
+}
+
+// statefulDelay is a Resetter whose waiting periods grow by a second with
+// each call to Wait made upon the same state.
+type statefulDelay struct {
+	calls *int
+}
+
+func (statefulDelay) OK(uint) error         { return nil }
+func (statefulDelay) Warmup() time.Duration { return 0 }
+
+func (cd statefulDelay) Wait(uint) time.Duration {
+	if cd.calls == nil {
+		return time.Second
+	}
+	*cd.calls++
+	return time.Duration(*cd.calls) * time.Second
+}
+
+func (cd statefulDelay) Reset() Algorithm {
+	cd.calls = new(int)
+	return cd
+}
+
+func TestAttemptRemainingWaitState(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls []time.Duration
+		start := time.Now()
+
+		r := New(4).
+			WithAlgorithm(statefulDelay{}).
+			WithContextFunction(func(ctx context.Context, _ uint) error {
+				calls = append(calls, time.Since(start))
+				a, _ := AttemptFromContext(ctx)
+				a.RemainingWait()
+				return ErrDoRetry
+			})
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if want := []time.Duration{0, time.Second, 3 * time.Second, 6 * time.Second}; !slices.Equal(calls, want) {
+			t.Errorf("calls made at %v; wanted %v", calls, want)
+		}
+	})
+}
+
+func TestClockTimeoutDerived(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		clk  Clock
+	}{
+		{"real", SystemClock()},
+		{"fake", &stepClock{now: time.Unix(0, 0)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ctx, cancel := withClockTimeout(context.Background(), tc.clk, time.Second)
+				defer cancel()
+
+				child, cancelChild := context.WithTimeout(ctx, time.Hour)
+				defer cancelChild()
+
+				<-child.Done()
+
+				if err := ctx.Err(); err != context.DeadlineExceeded {
+					t.Errorf("ctx.Err() == %v; wanted %v", err, context.DeadlineExceeded)
+				}
+
+				if err := child.Err(); err != context.DeadlineExceeded {
+					t.Errorf("child.Err() == %v; wanted %v", err, context.DeadlineExceeded)
+				}
+
+				if err := context.Cause(child); err != context.DeadlineExceeded {
+					t.Errorf("context.Cause(child) == %v; wanted %v", err, context.DeadlineExceeded)
+				}
+			})
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := withClockTimeout(parent, frozenClock{}, time.Hour)
+		defer cancel()
+
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+
+		cancelParent()
+		<-child.Done()
+
+		if err := child.Err(); err != context.Canceled {
+			t.Errorf("child.Err() == %v; wanted %v", err, context.Canceled)
+		}
+	})
+}
+
+// frozenClock is a Clock whose time never changes and whose timers never
+// expire.
+type frozenClock struct{}
+
+func (frozenClock) Now() time.Time {
+	return time.Unix(0, 0)
+}
+
+func (frozenClock) NewTimer(time.Duration) Timer {
+	return realTimer{time.NewTimer(math.MaxInt64)}
+}
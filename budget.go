@@ -0,0 +1,153 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RetryBudget is a token bucket limiting the rate of retries made by all of
+// the Reruns sharing it (see WithBudget). Every retry -- though not the first
+// attempt of each execution -- consumes a token (or, if cost weighting is
+// configured, its weight in tokens; see WithCost) and tokens are replenished
+// at a steady rate. When no token is available, Execute stops retrying at once
+// and returns ErrBudgetExhausted, preventing a fleet of clients from turning
+// a partial outage into a retry storm.
+//
+// Tokens are replenished according to the Clock of the Rerun drawing upon
+// the budget (see WithClock), so Reruns sharing a RetryBudget should share a
+// Clock, too.
+//
+// A RetryBudget is safe for concurrent use and must be created by
+// NewRetryBudget.
+type RetryBudget struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // tokens per second
+	tokens   float64
+	last     time.Time // zero until the first retry
+	clock    Clock     // that of the Rerun most recently drawing upon it
+
+	spent      uint64
+	consumed   float64
+	rejections uint64
+}
+
+// NewRetryBudget returns a new, full RetryBudget allowing up to retries
+// retries within each window. Tokens are replenished continuously, at a rate
+// of retries per window, up to a maximum of retries.
+func NewRetryBudget(retries uint, window time.Duration) *RetryBudget {
+	rate := math.Inf(1)
+	if window > 0 {
+		rate = float64(retries) / window.Seconds()
+	}
+
+	return &RetryBudget{
+		capacity: float64(retries),
+		rate:     rate,
+		tokens:   float64(retries),
+	}
+}
+
+// BudgetStats is a snapshot of the live state of a RetryBudget, suitable for
+// export as metrics so operators may be alerted before an exhausted budget
+// causes requests to fail fast.
+type BudgetStats struct {
+	// Tokens is the number of retries currently available.
+	Tokens float64
+
+	// Capacity is the maximum number of tokens the budget may hold.
+	Capacity float64
+
+	// Rate is the number of tokens replenished per second.
+	Rate float64
+
+	// Spent is the total number of retries that consumed tokens.
+	Spent uint64
+
+	// Consumed is the total number of tokens consumed by retries. This
+	// exceeds Spent when retries are cost-weighted (see WithCost).
+	Consumed float64
+
+	// Rejections is the total number of retries refused for want of a token.
+	Rejections uint64
+}
+
+// Stats returns a snapshot of the receiver's current state, measured using
+// the Clock of the Rerun that most recently drew upon it.
+func (b *RetryBudget) Stats() BudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.clock != nil {
+		b.refill(b.clock.Now())
+	}
+
+	return b.stats()
+}
+
+// take consumes n tokens, as of the current time according to clk, returning
+// false if fewer are available along with a snapshot of the receiver's state
+// afterward. A nil receiver always returns true.
+func (b *RetryBudget) take(clk Clock, n float64) (BudgetStats, bool) {
+	if b == nil {
+		return BudgetStats{}, true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.clock = clk
+	b.refill(clk.Now())
+
+	if b.tokens < n {
+		b.rejections++
+		return b.stats(), false
+	}
+
+	b.tokens -= n
+	b.consumed += n
+	b.spent++
+
+	return b.stats(), true
+}
+
+// stats returns a snapshot of the receiver's state. The receiver's mutex must
+// be held.
+func (b *RetryBudget) stats() BudgetStats {
+	return BudgetStats{
+		Tokens:     b.tokens,
+		Capacity:   b.capacity,
+		Rate:       b.rate,
+		Spent:      b.spent,
+		Consumed:   b.consumed,
+		Rejections: b.rejections,
+	}
+}
+
+// refill adds the tokens accrued between the last refill and now. The
+// receiver's mutex must be held.
+func (b *RetryBudget) refill(now time.Time) {
+	if b.last.IsZero() {
+		b.last = now
+		return
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.capacity, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// WithBudget returns a pointer to its receiver after attaching the given
+// RetryBudget, which is typically shared by many Reruns. Before each retry,
+// once nothing else has refused it (see WithMaxElapsed), Execute takes a
+// token from the budget; if none is available, Execute returns
+// ErrBudgetExhausted immediately rather than waiting. Passing nil detaches
+// any budget.
+func (r Rerun) WithBudget(b *RetryBudget) *Rerun {
+	r.budget = b
+	return &r
+}
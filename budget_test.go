@@ -0,0 +1,162 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		budget := NewRetryBudget(3, time.Minute)
+
+		var calls int
+		r := New(3).WithAlgorithm(FixedDelay(0)).WithBudget(budget).WithFunction(func(uint) error {
+			calls++
+			return ErrDoRetry
+		})
+
+		// The first execution spends 2 of the 3 tokens, the second spends
+		// the last and is then refused.
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrBudgetExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrBudgetExhausted)
+		}
+
+		if calls != 5 {
+			t.Errorf("Func called %d times; wanted 5", calls)
+		}
+
+		want := BudgetStats{Tokens: 0, Capacity: 3, Rate: 0.05, Spent: 3, Consumed: 3, Rejections: 1}
+		if got := r.Stats().Budget; got == nil || *got != want {
+			t.Errorf("Stats().Budget == %+v; wanted %+v", got, want)
+		}
+
+		// A token is replenished every 20s so, after that long, one more
+		// retry may be made.
+		time.Sleep(20 * time.Second)
+
+		if got := budget.Stats().Tokens; got != 1 {
+			t.Errorf("Tokens == %v after 20s; wanted 1", got)
+		}
+
+		calls = 0
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrBudgetExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrBudgetExhausted)
+		}
+
+		if calls != 2 {
+			t.Errorf("Func called %d times after 20s; wanted 2", calls)
+		}
+	})
+}
+
+func TestRetryBudgetClock(t *testing.T) {
+	budget := NewRetryBudget(1, time.Minute)
+	clk := &stepClock{now: time.Unix(0, 0)}
+
+	r := New(2).WithAlgorithm(FixedDelay(0)).WithClock(clk).WithBudget(budget).WithFunction(func(uint) error {
+		return ErrDoRetry
+	})
+
+	if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+		t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+	}
+
+	if err := r.Execute(context.Background()); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Execute() == %v; wanted %v", err, ErrBudgetExhausted)
+	}
+
+	// Tokens are replenished as the Rerun's Clock advances, not real time.
+	clk.now = clk.now.Add(time.Minute)
+
+	if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+		t.Errorf("Execute() == %v after 1m; wanted %v", err, ErrAttemptsExhausted)
+	}
+}
+
+func TestRetryBudgetRefused(t *testing.T) {
+	budget := NewRetryBudget(10, time.Hour)
+
+	// A retry refused for exceeding MaxElapsed takes no tokens.
+	err := New(3).WithAlgorithm(FixedDelay(time.Hour)).WithMaxElapsed(time.Minute).WithBudget(budget).
+		WithFunction(func(uint) error { return ErrDoRetry }).
+		Execute(context.Background())
+
+	if !errors.As(err, new(*ElapsedError)) {
+		t.Errorf("Execute() == %v; wanted an *ElapsedError", err)
+	}
+
+	if got := budget.Stats(); got.Spent != 0 || got.Tokens != got.Capacity {
+		t.Errorf("budget spent %d retries leaving %v of %v tokens; wanted none spent", got.Spent, got.Tokens, got.Capacity)
+	}
+}
+
+func TestRetryBudgetShared(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		budget := NewRetryBudget(10, time.Hour)
+
+		var calls atomic.Int64
+		fn := func(uint) error {
+			calls.Add(1)
+			return ErrDoRetry
+		}
+
+		// Distinct Reruns, executing concurrently, draw upon the same budget.
+		var (
+			mu      sync.Mutex
+			refused int
+			wg      sync.WaitGroup
+		)
+
+		for i := range 20 {
+			r := New(4).WithName(fmt.Sprint("client-", i)).WithAlgorithm(FixedDelay(time.Second)).WithBudget(budget).WithFunction(fn)
+
+			wg.Go(func() {
+				if err := r.Execute(context.Background()); errors.Is(err, ErrBudgetExhausted) {
+					mu.Lock()
+					refused++
+					mu.Unlock()
+				}
+			})
+		}
+
+		wg.Wait()
+
+		// Each of the 20 executions wants 3 retries but, with only 10 tokens
+		// to go around between them, every one is refused.
+		if got := calls.Load(); got != 30 || refused != 20 {
+			t.Errorf("Func called %d times with %d executions refused; wanted 30 and 20", got, refused)
+		}
+
+		if s := budget.Stats(); s.Spent != 10 || s.Rejections != 20 {
+			t.Errorf("Stats() == %+v; wanted 10 spent and 20 rejections", s)
+		}
+	})
+}
+
+// stepClock is a Clock whose time changes only when its now field is set.
+// Its timers expire immediately.
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	return c.now
+}
+
+func (c *stepClock) NewTimer(time.Duration) Timer {
+	return realTimer{time.NewTimer(0)}
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CheckAlgorithm verifies that algo honors the contract documented by the
+// Algorithm interface for a Rerun of n iterations. It is intended to give
+// authors of custom Algorithms a ready-made conformance test, e.g.:
+//
+//	if err := rerun.CheckAlgorithm(MyDelay{...}, 10); err != nil {
+//		t.Error(err)
+//	}
+//
+// If algo.OK(n) returns an error, there is nothing further to verify and
+// CheckAlgorithm returns nil. Otherwise, the following must hold:
+//
+//   - Warmup returns a non-negative value.
+//
+//   - Wait returns a non-negative value for every iteration from 1 to n-1.
+//
+//   - Wait(0) returns zero, since no wait precedes the first attempt.
+//
+// All violations found are returned, joined into a single error, with each
+// wrapping ErrAlgorithmContract. Since every wait must be checked, n may not
+// be Forever; ErrUnlimited is returned if it is.
+func CheckAlgorithm(algo Algorithm, n uint) error {
+	if algo == nil {
+		return ErrNilAlgorithm
+	}
+
+	if n == Forever {
+		return ErrUnlimited
+	}
+
+	if algo.OK(n) != nil {
+		return nil
+	}
+
+	var errs []error
+
+	violation := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrAlgorithmContract}, args...)...))
+	}
+
+	if d := algo.Warmup(); d < 0 {
+		violation("Warmup() == %v after OK(%d) returned nil", d, n)
+	}
+
+	if d := algo.Wait(0); d != 0 {
+		violation("Wait(0) == %v; wanted 0", d)
+	}
+
+	for i := uint(1); i < n; i++ {
+		if d := algo.Wait(i); d < 0 {
+			violation("Wait(%d) == %v after OK(%d) returned nil", i, d, n)
+		}
+	}
+
+	return errors.Join(errs...)
+}
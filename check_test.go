@@ -0,0 +1,40 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// badDelay violates the Algorithm contract by approving any iteration count
+// while calculating negative waits.
+type badDelay struct{}
+
+func (badDelay) OK(uint) error           { return nil }
+func (badDelay) Warmup() time.Duration   { return -time.Second }
+func (badDelay) Wait(uint) time.Duration { return -time.Second }
+
+func TestCheckAlgorithm(t *testing.T) {
+	for _, algo := range []Algorithm{
+		Fixed1s,
+		LinearDelay{Base: time.Second, Slope: float64(-100 * time.Millisecond)},
+		LogarithmicDelay{Units: Millisecond, Amplifier: 300, Coefficient: 20, Modifier: -14, VerticalOffset: -400},
+		ExponentialDelay{Base: time.Second, Multiplier: 2, Max: time.Minute},
+	} {
+		if err := CheckAlgorithm(algo, 20); err != nil {
+			t.Errorf("CheckAlgorithm(%#v, 20) returned unexpected error: %v", algo, err)
+		}
+	}
+
+	err := CheckAlgorithm(badDelay{}, 3)
+	if !errors.Is(err, ErrAlgorithmContract) {
+		t.Fatalf("CheckAlgorithm(badDelay{}, 3) == %v; wanted %v", err, ErrAlgorithmContract)
+	}
+
+	// Warmup, Wait(0), Wait(1) and Wait(2)
+	if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != 4 {
+		t.Errorf("CheckAlgorithm(badDelay{}, 3) reported %d violations; wanted 4:\n%v", got, err)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+// WithRetryIf returns a pointer to its receiver after configuring Execute to
+// retry whenever retryable returns true for the error returned by the
+// receiver's Func, just as if the Func had returned ErrDoRetry. This spares a
+// Func wrapping some third-party client from translating each of its
+// transient failures into ErrDoRetry; for example:
+//
+//	r = r.WithRetryIf(func(err error) bool {
+//		return errors.Is(err, io.ErrUnexpectedEOF) || os.IsTimeout(err)
+//	})
+//
+// The predicate is consulted only for errors not otherwise handled by
+// Execute: ErrDoRetry and errors created by RetryAfter or Progress still
+// call for a retry, whatever the predicate says, while panics remain subject
+// to WithRetryOnPanic. Passing nil restores the default behavior of retrying
+// only for ErrDoRetry.
+func (r Rerun) WithRetryIf(retryable func(error) bool) *Rerun {
+	r.retryIf = retryable
+	return &r
+}
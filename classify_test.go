@@ -0,0 +1,43 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"testing/synctest"
+)
+
+func TestWithRetryIf(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var reasons []Reason
+		hooks := Hooks{OnDecision: func(_ context.Context, d Decision) {
+			reasons = append(reasons, d.Reason)
+		}}
+
+		errs := []error{io.ErrUnexpectedEOF, ErrDoRetry, io.ErrUnexpectedEOF, io.EOF}
+		fn := func(i uint) error { return errs[i] }
+
+		r := New(5).WithNotify(hooks).WithFunction(fn).WithRetryIf(func(err error) bool {
+			return errors.Is(err, io.ErrUnexpectedEOF)
+		})
+
+		if err := r.Execute(context.Background()); err != io.EOF {
+			t.Errorf("Execute() == %v; wanted %v", err, io.EOF)
+		}
+
+		want := []Reason{ReasonRetryable, ReasonRetrySignal, ReasonRetryable, ReasonNonRetryable}
+		if !slices.Equal(reasons, want) {
+			t.Errorf("Decision reasons == %q; wanted %q", reasons, want)
+		}
+
+		if err := r.WithRetryIf(nil).Execute(context.Background()); err != io.ErrUnexpectedEOF {
+			t.Errorf("Execute() without predicate == %v; wanted %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+}
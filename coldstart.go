@@ -0,0 +1,94 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"sync"
+	"time"
+)
+
+// WarmState records when an operation last succeeded so that a Rerun
+// configured using WithColdStartWarmup can tell whether that operation is
+// "warm" -- i.e. whether it has succeeded recently enough that a warmup
+// period is no longer needed. A single WarmState may be shared by any number
+// of Reruns (and goroutines) performing the same operation.
+//
+// The zero value is ready to use but a WarmState must not be copied after
+// first use.
+type WarmState struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// LastSuccess returns the time at which the receiver last recorded a
+// successful execution, or the zero Time if none has been recorded.
+func (ws *WarmState) LastSuccess() time.Time {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.last
+}
+
+// Reset discards the receiver's record of any successful execution so that
+// the next Execute call relying on it is treated as a cold start.
+func (ws *WarmState) Reset() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.last = time.Time{}
+}
+
+func (ws *WarmState) succeeded(t time.Time) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if t.After(ws.last) {
+		ws.last = t
+	}
+}
+
+func (ws *WarmState) warm(now time.Time, ttl time.Duration) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return !ws.last.IsZero() && now.Sub(ws.last) < ttl
+}
+
+type coldStart struct {
+	ttl   time.Duration
+	state *WarmState
+}
+
+// WithColdStartWarmup returns a pointer to its receiver after configuring
+// Execute to impose its Algorithm's warmup period only for a cold start;
+// that is, when no execution recorded in state has succeeded within the
+// last ttl. Hot services then avoid paying the warmup penalty on every call
+// while genuine cold starts still get it. A ttl of zero means every start
+// is cold while a negative ttl causes Err to report ErrNegativeDuration.
+//
+// If state is nil, a new WarmState is allocated and shared by the receiver
+// and any Rerun subsequently derived from it. To share warmth across
+// separately constructed Reruns (e.g. for each of several methods calling
+// the same backend) pass each the same WarmState.
+func (r Rerun) WithColdStartWarmup(ttl time.Duration, state *WarmState) *Rerun {
+	if state == nil {
+		state = new(WarmState)
+	}
+	r.coldStart = &coldStart{ttl: ttl, state: state}
+	return &r
+}
+
+func (cs *coldStart) err() error {
+	if cs != nil && cs.ttl < 0 {
+		return fieldError("ColdStartTTL", cs.ttl, ErrNegativeDuration)
+	}
+	return nil
+}
+
+// warm reports whether the warmup period should be skipped at time now.
+func (cs *coldStart) warm(now time.Time) bool {
+	return cs != nil && cs.state.warm(now, cs.ttl)
+}
+
+// succeeded records a successful execution at time now (if configured).
+func (cs *coldStart) succeeded(now time.Time) {
+	if cs != nil {
+		cs.state.succeeded(now)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Min returns an Algorithm whose warmup and waiting periods are the lesser of
+// those produced by a and b for each iteration -- e.g. to hold an otherwise
+// unbounded curve beneath a hard cap.
+func Min(a, b Algorithm) Algorithm {
+	return &combined{a: a, b: b}
+}
+
+// Max returns an Algorithm whose warmup and waiting periods are the greater
+// of those produced by a and b for each iteration -- e.g. to ensure a wait
+// never falls below an exponential schedule.
+func Max(a, b Algorithm) Algorithm {
+	return &combined{a: a, b: b, max: true}
+}
+
+// combined is the Algorithm returned by Min and Max.
+type combined struct {
+	a, b Algorithm
+	max  bool
+}
+
+// OK returns an error if either component Algorithm is nil or its OK method
+// returns an error. All problems found are reported together (using
+// errors.Join).
+func (c *combined) OK(n uint) error {
+	var errs []error
+
+	for _, algo := range []Algorithm{c.a, c.b} {
+		if algo == nil {
+			errs = append(errs, ErrNilAlgorithm)
+		} else {
+			errs = append(errs, algo.OK(n))
+		}
+	}
+
+	return joinErrors(errs...)
+}
+
+func (c *combined) Warmup() time.Duration {
+	return c.pick(c.a.Warmup(), c.b.Warmup())
+}
+
+func (c *combined) Wait(n uint) time.Duration {
+	d, _ := c.waitUsing(n, plainWait)
+	return d
+}
+
+// WaitContext returns the lesser (or greater) of the waiting periods preceding
+// the given Attempt, passing ctx to either component Algorithm that is an
+// AlgorithmCtx. This method implements the AlgorithmCtx interface.
+func (c *combined) WaitContext(ctx context.Context, a Attempt) time.Duration {
+	d, _ := c.waitUsing(a.Iteration, contextWait(ctx, a))
+	return d
+}
+
+// WaitErr returns the lesser (or greater) of the waiting periods preceding
+// retry n, or the error reported by either component Algorithm that is an
+// AlgorithmErr. This method implements the AlgorithmErr interface.
+func (c *combined) WaitErr(n uint) (time.Duration, error) {
+	return c.waitUsing(n, errWait)
+}
+
+func (c *combined) wrapped() []Algorithm {
+	return []Algorithm{c.a, c.b}
+}
+
+func (c *combined) waitUsing(n uint, wait waitFunc) (time.Duration, error) {
+	a, err := wait(c.a, n)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := wait(c.b, n)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.pick(a, b), nil
+}
+
+func (c *combined) pick(a, b time.Duration) time.Duration {
+	if c.max {
+		return max(a, b)
+	}
+	return min(a, b)
+}
+
+// Deterministic reports whether both component Algorithms are Deterministic.
+func (c *combined) Deterministic() bool {
+	return isDeterministic(c.a) && isDeterministic(c.b)
+}
+
+// Reset returns a copy of the receiver wrapping reset copies of both of its
+// component Algorithms. This method implements the Resetter interface.
+func (c *combined) Reset() Algorithm {
+	return &combined{a: reset(c.a), b: reset(c.b), max: c.max}
+}
+
+func (c *combined) String() string {
+	name := "min"
+	if c.max {
+		name = "max"
+	}
+	return fmt.Sprintf("%s(%v, %v)", name, c.a, c.b)
+}
+
+// ImmediateRetries returns an Algorithm making its first k retries with no
+// waiting period at all -- quickly riding out brief, local blips -- before
+// deferring to algo. The schedule of algo begins anew after the immediate
+// retries, such that the wait before retry k+1 is algo.Wait(1). The warmup
+// period of algo is retained.
+func ImmediateRetries(k uint, algo Algorithm) Algorithm {
+	return &immediate{k: k, algo: algo}
+}
+
+// immediate is the Algorithm returned by ImmediateRetries.
+type immediate struct {
+	k    uint
+	algo Algorithm
+}
+
+func (im *immediate) OK(n uint) error {
+	if im.algo == nil {
+		return ErrNilAlgorithm
+	}
+	return im.algo.OK(n - min(n, im.k))
+}
+
+func (im *immediate) Warmup() time.Duration {
+	return im.algo.Warmup()
+}
+
+func (im *immediate) Wait(n uint) time.Duration {
+	d, _ := im.waitUsing(n, plainWait)
+	return d
+}
+
+// WaitContext returns the waiting period preceding the given Attempt; after
+// the immediate retries, this is obtained from the WaitContext method of the
+// wrapped Algorithm if it is an AlgorithmCtx (which is given an Attempt
+// renumbered accordingly). This method implements the AlgorithmCtx
+// interface.
+func (im *immediate) WaitContext(ctx context.Context, a Attempt) time.Duration {
+	d, _ := im.waitUsing(a.Iteration, contextWait(ctx, a))
+	return d
+}
+
+// WaitErr returns the waiting period preceding retry n; after the immediate
+// retries, this is obtained from the WaitErr method of the wrapped Algorithm
+// if it is an AlgorithmErr, along with any error it reports. This method
+// implements the AlgorithmErr interface.
+func (im *immediate) WaitErr(n uint) (time.Duration, error) {
+	return im.waitUsing(n, errWait)
+}
+
+func (im *immediate) wrapped() []Algorithm {
+	return []Algorithm{im.algo}
+}
+
+func (im *immediate) waitUsing(n uint, wait waitFunc) (time.Duration, error) {
+	if n <= im.k {
+		return 0, nil
+	}
+	return wait(im.algo, n-im.k)
+}
+
+// Deterministic reports whether the wrapped Algorithm is Deterministic.
+func (im *immediate) Deterministic() bool {
+	return isDeterministic(im.algo)
+}
+
+// Reset returns a copy of the receiver wrapping a reset copy of its
+// Algorithm. This method implements the Resetter interface.
+func (im *immediate) Reset() Algorithm {
+	return &immediate{k: im.k, algo: reset(im.algo)}
+}
+
+func (im *immediate) String() string {
+	return fmt.Sprintf("%d immediate, then %v", im.k, im.algo)
+}
+
+// Cap returns an Algorithm whose waiting periods are those of algo but never
+// more than max -- e.g. to hold a LogarithmicDelay beneath 30s. Unlike Min,
+// the warmup period of algo is retained as is. A negative max causes the OK
+// method to return ErrNegativeDuration.
+func Cap(algo Algorithm, max time.Duration) Algorithm {
+	return &bounded{algo: algo, bound: max, cap: true}
+}
+
+// Floor returns an Algorithm whose waiting periods are those of algo but
+// never less than min -- e.g. to ensure at least 50ms between attempts.
+// Unlike Max, the warmup period of algo is retained as is. A negative min
+// causes the OK method to return ErrNegativeDuration.
+func Floor(algo Algorithm, min time.Duration) Algorithm {
+	return &bounded{algo: algo, bound: min}
+}
+
+// bounded is the Algorithm returned by Cap and Floor.
+type bounded struct {
+	algo  Algorithm
+	bound time.Duration
+	cap   bool
+}
+
+func (b *bounded) OK(n uint) error {
+	var errs []error
+
+	if b.algo == nil {
+		errs = append(errs, ErrNilAlgorithm)
+	} else {
+		errs = append(errs, b.algo.OK(n))
+	}
+
+	if b.bound < 0 {
+		field := "Floor"
+		if b.cap {
+			field = "Cap"
+		}
+		errs = append(errs, fieldError(field, b.bound, ErrNegativeDuration))
+	}
+
+	return joinErrors(errs...)
+}
+
+func (b *bounded) Warmup() time.Duration {
+	return b.algo.Warmup()
+}
+
+func (b *bounded) Wait(n uint) time.Duration {
+	d, _ := b.waitUsing(n, plainWait)
+	return d
+}
+
+// WaitContext returns the bounded waiting period preceding the given Attempt,
+// as returned by the WaitContext method of the wrapped Algorithm if it is an
+// AlgorithmCtx. This method implements the AlgorithmCtx interface.
+func (b *bounded) WaitContext(ctx context.Context, a Attempt) time.Duration {
+	d, _ := b.waitUsing(a.Iteration, contextWait(ctx, a))
+	return d
+}
+
+// WaitErr returns the bounded waiting period preceding retry n, as returned
+// by the WaitErr method of the wrapped Algorithm if it is an AlgorithmErr, or
+// the error it reports. This method implements the AlgorithmErr interface.
+func (b *bounded) WaitErr(n uint) (time.Duration, error) {
+	return b.waitUsing(n, errWait)
+}
+
+func (b *bounded) wrapped() []Algorithm {
+	return []Algorithm{b.algo}
+}
+
+func (b *bounded) waitUsing(n uint, wait waitFunc) (time.Duration, error) {
+	if n == 0 {
+		return 0, nil
+	}
+
+	d, err := wait(b.algo, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if b.cap {
+		return min(d, b.bound), nil
+	}
+
+	return max(d, b.bound), nil
+}
+
+// Deterministic reports whether the wrapped Algorithm is Deterministic.
+func (b *bounded) Deterministic() bool {
+	return isDeterministic(b.algo)
+}
+
+// Reset returns a copy of the receiver wrapping a reset copy of its
+// Algorithm. This method implements the Resetter interface.
+func (b *bounded) Reset() Algorithm {
+	return &bounded{algo: reset(b.algo), bound: b.bound, cap: b.cap}
+}
+
+func (b *bounded) String() string {
+	if b.cap {
+		return fmt.Sprintf("%v capped at %v", b.algo, b.bound)
+	}
+	return fmt.Sprintf("%v floored at %v", b.algo, b.bound)
+}
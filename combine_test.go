@@ -0,0 +1,152 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestMinMax(t *testing.T) {
+	exp := ExponentialDelay{Start: time.Second, Base: 100 * time.Millisecond, Multiplier: 2}
+	fixed := FixedDelay(time.Second)
+
+	lo, hi := Min(exp, fixed), Max(exp, fixed)
+
+	if got := lo.Warmup(); got != 0 {
+		t.Errorf("Min.Warmup() == %v; wanted 0", got)
+	}
+
+	if got := hi.Warmup(); got != time.Second {
+		t.Errorf("Max.Warmup() == %v; wanted 1s", got)
+	}
+
+	// exp waits: 100ms, 200ms, 400ms, 800ms, 1.6s
+	for i, want := range []struct{ lo, hi time.Duration }{
+		{0, 0},
+		{100 * time.Millisecond, time.Second},
+		{200 * time.Millisecond, time.Second},
+		{400 * time.Millisecond, time.Second},
+		{800 * time.Millisecond, time.Second},
+		{time.Second, 1600 * time.Millisecond},
+	} {
+		if got := lo.Wait(uint(i)); got != want.lo {
+			t.Errorf("Min.Wait(%d) == %v; wanted %v", i, got, want.lo)
+		}
+
+		if got := hi.Wait(uint(i)); got != want.hi {
+			t.Errorf("Max.Wait(%d) == %v; wanted %v", i, got, want.hi)
+		}
+	}
+
+	if err := CheckAlgorithm(lo, 10); err != nil {
+		t.Errorf("CheckAlgorithm(Min) == %v", err)
+	}
+
+	if err := Max(nil, fixed).OK(5); err != ErrNilAlgorithm {
+		t.Errorf("Max(nil, ...).OK() == %v; wanted %v", err, ErrNilAlgorithm)
+	}
+
+	if got, want := hi.(fmt.Stringer).String(), "max(exponential 100ms×2, warmup 1s, fixed 1s)"; got != want {
+		t.Errorf("String() == %q; wanted %q", got, want)
+	}
+}
+
+func TestImmediateRetries(t *testing.T) {
+	algo := ImmediateRetries(2, LinearDelay{Base: time.Second, Slope: float64(time.Second)})
+
+	for i, want := range []time.Duration{0, 0, 0, time.Second, 2 * time.Second} {
+		if got := algo.Wait(uint(i)); got != want {
+			t.Errorf("Wait(%d) == %v; wanted %v", i, got, want)
+		}
+	}
+
+	if got := New(5).WithAlgorithm(algo).Waits(); len(got) != 4 {
+		t.Errorf("Waits() == %v; wanted a precomputed schedule", got)
+	}
+}
+
+func TestCapFloor(t *testing.T) {
+	ld := LinearDelay{Start: time.Minute, Base: 10 * time.Millisecond, SlopeDuration: 10 * time.Second}
+	algo := Floor(Cap(ld, 30*time.Second), 50*time.Millisecond)
+
+	if err := CheckAlgorithm(algo, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := algo.Warmup(); got != time.Minute {
+		t.Errorf("Warmup() == %v; wanted %v", got, time.Minute)
+	}
+
+	for n, want := range map[uint]time.Duration{
+		1: 50 * time.Millisecond,
+		2: 10*time.Second + 10*time.Millisecond,
+		3: 20*time.Second + 10*time.Millisecond,
+		4: 30 * time.Second,
+		9: 30 * time.Second,
+	} {
+		if got := algo.Wait(n); got != want {
+			t.Errorf("Wait(%d) == %v; wanted %v", n, got, want)
+		}
+	}
+
+	if err := Cap(ld, -time.Second).OK(2); !errors.Is(err, ErrNegativeDuration) {
+		t.Errorf("Cap(-1s).OK() == %v; wanted %v", err, ErrNegativeDuration)
+	}
+
+	if got, want := fmt.Sprint(Cap(Fixed1s, time.Millisecond)), "fixed 1s capped at 1ms"; got != want {
+		t.Errorf("String() == %q; wanted %q", got, want)
+	}
+}
+
+func TestWrappedAlgorithmCtxErr(t *testing.T) {
+	wrappers := map[string]func(Algorithm) Algorithm{
+		"min":       func(a Algorithm) Algorithm { return Min(a, FixedDelay(time.Hour)) },
+		"max":       func(a Algorithm) Algorithm { return Max(FixedDelay(0), a) },
+		"cap":       func(a Algorithm) Algorithm { return Cap(a, time.Hour) },
+		"floor":     func(a Algorithm) Algorithm { return Floor(a, 0) },
+		"immediate": func(a Algorithm) Algorithm { return ImmediateRetries(0, a) },
+		"jitter":    func(a Algorithm) Algorithm { return WithJitter(a, ProportionalJitter(0)) },
+	}
+
+	for name, wrap := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				dd := &deadlineDelay{FixedDelay: FixedDelay(time.Hour)}
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+
+				start := time.Now()
+				err := New(3).WithAlgorithm(wrap(dd)).WithFunction(func(uint) error {
+					return ErrDoRetry
+				}).Execute(ctx)
+
+				if !errors.Is(err, ErrAttemptsExhausted) {
+					t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+				}
+
+				if got, want := time.Since(start), 45*time.Second; got != want {
+					t.Errorf("Execute took %v; wanted %v", got, want)
+				}
+			})
+
+			var calls int
+
+			err := New(5).WithAlgorithm(wrap(blackoutDelay{})).WithFunction(func(uint) error {
+				calls++
+				return ErrDoRetry
+			}).Execute(context.Background())
+
+			if err != errBlackout || calls != 3 {
+				t.Errorf("Execute() == %v after %d calls; wanted %v after 3", err, calls, errBlackout)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestConcurrentExecute runs a single, shared Rerun from many goroutines at
+// once. It is most useful when run with the race detector enabled.
+func TestConcurrentExecute(t *testing.T) {
+	const goroutines = 50
+
+	var calls atomic.Int64
+
+	r := New(4).
+		WithAlgorithm(ExponentialDelay{Base: time.Microsecond, Multiplier: 2}).
+		WithHintSmoothing(HintSmoothing{Alpha: 0.5}).
+		WithRetryOnPanic(1).
+		WithContextError(TimedOut).
+		WithDeadlineWarning(func(*DeadlineError) {}).
+		WithFunction(func(i uint) error {
+			calls.Add(1)
+			switch i {
+			case 0:
+				return ErrDoRetry
+			case 1:
+				return RetryAfter(time.Microsecond)
+			case 2:
+				panic("transient")
+			default:
+				return nil
+			}
+		})
+
+	var wg sync.WaitGroup
+
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			var err error
+			if g%2 == 0 {
+				err = r.Execute(ctx)
+			} else {
+				var rp *Report
+				rp, err = r.ExecuteReport(ctx)
+				if len(rp.Attempts) != 4 {
+					t.Errorf("Report holds %d attempts; wanted 4", len(rp.Attempts))
+				}
+			}
+
+			if err != nil {
+				t.Errorf("Execute() returned unexpected error: %v", err)
+			}
+
+			if err := r.Err(); err != nil {
+				t.Errorf("Err() returned unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got, want := calls.Load(), int64(4*goroutines); got != want {
+		t.Errorf("Func called %d times; wanted %d", got, want)
+	}
+
+	st := r.Stats()
+	want := Stats{Executions: goroutines, Attempts: 4 * goroutines, Retries: 3 * goroutines, Waited: st.Waited}
+	if st != want {
+		t.Errorf("Stats() == %+v; wanted %+v", st, want)
+	}
+
+	// Each execution waits at least 1µs + 1µs (hint) + 4µs.
+	if min := goroutines * 6 * time.Microsecond; st.Waited < min {
+		t.Errorf("Stats().Waited == %v; wanted at least %v", st.Waited, min)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ContextErrorFunc maps the termination of the Context given to Execute to
+// the error that Execute should return. ctxErr is the value of ctx.Err()
+// (either context.Canceled or context.DeadlineExceeded), cause is the value of
+// context.Cause(ctx), and last is the most recent error returned by the Func
+// (or nil if it was never called).
+type ContextErrorFunc func(ctxErr, cause, last error) error
+
+// WithContextError returns a pointer to its receiver after configuring fn to
+// determine the error Execute returns when its Context becomes done. This
+// allows callers to treat cancellation and deadlines differently -- e.g. for
+// telemetry or fallback behavior. See TimedOut for a ready-made mapping.
+// Passing nil restores the default behavior of returning context.Cause(ctx).
+func (r Rerun) WithContextError(fn ContextErrorFunc) *Rerun {
+	r.ctxErrFunc = fn
+	return &r
+}
+
+func (r Rerun) contextError(ctx context.Context, last error) error {
+	if r.ctxErrFunc == nil {
+		return context.Cause(ctx)
+	}
+	return r.ctxErrFunc(ctx.Err(), context.Cause(ctx), last)
+}
+
+// TimedOut is a ContextErrorFunc that returns a *TimedOutError when the
+// Context's deadline has been exceeded and the Context's cause when it has
+// been canceled.
+func TimedOut(ctxErr, cause, last error) error {
+	if errors.Is(ctxErr, context.DeadlineExceeded) {
+		return &TimedOutError{Cause: cause, Last: last}
+	}
+	return cause
+}
+
+// TimedOutError is returned by Execute, when configured with
+// WithContextError(TimedOut), if its Context's deadline is exceeded. It wraps
+// both the Context's cause (normally context.DeadlineExceeded) and the last
+// error returned by the Func, so either can be matched using errors.Is or
+// errors.As -- except that any retry signal is stripped from the latter
+// (leaving only the cause it carries) so that a Rerun enclosing the one that
+// timed out does not mistake it for a request to retry.
+type TimedOutError struct {
+	// Cause is the value of context.Cause for the expired Context.
+	Cause error
+
+	// Last is the most recent error returned by the Func, or nil if the
+	// deadline passed before it was ever called.
+	Last error
+}
+
+func (e *TimedOutError) Error() string {
+	if e.Last == nil {
+		return fmt.Sprintf("timed out: %v", e.Cause)
+	}
+	return fmt.Sprintf("timed out: %v (last error: %v)", e.Cause, e.Last)
+}
+
+func (e *TimedOutError) Unwrap() []error {
+	last := signalCause(e.Last)
+	if last == nil {
+		return []error{e.Cause}
+	}
+	return []error{e.Cause, last}
+}
+
+type maxAttemptsKey struct{}
+
+// DisableRetries returns a Context derived from ctx under which every call to
+// Execute makes just a single attempt. This allows a request-level decision
+// -- e.g. "this is a health check" or "this request is already over budget"
+// -- to suppress retries everywhere beneath it, even in code that is unaware
+// of the decision. It is equivalent to LimitAttempts(ctx, 1).
+func DisableRetries(ctx context.Context) context.Context {
+	return LimitAttempts(ctx, 1)
+}
+
+// LimitAttempts returns a Context derived from ctx under which every call to
+// Execute makes no more than n attempts, regardless of the iterations for
+// which its Rerun is configured. A limit already carried by ctx that is lower
+// than n remains in effect, and a limit of zero is treated as 1 since
+// Execute always makes at least one attempt. When the limit cuts an
+// execution short, Execute returns an *AttemptsError as usual.
+func LimitAttempts(ctx context.Context, n uint) context.Context {
+	n = max(n, 1)
+	if m, ok := MaxAttemptsFromContext(ctx); ok && m <= n {
+		return ctx
+	}
+	return context.WithValue(ctx, maxAttemptsKey{}, n)
+}
+
+// MaxAttemptsFromContext returns the attempt limit carried by ctx (see
+// LimitAttempts and DisableRetries) and true, or zero and false if ctx
+// carries no limit.
+func MaxAttemptsFromContext(ctx context.Context) (uint, bool) {
+	n, ok := ctx.Value(maxAttemptsKey{}).(uint)
+	return n, ok
+}
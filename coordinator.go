@@ -0,0 +1,224 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Coordinator observes the retries scheduled by every Rerun whose Algorithm
+// it decorates (see Coordinate) and damps retry storms by spreading them out
+// over time. The timeline is divided into slots of a fixed width and only a
+// limited number of retries may be scheduled within each. When many retry
+// loops, perhaps all reacting to the same outage, would otherwise retry at
+// about the same moment, those beyond the limit have their waiting periods
+// extended into the following slots until each finds room.
+//
+// A Coordinator is safe for concurrent use and must be created by
+// NewCoordinator. A single Coordinator is generally shared by all of the
+// Reruns in a process; see DefaultCoordinator.
+type Coordinator struct {
+	slot  time.Duration
+	limit uint
+
+	mu    sync.Mutex
+	slots map[int64]uint // slot number -> retries scheduled
+	first int64          // earliest slot number that may be in use
+
+	delayed uint64
+	extra   time.Duration
+}
+
+// DefaultCoordinator is a process-wide Coordinator allowing up to 100
+// retries in each 100ms slot. It is used only by Algorithms explicitly
+// decorated using its Coordinate method.
+var DefaultCoordinator = NewCoordinator(100*time.Millisecond, 100)
+
+// NewCoordinator returns a new Coordinator allowing up to limit retries to
+// be scheduled within each slot of the given width. A limit of zero is
+// treated as 1, while a non-positive slot width disables coordination
+// entirely.
+func NewCoordinator(slot time.Duration, limit uint) *Coordinator {
+	return &Coordinator{
+		slot:  slot,
+		limit: max(limit, 1),
+		slots: make(map[int64]uint),
+	}
+}
+
+// CoordinatorStats is a snapshot of the activity of a Coordinator.
+type CoordinatorStats struct {
+	// Delayed is the total number of retries whose waiting periods were
+	// extended to avoid an overfull slot.
+	Delayed uint64
+
+	// Extra is the total additional waiting time imposed on those retries.
+	Extra time.Duration
+}
+
+// Stats returns a snapshot of the receiver's activity.
+func (c *Coordinator) Stats() CoordinatorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CoordinatorStats{Delayed: c.delayed, Extra: c.extra}
+}
+
+// Coordinate returns an Algorithm whose warmup and waiting periods are those
+// of algo except that each retry is first scheduled with the receiver, which
+// may extend its waiting period to spread out simultaneous retries. Any
+// Rerun may opt into coordination by decorating its Algorithm:
+//
+//	r := rerun.New(5).WithAlgorithm(rerun.DefaultCoordinator.Coordinate(algo))
+//
+// The returned Algorithm is never Deterministic since its waiting periods
+// depend on the activity of other Reruns. It implements AlgorithmCtx and so
+// any AlgorithmErr implemented by algo is disregarded. Retries are scheduled
+// according to the Clock and time scale of the executing Rerun (see
+// WithClock and WithTimeScale) and not at all under WithoutSleep. A retry
+// Execute then refuses (e.g. because of WithMaxElapsed or WithBudget)
+// releases its reservation.
+//
+// Since other decorators (such as WithJitter and Cap) would alter the waiting
+// periods after they have been reserved, moving retries out of their slots,
+// Coordinate should be applied last -- e.g.
+// Coordinate(WithJitter(algo, FullJitter)) rather than
+// WithJitter(Coordinate(algo), FullJitter).
+func (c *Coordinator) Coordinate(algo Algorithm) Algorithm {
+	return &coordinated{c: c, algo: algo}
+}
+
+// schedule reserves room for a retry to be made d after now and returns the
+// additional waiting time needed to reach it, along with a function releasing
+// the reservation should the retry not be made after all (or nil if no
+// reservation was made).
+func (c *Coordinator) schedule(now time.Time, d time.Duration) (time.Duration, func()) {
+	if c.slot <= 0 || d < 0 {
+		return 0, nil
+	}
+
+	target := now.Add(d).UnixNano()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(now.UnixNano() / int64(c.slot))
+
+	n := target / int64(c.slot)
+	for c.slots[n] >= c.limit {
+		n++
+	}
+	c.slots[n]++
+
+	var extra time.Duration
+	if at := n * int64(c.slot); at > target {
+		extra = time.Duration(at - target)
+		c.delayed++
+		c.extra += extra
+	}
+
+	return extra, func() { c.release(n, extra) }
+}
+
+// release discards the reservation made by schedule in slot n for a retry
+// that was to have been extended by extra.
+func (c *Coordinator) release(n int64, extra time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// n.b. The slot may already have been pruned.
+	if c.slots[n] > 0 {
+		c.slots[n]--
+	}
+
+	if extra > 0 {
+		c.delayed--
+		c.extra -= extra
+	}
+}
+
+// prune discards the reservations held for any slot prior to current.
+func (c *Coordinator) prune(current int64) {
+	if current <= c.first {
+		return
+	}
+
+	for n := range c.slots {
+		if n < current {
+			delete(c.slots, n)
+		}
+	}
+
+	c.first = current
+}
+
+// coordinated is the Algorithm returned by Coordinator.Coordinate.
+type coordinated struct {
+	c    *Coordinator
+	algo Algorithm
+}
+
+func (co *coordinated) OK(n uint) error {
+	if co.algo == nil {
+		return ErrNilAlgorithm
+	}
+	return co.algo.OK(n)
+}
+
+func (co *coordinated) Warmup() time.Duration {
+	return co.algo.Warmup()
+}
+
+// Wait returns the uncoordinated waiting period of the wrapped Algorithm;
+// only the retries actually scheduled by Execute (see WaitContext) are
+// coordinated so that planning methods such as LatestGiveUp make no
+// reservations.
+func (co *coordinated) Wait(n uint) time.Duration {
+	return co.algo.Wait(n)
+}
+
+// WaitContext schedules the retry described by a with the Coordinator and
+// returns its (possibly extended) waiting period. Since the slots are
+// measured in real time, the retry is scheduled after its scaled waiting
+// period and any extension is scaled back before being added.
+func (co *coordinated) WaitContext(ctx context.Context, a Attempt) time.Duration {
+	var d time.Duration
+	if ac, ok := co.algo.(AlgorithmCtx); ok {
+		d = ac.WaitContext(ctx, a)
+	} else {
+		d = co.algo.Wait(a.Iteration)
+	}
+
+	r := a.r
+	if r == nil {
+		r = new(Rerun)
+	}
+
+	if r.noSleep {
+		return d
+	}
+
+	extra, release := co.c.schedule(r.clk().Now(), r.delay(d))
+	if e := executionFrom(ctx); e != nil && release != nil {
+		e.reserved(release)
+	}
+
+	if extra == 0 {
+		return d
+	}
+
+	return d + time.Duration(math.Ceil(float64(extra)/r.timeFactor()))
+}
+
+// Reset returns a copy of the receiver wrapping a reset copy of its
+// Algorithm. This method implements the Resetter interface.
+func (co *coordinated) Reset() Algorithm {
+	return &coordinated{c: co.c, algo: reset(co.algo)}
+}
+
+func (co *coordinated) String() string {
+	return fmt.Sprintf("coordinated %v", co.algo)
+}
@@ -0,0 +1,156 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestCoordinatorSchedule(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := NewCoordinator(100*time.Millisecond, 2)
+
+		ms := time.Millisecond
+		want := []time.Duration{1000 * ms, 1000 * ms, 1100 * ms, 1100 * ms, 1200 * ms, 1200 * ms, 1300 * ms}
+		for i, d := range []time.Duration{1000 * ms, 1000 * ms, 1000 * ms, 1050 * ms, 1000 * ms, 1150 * ms, 1150 * ms} {
+			if extra, _ := c.schedule(time.Now(), d); d+extra != want[i] {
+				t.Errorf("schedule #%d (%v) == %v; wanted %v", i, d, d+extra, want[i])
+			}
+		}
+
+		if got, want := c.Stats(), (CoordinatorStats{Delayed: 5, Extra: 550 * ms}); got != want {
+			t.Errorf("Stats() == %+v; wanted %+v", got, want)
+		}
+
+		time.Sleep(time.Minute)
+		if got, _ := c.schedule(time.Now(), time.Second); got != 0 {
+			t.Errorf("schedule after pruning == %v; wanted 0", got)
+		}
+
+		if len(c.slots) != 1 {
+			t.Errorf("%d slots retained after pruning; wanted 1", len(c.slots))
+		}
+	})
+}
+
+func TestCoordinatedExecute(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := NewCoordinator(time.Second, 3)
+
+		var (
+			mu    sync.Mutex
+			calls = make(map[time.Duration]int)
+			wg    sync.WaitGroup
+		)
+
+		start := time.Now()
+		r := New(2).
+			WithAlgorithm(c.Coordinate(FixedDelay(time.Minute))).
+			WithFunction(func(i uint) error {
+				if i == 0 {
+					return ErrDoRetry
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				calls[time.Since(start)]++
+				return nil
+			})
+
+		for range 10 {
+			wg.Go(func() { r.Execute(context.Background()) })
+		}
+		wg.Wait()
+
+		want := map[time.Duration]int{
+			time.Minute:                 3,
+			time.Minute + time.Second:   3,
+			time.Minute + 2*time.Second: 3,
+			time.Minute + 3*time.Second: 1,
+		}
+
+		if len(calls) != len(want) {
+			t.Fatalf("retries made at %v; wanted %v", calls, want)
+		}
+
+		for at, n := range want {
+			if calls[at] != n {
+				t.Errorf("%d retries made at %v; wanted %d", calls[at], at, n)
+			}
+		}
+
+		if got, want := r.Waits(), []time.Duration(nil); len(got) != len(want) {
+			t.Errorf("Waits() == %v; wanted %v", got, want)
+		}
+	})
+}
+
+func TestCoordinatedTimeScale(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := NewCoordinator(time.Second, 1)
+
+		var (
+			mu    sync.Mutex
+			calls []time.Duration
+			wg    sync.WaitGroup
+		)
+
+		start := time.Now()
+		r := New(2).
+			WithTimeScale(0.01).
+			WithAlgorithm(c.Coordinate(FixedDelay(time.Minute))).
+			WithFunction(func(i uint) error {
+				if i == 0 {
+					return ErrDoRetry
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, time.Since(start))
+				return nil
+			})
+
+		for range 3 {
+			wg.Go(func() { r.Execute(context.Background()) })
+		}
+		wg.Wait()
+
+		slices.Sort(calls)
+		want := []time.Duration{
+			600 * time.Millisecond,
+			time.Second,
+			2 * time.Second,
+		}
+
+		if !slices.Equal(calls, want) {
+			t.Errorf("retries made at %v; wanted %v", calls, want)
+		}
+	})
+}
+
+func TestCoordinatedRefused(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := NewCoordinator(time.Second, 1)
+
+		err := New(2).
+			WithAlgorithm(c.Coordinate(FixedDelay(time.Minute))).
+			WithMaxElapsed(time.Second).
+			WithFunction(func(uint) error { return ErrDoRetry }).
+			Execute(context.Background())
+
+		if !errors.As(err, new(*ElapsedError)) {
+			t.Errorf("Execute() == %v; wanted an *ElapsedError", err)
+		}
+
+		// The slot reserved for the refused retry is free once more.
+		if extra, _ := c.schedule(time.Now(), time.Minute); extra != 0 {
+			t.Errorf("schedule after refused retry == %v; wanted 0", extra)
+		}
+	})
+}
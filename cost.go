@@ -0,0 +1,117 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+)
+
+// costPolicy holds the configuration given to WithCost.
+type costPolicy struct {
+	fn    func(Attempt) float64
+	limit float64
+}
+
+// WithCost returns a pointer to its receiver after configuring a cost weight
+// -- in API credits, dollars or whatever unit suits -- for each attempt made
+// by Execute. Before each attempt, cost is called to determine its weight.
+// The weight of each retry is charged against limit, which bounds the total
+// cost of a single execution (including its first attempt), and is also the
+// number of tokens taken from any shared RetryBudget (see WithBudget) in
+// place of the usual single token. If either would be exceeded, Execute
+// gives up at once and returns a *CostError.
+//
+// A limit of zero (or less) imposes no per-execution limit and a weight
+// that is not positive is treated as zero. Passing a nil cost function
+// disables cost weighting.
+func (r Rerun) WithCost(cost func(Attempt) float64, limit float64) *Rerun {
+	if cost == nil {
+		r.cost = nil
+	} else {
+		r.cost = &costPolicy{fn: cost, limit: limit}
+	}
+	return &r
+}
+
+// weight returns the cost of attempt a, or zero if cost weighting has not
+// been configured.
+func (cp *costPolicy) weight(a Attempt) float64 {
+	if cp == nil {
+		return 0
+	}
+
+	if c := cp.fn(a); c > 0 {
+		return c
+	}
+
+	return 0
+}
+
+// charge determines whether the retry described by a may be made given the
+// total weight already spent by the execution, taking its tokens from any
+// shared RetryBudget if so (and reporting the budget's state to the
+// receiver's MetricsRecorder).
+func (r Rerun) charge(ctx context.Context, a Attempt, spent float64) error {
+	tokens := 1.0
+
+	if r.cost != nil {
+		if limit := r.cost.limit; limit > 0 && spent+a.cost > limit {
+			return &CostError{Iteration: a.Iteration, Cost: a.cost, Spent: spent, Limit: limit, Err: lastCause(a.Last)}
+		}
+		tokens = a.cost
+	}
+
+	if r.budget == nil {
+		return nil
+	}
+
+	bs, ok := r.budget.take(r.clk(), tokens)
+	if r.metrics != nil {
+		r.metrics.Budget(ctx, r.name, ok, bs)
+	}
+
+	switch {
+	case ok:
+		return nil
+	case r.cost != nil:
+		return &CostError{Iteration: a.Iteration, Cost: a.cost, Spent: spent, Err: lastCause(a.Last)}
+	default:
+		return ErrBudgetExhausted.Wrap(lastCause(a.Last))
+	}
+}
+
+// CostError is returned by Execute when a retry is refused because its cost
+// weight (see WithCost) would exceed the limit for the execution or the
+// tokens available from a shared RetryBudget. CostError wraps both
+// ErrBudgetExhausted and the error returned by the final attempt so either
+// may be detected using errors.Is as well as errors.As.
+type CostError struct {
+	// Iteration is the iteration number of the refused retry.
+	Iteration uint
+
+	// Cost is the weight of the refused retry.
+	Cost float64
+
+	// Spent is the total weight of all attempts already made.
+	Spent float64
+
+	// Limit is the per-execution limit given to WithCost, or zero if the
+	// retry was instead refused by a shared RetryBudget.
+	Limit float64
+
+	// Err is the error returned by the final attempt, or nil if that was a
+	// bare ErrDoRetry.
+	Err error
+}
+
+func (e *CostError) Error() string {
+	if e.Limit <= 0 {
+		return fmt.Sprintf("%v: retry %d costing %g refused by shared budget%s", ErrBudgetExhausted, e.Iteration, e.Cost, lastSuffix(e.Err))
+	}
+	return fmt.Sprintf("%v: retry %d costing %g would exceed limit of %g (%g spent)%s", ErrBudgetExhausted, e.Iteration, e.Cost, e.Limit, e.Spent, lastSuffix(e.Err))
+}
+
+func (e *CostError) Unwrap() []error {
+	return []error{ErrBudgetExhausted, e.Err}
+}
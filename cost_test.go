@@ -0,0 +1,70 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestCostWeighting(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var reports []AttemptReport
+
+		r := New(10).
+			WithAlgorithm(FixedDelay(time.Second)).
+			WithCost(func(a Attempt) float64 { return float64(a.Iteration + 1) }, 12).
+			WithFunction(func(uint) error { return ErrDoRetry }).
+			WithNotify(Hooks{OnAttemptEnd: func(_ context.Context, ar AttemptReport) {
+				reports = append(reports, ar)
+			}})
+
+		// Attempts cost 1, 2, 3 and 4 (totalling 10) so a fifth, costing 5,
+		// would exceed the limit of 12.
+		err := r.Execute(context.Background())
+
+		var ce *CostError
+		if !errors.As(err, &ce) || !errors.Is(err, ErrBudgetExhausted) {
+			t.Fatalf("Execute() == %v; wanted a *CostError", err)
+		}
+
+		if want := (CostError{Iteration: 4, Cost: 5, Spent: 10, Limit: 12}); *ce != want {
+			t.Errorf("CostError == %+v; wanted %+v", *ce, want)
+		}
+
+		if errors.Is(err, ErrDoRetry) {
+			t.Errorf("errors.Is(%v, ErrDoRetry) == true; wanted false", err)
+		}
+
+		if len(reports) != 4 || reports[3].Cost != 4 {
+			t.Errorf("attempt reports == %+v; wanted 4 with the last costing 4", reports)
+		}
+
+		// Without a limit, the weights are merely recorded.
+		r = r.WithCost(func(Attempt) float64 { return 2.5 }, 0)
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		// A shared budget is charged the weight of each retry.
+		budget := NewRetryBudget(6, time.Hour)
+
+		err = r.WithBudget(budget).Execute(context.Background())
+		if !errors.As(err, &ce) {
+			t.Fatalf("Execute() == %v; wanted a *CostError", err)
+		}
+
+		if want := (CostError{Iteration: 3, Cost: 2.5, Spent: 7.5}); *ce != want {
+			t.Errorf("CostError == %+v; wanted %+v", *ce, want)
+		}
+
+		if got := budget.Stats(); got.Spent != 2 || got.Consumed != 5 {
+			t.Errorf("budget spent %d retries consuming %v tokens; wanted 2 and 5", got.Spent, got.Consumed)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DeadlineError is reported when the minimum schedule for a Rerun -- that is,
+// its warmup period plus every wait period, assuming each call to its Func
+// returns instantly -- cannot complete before the deadline of the Context
+// given to Execute.
+//
+// DeadlineError wraps ErrScheduleTooLong so it may be detected using
+// errors.Is as well as errors.As.
+type DeadlineError struct {
+	// Schedule is the minimum time needed to make all configured attempts.
+	Schedule time.Duration
+
+	// Remaining is the time left before the Context's deadline at the
+	// moment Execute was called.
+	Remaining time.Duration
+}
+
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("%v: need %v but only %v remains", ErrScheduleTooLong, e.Schedule, e.Remaining)
+}
+
+func (e *DeadlineError) Unwrap() error {
+	return ErrScheduleTooLong
+}
+
+// WithDeadlineWarning returns a pointer to its receiver after configuring
+// warn as a callback to be called by Execute, before its first attempt, if the
+// receiver's minimum schedule cannot fit before the Context's deadline. Any
+// previously configured callback is overwritten and passing nil disables the
+// warning. Execute continues normally after calling warn unless strict mode
+// has been enabled with WithStrictDeadline.
+func (r Rerun) WithDeadlineWarning(warn func(*DeadlineError)) *Rerun {
+	r.onDeadline = warn
+	return &r
+}
+
+// WithStrictDeadline returns a pointer to its receiver after enabling strict
+// deadline mode. In strict mode, Execute returns a *DeadlineError immediately
+// (without calling its Func) if the receiver's minimum schedule cannot fit
+// before the Context's deadline. Since an unlimited Rerun (see Forever) relies
+// upon its Context to end, its schedule is never checked.
+func (r Rerun) WithStrictDeadline() *Rerun {
+	r.strictDeadline = true
+	return &r
+}
+
+// WithFirstAttemptGuarantee returns a pointer to its receiver after
+// configuring Execute to skip its warmup period whenever that period would
+// consume all of the time remaining before the Context's deadline. In that
+// case the first attempt is made immediately, with whatever time remains,
+// which is important for best-effort operations (such as cleanup) that
+// should always be attempted at least once. Strict deadline mode (see
+// WithStrictDeadline) takes precedence over this option.
+func (r Rerun) WithFirstAttemptGuarantee() *Rerun {
+	r.firstAttempt = true
+	return &r
+}
+
+// warmup returns the warmup period Execute should impose before its first
+// attempt, made at time now, given the deadline of ctx (if any).
+func (r Rerun) warmup(ctx context.Context, now time.Time) time.Duration {
+	w := r.algorithm.Warmup()
+	if w > 0 && r.coldStart.warm(now) {
+		return 0
+	}
+
+	if !r.firstAttempt || w <= 0 {
+		return w
+	}
+
+	if dl, ok := ctx.Deadline(); ok && dl.Sub(now) <= r.delay(w) {
+		return 0
+	}
+
+	return w
+}
+
+// checkDeadline compares the receiver's minimum schedule to the deadline of
+// ctx (if any), as measured by the receiver's Clock, calling any configured warning callback and, in strict mode,
+// returning a *DeadlineError if the schedule does not fit.
+func (r Rerun) checkDeadline(ctx context.Context) error {
+	if r.onDeadline == nil && !r.strictDeadline || r.unlimited() {
+		return nil
+	}
+
+	dl, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	de := &DeadlineError{Schedule: r.minSchedule(), Remaining: dl.Sub(r.clk().Now())}
+	if de.Schedule <= de.Remaining {
+		return nil
+	}
+
+	if r.onDeadline != nil {
+		r.onDeadline(de)
+	}
+
+	if r.strictDeadline {
+		return de
+	}
+
+	return nil
+}
+
+// minSchedule returns the total of all waiting periods the receiver would
+// impose if every call to its Func were to return ErrDoRetry instantly. For
+// an unlimited Rerun (see Forever), it returns the largest possible Duration.
+func (r Rerun) minSchedule() time.Duration {
+	if r.unlimited() {
+		return math.MaxInt64
+	}
+
+	r = r.estimator()
+
+	d := r.delay(r.algorithm.Warmup())
+	for i := uint(1); i < r.iterations; i++ {
+		d = addSaturating(d, r.delay(r.wait(i)))
+	}
+	return d
+}
+
+// addSaturating returns a+b, neither of which may be negative, or the largest
+// possible Duration should that overflow.
+func addSaturating(a, b time.Duration) time.Duration {
+	if b > math.MaxInt64-a {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// delay returns the time Execute actually spends pausing for d, taking into
+// account any time scale factor or WithoutSleep.
+func (r Rerun) delay(d time.Duration) time.Duration {
+	if r.noSleep {
+		return 0
+	}
+	return r.scale(d)
+}
+
+// EarliestSuccess returns the shortest time, measured from the start of a call
+// to Execute, after which that call may succeed -- assuming each call to the
+// receiver's Func takes the given amount of time. This is the receiver's
+// warmup period followed by a single, successful attempt. An error is
+// returned if the receiver is invalid (see Err).
+func (r Rerun) EarliestSuccess(attempt time.Duration) (time.Duration, error) {
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+	return r.delay(r.algorithm.Warmup()) + attempt, nil
+}
+
+// LatestGiveUp returns the longest time, measured from the start of a call to
+// Execute, before that call will give up -- assuming each call to the
+// receiver's Func takes the given amount of time. This is the receiver's
+// warmup period plus every waiting period and every configured attempt.
+// Together with EarliestSuccess, this allows deadlines and SLAs to be planned
+// before Execute is ever called. An error is returned if the receiver is
+// invalid (see Err).
+//
+// If an attempt timeout is configured (see WithAttemptTimeout), no attempt is
+// assumed to take longer than that timeout -- which holds only if the Func
+// observes its Context or hung attempts are abandoned. If a limit on elapsed
+// time is configured (see WithMaxElapsed), the schedule ends with the first
+// retry that Execute would refuse for exceeding it.
+//
+// Take note that waiting periods requested using RetryAfter may exceed those
+// calculated by the receiver's Algorithm and are not accounted for here.
+// Also, for an Algorithm employing randomness, the result reflects just one
+// possible schedule. An unlimited Rerun (see Forever) without a limit on
+// elapsed time never gives up, so the largest possible Duration is returned;
+// with such a limit, the latest an unlimited Rerun could give up is returned
+// instead: one attempt after the limit (or warmup period, if longer).
+func (r Rerun) LatestGiveUp(attempt time.Duration) (time.Duration, error) {
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+
+	if r.attemptTimeout > 0 {
+		attempt = min(attempt, r.attemptTimeout)
+	}
+
+	r = r.estimator()
+	warmup := r.delay(r.algorithm.Warmup())
+
+	if r.unlimited() {
+		if r.maxElapsed <= 0 {
+			return math.MaxInt64, nil
+		}
+		return addSaturating(max(warmup, r.maxElapsed), attempt), nil
+	}
+
+	elapsed := addSaturating(warmup, attempt)
+	for i := uint(1); i < r.iterations; i++ {
+		wait := r.delay(r.wait(i))
+		if r.maxElapsed > 0 && addSaturating(elapsed, wait) > r.maxElapsed {
+			break
+		}
+		elapsed = addSaturating(elapsed, addSaturating(wait, attempt))
+	}
+
+	return elapsed, nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestCompletionEstimates(t *testing.T) {
+	algo := LinearDelay{Start: time.Second, Base: time.Second, Slope: float64(time.Second)}
+	r := New(4).WithAlgorithm(algo)
+
+	for _, tc := range []struct {
+		name     string
+		r        *Rerun
+		earliest time.Duration
+		latest   time.Duration
+	}{
+		// warmup 1s; waits 1s, 2s, 3s; 4 attempts of 500ms
+		{"plain", r, 1500 * time.Millisecond, 9 * time.Second},
+		{"scaled", r.WithTimeScale(0.5), 1000 * time.Millisecond, 5500 * time.Millisecond},
+		{"nosleep", r.WithoutSleep(), 500 * time.Millisecond, 2 * time.Second},
+		// attempts of 200ms: 1s + 0.2s + 1s + 0.2s + 2s + 0.2s + 3s + 0.2s
+		{"timeout", r.WithAttemptTimeout(200 * time.Millisecond), 1500 * time.Millisecond, 7800 * time.Millisecond},
+		// the third retry (at 5.5s, after 3s) would exceed 6s
+		{"elapsed", r.WithMaxElapsed(6 * time.Second), 1500 * time.Millisecond, 5500 * time.Millisecond},
+		{"forever", New(Forever).WithAlgorithm(algo).WithMaxElapsed(6 * time.Second), 1500 * time.Millisecond, 6500 * time.Millisecond},
+	} {
+		if got, err := tc.r.EarliestSuccess(500 * time.Millisecond); err != nil || got != tc.earliest {
+			t.Errorf("%s: EarliestSuccess() == (%v, %v); wanted (%v, nil)", tc.name, got, err, tc.earliest)
+		}
+
+		if got, err := tc.r.LatestGiveUp(500 * time.Millisecond); err != nil || got != tc.latest {
+			t.Errorf("%s: LatestGiveUp() == (%v, %v); wanted (%v, nil)", tc.name, got, err, tc.latest)
+		}
+	}
+
+	if _, err := New(2).WithAlgorithm(FixedDelay(-1)).LatestGiveUp(0); err == nil {
+		t.Errorf("LatestGiveUp() with invalid algorithm returned nil error")
+	}
+}
+
+func TestStrictDeadline(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		r    *Rerun
+		fits bool
+	}{
+		{"fits", New(3).WithAlgorithm(FixedDelay(time.Minute)), true},
+		{"too long", New(3).WithAlgorithm(FixedDelay(time.Hour)), false},
+		// n.b. Left to overflow, the schedule would appear to be negative.
+		{"overflow", New(3).WithAlgorithm(FixedDelay(math.MaxInt64/2 + 1)), false},
+		{"scaled", New(3).WithAlgorithm(FixedDelay(time.Hour)).WithTimeScale(0.25), true},
+		// The time remaining is measured by the Rerun's Clock.
+		{"clock", New(3).WithAlgorithm(FixedDelay(time.Hour)).WithClock(&stepClock{now: time.Unix(0, 0)}), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+				defer cancel()
+
+				var calls int
+				err := tc.r.WithStrictDeadline().WithFunction(func(uint) error {
+					calls++
+					return nil
+				}).Execute(ctx)
+
+				if tc.fits {
+					if err != nil || calls != 1 {
+						t.Errorf("Execute() == %v after %d calls; wanted nil after 1", err, calls)
+					}
+					return
+				}
+
+				if !errors.Is(err, ErrScheduleTooLong) || calls != 0 {
+					t.Errorf("Execute() == %v after %d calls; wanted %v after none", err, calls, ErrScheduleTooLong)
+				}
+			})
+		})
+	}
+}
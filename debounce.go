@@ -0,0 +1,117 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"sync"
+)
+
+// Debouncer collapses bursts of triggers -- as in "something changed, please
+// reconcile X" -- into a single execution of a Rerun, such that redundant
+// triggers do not each cost a full retry loop. It suits reconcile-loop style
+// consumers where only the latest state matters.
+//
+// A trigger arriving while no execution is pending starts one, while a
+// trigger arriving before the next attempt of a pending execution begins is
+// satisfied by that attempt and simply coalesced. A trigger arriving during
+// an attempt causes a fresh execution to follow the current one, since the
+// attempt may have missed whatever prompted it. Since the warmup period of
+// the Rerun's Algorithm precedes each execution's first attempt, it serves
+// as the quiet period during which a burst of triggers is gathered.
+//
+// A Debouncer is safe for concurrent use and must be created by
+// NewDebouncer.
+type Debouncer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	r      *Rerun
+	done   func(error)
+
+	mu        sync.Mutex
+	active    bool // an execution is pending or underway
+	attempted bool // the current execution has made an attempt
+	dirty     bool // a trigger awaits an attempt
+	stopped   bool
+	wg        sync.WaitGroup
+}
+
+// NewDebouncer returns a new Debouncer executing r whenever it is triggered.
+// Each execution is given a Context derived from ctx, which is canceled by
+// Stop. If done is not nil, it is called with the result of each execution.
+func NewDebouncer(ctx context.Context, r *Rerun, done func(error)) *Debouncer {
+	d := &Debouncer{done: done}
+	d.ctx, d.cancel = context.WithCancel(ctx)
+
+	fn := r.function
+	d.r = r.WithContextFunction(func(ctx context.Context, i uint) error {
+		d.mu.Lock()
+		d.dirty, d.attempted = false, true
+		d.mu.Unlock()
+
+		if fn == nil {
+			return ErrNoFunction
+		}
+
+		return fn(ctx, i)
+	})
+
+	return d
+}
+
+// Trigger requests an execution of the receiver's Rerun and returns true if
+// a new execution was started or false if the request was coalesced into
+// one already pending (or the receiver has been stopped).
+func (d *Debouncer) Trigger() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopped {
+		return false
+	}
+
+	d.dirty = true
+
+	if d.active {
+		return false
+	}
+
+	d.active, d.attempted = true, false
+	d.wg.Add(1)
+	go d.run()
+
+	return true
+}
+
+// Stop cancels any pending execution, waits for it to return, and causes all
+// subsequent triggers to be ignored.
+func (d *Debouncer) Stop() {
+	d.mu.Lock()
+	d.stopped = true
+	d.mu.Unlock()
+
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Debouncer) run() {
+	defer d.wg.Done()
+
+	for {
+		err := d.r.Execute(d.ctx)
+		if d.done != nil {
+			d.done(err)
+		}
+
+		// n.b. If no attempt was made (e.g. the Rerun is invalid or Stop was
+		// called during warmup) running again would only fail the same way.
+		d.mu.Lock()
+		if !d.dirty || !d.attempted || d.stopped {
+			d.active = false
+			d.mu.Unlock()
+			return
+		}
+		d.attempted = false
+		d.mu.Unlock()
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls, results atomic.Int32
+
+		r := New(3).
+			WithAlgorithm(Fixed{Delay: time.Second, Start: time.Second}).
+			WithFunction(func(uint) error {
+				calls.Add(1)
+				time.Sleep(time.Second)
+				return nil
+			})
+
+		d := NewDebouncer(context.Background(), r, func(err error) {
+			if err != nil {
+				t.Errorf("execution returned %v", err)
+			}
+			results.Add(1)
+		})
+		defer d.Stop()
+
+		// A burst of triggers during warmup is coalesced.
+		if !d.Trigger() {
+			t.Error("first Trigger() returned false; wanted true")
+		}
+		for range 5 {
+			time.Sleep(100 * time.Millisecond)
+			if d.Trigger() {
+				t.Error("Trigger() during warmup returned true; wanted false")
+			}
+		}
+
+		synctest.Wait()
+		time.Sleep(time.Minute)
+		if c, r := calls.Load(), results.Load(); c != 1 || r != 1 {
+			t.Fatalf("%d calls and %d results; wanted 1 and 1", c, r)
+		}
+
+		// A trigger during an attempt causes another execution.
+		d.Trigger()
+		time.Sleep(1500 * time.Millisecond)
+		d.Trigger()
+
+		time.Sleep(time.Minute)
+		if c, r := calls.Load(), results.Load(); c != 3 || r != 3 {
+			t.Fatalf("%d calls and %d results; wanted 3 and 3", c, r)
+		}
+
+		d.Stop()
+		if d.Trigger() {
+			t.Error("Trigger() after Stop() returned true; wanted false")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reason explains a Decision made by Execute.
+type Reason string
+
+// These are the Reasons given for each Decision made by Execute. Note that
+// ReasonPanic may explain either a retry (see WithRetryOnPanic) or giving
+// up.
+const (
+	// Reasons for a retry.
+	ReasonRetrySignal    Reason = "retry signal"     // the Func returned ErrDoRetry
+	ReasonRetryAfter     Reason = "retry-after hint" // the Func returned a RetryAfter error
+	ReasonProgress       Reason = "progress"         // the Func returned a Progress error
+	ReasonRetryable      Reason = "retryable error"  // see WithRetryIf
+	ReasonAttemptTimeout Reason = "attempt timeout"  // see WithAttemptTimeout
+	ReasonPanic          Reason = "panic"            // the Func panicked
+
+	// Reasons for returning.
+	ReasonSuccess      Reason = "success" // the Func returned nil
+	ReasonNonRetryable Reason = "non-retryable error"
+	ReasonPermanent    Reason = "permanent error" // the Func returned a Permanent error
+	ReasonExhausted    Reason = "attempts exhausted"
+	ReasonBudget       Reason = "budget exhausted" // see WithBudget, WithCost and WithMaxElapsed
+	ReasonWaitError    Reason = "wait error"       // no valid waiting period was available
+	ReasonContextDone  Reason = "context done"
+)
+
+// Decision explains a single decision made by Execute following an attempt:
+// either to retry or to return. Decisions are reported to the OnDecision
+// hook (see Hooks) to provide an audit trail of an execution, which makes
+// complex, layered policies much easier to debug.
+type Decision struct {
+	// Retry is true if another attempt is to be made; otherwise, Execute is
+	// about to return Err.
+	Retry bool
+
+	// Iteration is the iteration number of the most recent attempt.
+	Iteration uint
+
+	// Reason explains the decision.
+	Reason Reason
+
+	// Err is the error returned by the most recent attempt for a retry, or
+	// the error Execute is about to return.
+	Err error
+
+	// Wait is the waiting period preceding the retry (before any time
+	// scaling).
+	Wait time.Duration
+
+	// Source identifies where Wait came from: "algorithm" if it was
+	// calculated by the Rerun's Algorithm or "retry-after" if it was
+	// requested using RetryAfter.
+	Source string
+}
+
+// String returns a concise, human-readable description of the receiver,
+// such as "retrying: reason=retry signal, wait=1.2s source=algorithm" or
+// "giving up: budget exhausted".
+func (d Decision) String() string {
+	var sb strings.Builder
+
+	switch {
+	case d.Retry:
+		fmt.Fprintf(&sb, "retrying: reason=%s, wait=%v source=%s", d.Reason, d.Wait, d.Source)
+	case d.Reason == ReasonSuccess:
+		sb.WriteString("succeeded")
+	default:
+		fmt.Fprintf(&sb, "giving up: %s", d.Reason)
+	}
+
+	if d.Err != nil {
+		fmt.Fprintf(&sb, " (%v)", d.Err)
+	}
+
+	return sb.String()
+}
+
+func (h Hooks) decide(ctx context.Context, d Decision) {
+	if h.OnDecision != nil {
+		h.OnDecision(ctx, d)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DecorrelatedJitter is a stateful Algorithm implementing the "decorrelated
+// jitter" strategy popularized by the AWS Architecture Blog: each waiting
+// period is chosen uniformly from the range Base to 3 times the previous
+// waiting period (with Base taking the place of the previous wait before the
+// first retry), then capped at Max. This grows waits roughly exponentially
+// while keeping clients sharing the same configuration well spread.
+//
+// Since each wait depends upon the one before it, DecorrelatedJitter
+// implements Resetter: Execute obtains a fresh instance, holding its own
+// state, for each execution. An instance not obtained from Reset (such as a
+// DecorrelatedJitter literal) holds no state and is safe for concurrent use;
+// each call to its Wait method draws a new chain of waits up to the given
+// iteration.
+type DecorrelatedJitter struct {
+	// Start defines the warmup time Rerun uses before its first call to a Func.
+	// A negative value will cause the OK method to return ErrNegativeDuration.
+	Start time.Duration
+
+	// Base is the smallest waiting period. It must be positive; otherwise,
+	// the OK method returns ErrZeroValue or ErrNegativeDuration.
+	Base time.Duration
+
+	// Max is the upper bound of each waiting period. It cannot be less than
+	// Base; otherwise, the OK method returns ErrConflictingFields.
+	Max time.Duration
+
+	// Rand is the source of randomness. If nil, the top-level functions from
+	// math/rand/v2 are used.
+	Rand Random
+
+	state *decorrelatedState
+}
+
+// decorrelatedState holds the wait most recently returned for an iteration.
+type decorrelatedState struct {
+	n    uint
+	wait time.Duration
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the Algorithm interface.
+func (dj DecorrelatedJitter) OK(uint) error {
+	var errs []error
+
+	if dj.Start < 0 {
+		errs = append(errs, fieldError("DecorrelatedJitter.Start", dj.Start, ErrNegativeDuration))
+	}
+
+	switch {
+	case dj.Base < 0:
+		errs = append(errs, fieldError("DecorrelatedJitter.Base", dj.Base, ErrNegativeDuration))
+	case dj.Base == 0:
+		errs = append(errs, fieldError("DecorrelatedJitter.Base", dj.Base, ErrZeroValue))
+	}
+
+	if dj.Max < dj.Base {
+		errs = append(errs, fieldError("DecorrelatedJitter.Max", dj.Max, ErrConflictingFields))
+	}
+
+	return joinErrors(errs...)
+}
+
+// Warmup returns the value of the receiver's Start field in order to satisfy
+// the Algorithm interface.
+func (dj DecorrelatedJitter) Warmup() time.Duration {
+	return dj.Start
+}
+
+// Wait returns the waiting period before retry n. For an instance obtained
+// from Reset, this is derived from the wait it last returned if that was for
+// iteration n-1, which is always the case when called by Execute; otherwise,
+// a new chain of waits is drawn, beginning at iteration 1.
+// Wait is part of the Algorithm interface.
+func (dj DecorrelatedJitter) Wait(n uint) time.Duration {
+	if n == 0 {
+		return 0
+	}
+
+	var s decorrelatedState
+	if dj.state != nil && dj.state.n < n {
+		s = *dj.state
+	}
+
+	for s.n < n {
+		prev := s.wait
+		if s.n == 0 {
+			prev = dj.Base
+		}
+		s.n, s.wait = s.n+1, dj.next(prev)
+	}
+
+	if dj.state != nil {
+		*dj.state = s
+	}
+
+	return s.wait
+}
+
+// next returns a wait chosen uniformly from Base to 3·prev, capped at Max.
+func (dj DecorrelatedJitter) next(prev time.Duration) time.Duration {
+	lo, hi := float64(dj.Base), 3*float64(prev)
+	d := lo + (hi-lo)*RandomFloat64(dj.Rand)
+	return time.Duration(math.Min(d, float64(dj.Max)))
+}
+
+// Reset returns a copy of the receiver holding new, empty state for use by a
+// single execution.
+// This method implements the Resetter interface.
+func (dj DecorrelatedJitter) Reset() Algorithm {
+	dj.state = new(decorrelatedState)
+	return dj
+}
+
+func (dj DecorrelatedJitter) String() string {
+	return fmt.Sprintf("decorrelated jitter %v–%v%s", dj.Base, dj.Max, warmupString(dj.Start))
+}
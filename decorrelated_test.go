@@ -0,0 +1,78 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDecorrelatedJitter(t *testing.T) {
+	dj := DecorrelatedJitter{Base: 100 * time.Millisecond, Max: 10 * time.Second, Rand: NewRandom(7)}
+
+	if err := CheckAlgorithm(dj, 50); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each wait of a reset instance lies between Base and 3 times the
+	// previous wait, capped at Max.
+	for range 100 {
+		algo := dj.Reset()
+		prev := dj.Base
+		for n := uint(1); n < 30; n++ {
+			d := algo.Wait(n)
+			if d < dj.Base || d > min(3*prev, dj.Max) {
+				t.Fatalf("Wait(%d) == %v following %v", n, d, prev)
+			}
+			prev = d
+		}
+	}
+
+	bad := DecorrelatedJitter{Base: time.Second, Max: time.Millisecond}
+	if err := bad.OK(2); !errors.Is(err, ErrConflictingFields) {
+		t.Errorf("OK() == %v; wanted %v", err, ErrConflictingFields)
+	}
+
+	if err := (DecorrelatedJitter{Max: time.Second}).OK(2); !errors.Is(err, ErrZeroValue) {
+		t.Errorf("OK() == %v; wanted %v", err, ErrZeroValue)
+	}
+}
+
+// resetCounter is a stateful Algorithm counting calls to Wait since it was
+// last reset.
+type resetCounter struct {
+	FixedDelay
+	calls *int
+}
+
+func (rc resetCounter) Wait(n uint) time.Duration {
+	*rc.calls++
+	return time.Duration(*rc.calls)
+}
+
+func (rc resetCounter) Reset() Algorithm {
+	return resetCounter{calls: new(int)}
+}
+
+func TestExecuteResetter(t *testing.T) {
+	var waits []time.Duration
+
+	r := New(3).
+		WithAlgorithm(WithJitter(resetCounter{calls: new(int)}, ProportionalJitter(0))).
+		WithNotify(Hooks{OnDecision: func(_ context.Context, d Decision) {
+			if d.Retry {
+				waits = append(waits, d.Wait)
+			}
+		}}).
+		WithFunction(func(uint) error { return ErrDoRetry })
+
+	r.Execute(context.Background())
+	r.Execute(context.Background())
+
+	if want := []time.Duration{1, 2, 1, 2}; !slices.Equal(waits, want) {
+		t.Errorf("waits == %v; wanted %v", waits, want)
+	}
+}
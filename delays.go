@@ -0,0 +1,91 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"iter"
+	"time"
+)
+
+// Delays returns an iterator over the schedule algo defines for n iterations
+// (or an unlimited number, given Forever), yielding each iteration number
+// paired with the waiting period preceding it: the warmup period for
+// iteration 0 and then Wait(i) for each retry i. A stateful algo (see
+// Resetter) is reset each time the sequence is iterated, just as Execute
+// resets it for each execution. No validation is performed; see
+// Algorithm.OK.
+func Delays(algo Algorithm, n uint) iter.Seq2[uint, time.Duration] {
+	return func(yield func(uint, time.Duration) bool) {
+		if algo == nil {
+			return
+		}
+
+		a := reset(algo)
+		for i := uint(0); i < n || n == Forever; i++ {
+			d := a.Warmup()
+			if i > 0 {
+				d = a.Wait(i)
+			}
+
+			if !yield(i, d) {
+				return
+			}
+		}
+	}
+}
+
+// Delays returns an iterator over the schedule Execute would follow for the
+// receiver, yielding each iteration number paired with the time Execute
+// would pause before making that attempt: the warmup period for iteration 0
+// and the waiting period for each retry thereafter, as scaled by any time
+// scale factor (see WithTimeScale) or zero under WithoutSleep. Waiting
+// periods are taken from the receiver's precomputed schedule if it has one
+// (see Waits). Like Execute, an unlimited Rerun (see Forever) yields an
+// unlimited sequence, while requests made using RetryAfter and any warmup
+// skipped by WithColdStartWarmup cannot be foreseen.
+func (r Rerun) Delays() iter.Seq2[uint, time.Duration] {
+	return func(yield func(uint, time.Duration) bool) {
+		for i, d := range Delays(r.algorithm, r.iterations) {
+			if i > 0 && int(i) <= len(r.waits) {
+				d = r.waits[i-1]
+			}
+
+			if !yield(i, r.delay(d)) {
+				return
+			}
+		}
+	}
+}
+
+// Schedule returns the complete schedule the receiver would follow, without
+// executing anything: the warmup period followed by the waiting period
+// preceding each retry, each as described for Delays. Since it validates the
+// receiver (see Err) and every waiting period, Schedule suits logging a
+// policy at startup or checking configurations in CI. Each call yields a
+// fresh sample from a randomized Algorithm.
+//
+// ErrTooFewIterations is returned for fewer than 2 iterations while
+// ErrUnlimited is returned for an unlimited Rerun (see Forever), whose
+// schedule has no end.
+func (r Rerun) Schedule() ([]time.Duration, error) {
+	switch {
+	case r.iterations < 2:
+		return nil, ErrTooFewIterations
+	case r.unlimited():
+		return nil, ErrUnlimited
+	}
+
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	sched := make([]time.Duration, 0, min(r.iterations, maxPrecomputed))
+	for i, d := range r.Delays() {
+		if d < 0 {
+			return nil, waitError(i, d)
+		}
+		sched = append(sched, d)
+	}
+
+	return sched, nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDelays(t *testing.T) {
+	algo := Fixed{Start: time.Second, Delay: 2 * time.Second}
+
+	var got []time.Duration
+	for i, d := range Delays(algo, 4) {
+		if int(i) != len(got) {
+			t.Fatalf("yielded iteration %d; wanted %d", i, len(got))
+		}
+		got = append(got, d)
+	}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second}
+	if !slices.Equal(got, want) {
+		t.Errorf("Delays() yielded %v; wanted %v", got, want)
+	}
+
+	// An unlimited schedule stops when asked.
+	var n int
+	for range Delays(algo, Forever) {
+		if n++; n == 100 {
+			break
+		}
+	}
+	if n != 100 {
+		t.Errorf("Delays(Forever) yielded %d values; wanted 100", n)
+	}
+
+	for range Delays(nil, 3) {
+		t.Error("Delays(nil) yielded a value")
+	}
+}
+
+func TestRerunDelays(t *testing.T) {
+	r := New(3).WithAlgorithm(LinearDelay{Start: time.Second, Base: time.Second, SlopeDuration: time.Second}).WithTimeScale(0.5)
+
+	var got []time.Duration
+	for _, d := range r.Delays() {
+		got = append(got, d)
+	}
+
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, time.Second}
+	if !slices.Equal(got, want) {
+		t.Errorf("Delays() yielded %v; wanted %v", got, want)
+	}
+
+	for _, d := range New(3).WithoutSleep().Delays() {
+		if d != 0 {
+			t.Errorf("Delays() yielded %v under WithoutSleep; wanted 0", d)
+		}
+	}
+}
+
+func TestRerunSchedule(t *testing.T) {
+	r := New(4).WithAlgorithm(Fixed{Start: time.Second, Delay: 2 * time.Second})
+
+	got, err := r.Schedule()
+	want := []time.Duration{time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("Schedule() == %v, %v; wanted %v, <nil>", got, err, want)
+	}
+
+	tests := []struct {
+		r    *Rerun
+		want error
+	}{
+		{New(1), ErrTooFewIterations},
+		{New(Forever), ErrUnlimited},
+		{New(3).WithAlgorithm(FixedDelay(-time.Second)), ErrNegativeDuration},
+		{New(3).WithAlgorithm(nil), ErrNilAlgorithm},
+	}
+
+	for _, tc := range tests {
+		if got, err := tc.r.Schedule(); got != nil || !errors.Is(err, tc.want) {
+			t.Errorf("%v: Schedule() == %v, %v; wanted <nil>, %v", tc.r, got, err, tc.want)
+		}
+	}
+}
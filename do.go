@@ -0,0 +1,76 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"sync"
+)
+
+// Do executes fn using the given Rerun and returns the value produced by its
+// successful attempt, sparing the caller from capturing that value in a
+// closure. Aside from its Func, r is used as configured and Do's retry,
+// panic and Context handling are exactly those of Execute; any Func already
+// associated with r is disregarded.
+//
+// If Execute returns an error, Do returns that error along with the zero
+// value of T -- even if fn returned a value of its own alongside that error.
+func Do[T any](ctx context.Context, r *Rerun, fn func(uint) (T, error)) (T, error) {
+	var v T
+
+	if fn == nil {
+		return v, ErrNoFunction
+	}
+
+	return DoContext(ctx, r, func(_ context.Context, i uint) (T, error) {
+		return fn(i)
+	})
+}
+
+// DoContext is like Do except that fn is also passed a Context derived from
+// ctx, as is a ContextFunc (see WithContextFunction), through which it may
+// observe cancellation, deadlines and the current Attempt.
+func DoContext[T any](ctx context.Context, r *Rerun, fn func(context.Context, uint) (T, error)) (T, error) {
+	var (
+		mu       sync.Mutex
+		v        T
+		calls    uint // the number of calls made to fn
+		returned bool
+	)
+
+	if fn == nil {
+		return v, ErrNoFunction
+	}
+
+	// n.b. A call abandoned by Execute (see WithAbandonHungAttempts) may yet
+	// succeed after a later call has begun, or after DoContext has returned,
+	// and so must not overwrite v. Only the latest call can be the one
+	// whose success Execute reports.
+	err := r.WithContextFunction(func(ctx context.Context, i uint) error {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+
+		res, err := fn(ctx, i)
+		if err == nil {
+			mu.Lock()
+			if call == calls && !returned {
+				v = res
+			}
+			mu.Unlock()
+		}
+		return err
+	}).Execute(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	returned = true
+
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
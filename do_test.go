@@ -0,0 +1,83 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		r := New(3)
+
+		v, err := Do(context.Background(), r, func(i uint) (uint, error) {
+			if i < 2 {
+				return i, ErrDoRetry
+			}
+			return i * 10, nil
+		})
+		if v != 20 || err != nil {
+			t.Errorf("Do() == (%d, %v); wanted (20, <nil>)", v, err)
+		}
+
+		s, err := Do(context.Background(), r, func(uint) (string, error) {
+			return "partial", ErrDoRetry
+		})
+		if s != "" || !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Do() == (%q, %v); wanted (\"\", %v)", s, err, ErrAttemptsExhausted)
+		}
+
+		boom := errors.New("boom")
+		p, err := Do(context.Background(), r, func(uint) (*int, error) {
+			return new(int), boom
+		})
+		if p != nil || err != boom {
+			t.Errorf("Do() == (%v, %v); wanted (<nil>, %v)", p, err, boom)
+		}
+
+		var pe *PanicError
+		if _, err := Do(context.Background(), r, func(uint) (int, error) { panic("oops") }); !errors.As(err, &pe) {
+			t.Errorf("Do() returned %v; wanted a *PanicError", err)
+		}
+
+		if _, err := Do[int](context.Background(), r, nil); err != ErrNoFunction {
+			t.Errorf("Do(nil) returned %v; wanted %v", err, ErrNoFunction)
+		}
+	})
+}
+
+func TestDoContext(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		r := New(3).WithAttemptTimeout(time.Second)
+
+		v, err := DoContext(context.Background(), r, func(ctx context.Context, i uint) (time.Duration, error) {
+			if a, ok := AttemptFromContext(ctx); !ok || a.Iteration != i {
+				t.Errorf("AttemptFromContext() == (%+v, %t); wanted iteration %d", a, ok, i)
+			}
+
+			if i == 0 {
+				<-ctx.Done() // exceed the attempt timeout
+				return 0, ctx.Err()
+			}
+
+			dl, _ := ctx.Deadline()
+			return time.Until(dl), nil
+		})
+		if v != time.Second || err != nil {
+			t.Errorf("DoContext() == (%v, %v); wanted (%v, <nil>)", v, err, time.Second)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := DoContext(ctx, r, func(context.Context, uint) (int, error) { return 1, nil }); err != context.Canceled {
+			t.Errorf("DoContext() returned %v; wanted %v", err, context.Canceled)
+		}
+	})
+}
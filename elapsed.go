@@ -0,0 +1,68 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithMaxElapsed returns a pointer to its receiver after limiting the total
+// wall-clock time -- attempts and waiting periods alike -- that Execute may
+// spend on a single execution to d, measured from the moment Execute is
+// called. Before each retry, Execute gives up (returning an *ElapsedError)
+// rather than impose a waiting period that would carry it beyond d. This
+// limit is independent of both the receiver's iterations and any deadline
+// carried by the Context given to Execute; whichever is reached first ends
+// the execution.
+//
+// A zero d disables the limit while a negative d causes subsequent calls to
+// the receiver's Err method to return an error wrapping ErrNegativeDuration.
+// Note that the limit is only consulted between attempts; an attempt already
+// underway is never interrupted (but see WithAttemptTimeout).
+func (r Rerun) WithMaxElapsed(d time.Duration) *Rerun {
+	r.maxElapsed = d
+	return &r
+}
+
+// checkElapsed returns an *ElapsedError if the retry described by a,
+// preceded by the given waiting period, would carry an execution having
+// already spent elapsed beyond the receiver's limit (see WithMaxElapsed).
+func (r Rerun) checkElapsed(a Attempt, elapsed, wait time.Duration) error {
+	if r.maxElapsed <= 0 || elapsed+r.delay(wait) <= r.maxElapsed {
+		return nil
+	}
+	return &ElapsedError{Iteration: a.Iteration, Elapsed: elapsed, Wait: wait, Limit: r.maxElapsed, Err: lastCause(a.Last)}
+}
+
+// ElapsedError is returned by Execute when a retry is refused because it
+// would exceed the limit on total elapsed time given to WithMaxElapsed.
+// ElapsedError wraps both ErrBudgetExhausted and the error returned by the
+// final attempt so either may be detected using errors.Is as well as
+// errors.As.
+type ElapsedError struct {
+	// Iteration is the iteration number of the refused retry.
+	Iteration uint
+
+	// Elapsed is the time spent by the execution before the retry was
+	// refused.
+	Elapsed time.Duration
+
+	// Wait is the waiting period that would have preceded the retry.
+	Wait time.Duration
+
+	// Limit is the limit given to WithMaxElapsed.
+	Limit time.Duration
+
+	// Err is the error returned by the final attempt, or nil if that was a
+	// bare ErrDoRetry.
+	Err error
+}
+
+func (e *ElapsedError) Error() string {
+	return fmt.Sprintf("%v: retry %d after %v would exceed limit of %v (%v elapsed)%s", ErrBudgetExhausted, e.Iteration, e.Wait, e.Limit, e.Elapsed, lastSuffix(e.Err))
+}
+
+func (e *ElapsedError) Unwrap() []error {
+	return []error{ErrBudgetExhausted, e.Err}
+}
@@ -0,0 +1,52 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestWithMaxElapsed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls uint
+		r := New(Forever).WithAlgorithm(Fixed1s).WithMaxElapsed(10 * time.Second).WithFunction(func(uint) error {
+			calls++
+			return ErrDoRetry
+		})
+
+		start := time.Now()
+		err := r.Execute(context.Background())
+
+		want := &ElapsedError{Iteration: 11, Elapsed: 10 * time.Second, Wait: time.Second, Limit: 10 * time.Second}
+		var ee *ElapsedError
+		if !errors.As(err, &ee) || *ee != *want {
+			t.Errorf("Execute() == %v; wanted %v", err, want)
+		}
+
+		if !errors.Is(err, ErrBudgetExhausted) {
+			t.Errorf("errors.Is(%v, ErrBudgetExhausted) == false", err)
+		}
+
+		if errors.Is(err, ErrDoRetry) {
+			t.Errorf("errors.Is(%v, ErrDoRetry) == true; wanted false", err)
+		}
+
+		if calls != 11 {
+			t.Errorf("Func called %d times; wanted 11", calls)
+		}
+
+		if d := time.Since(start); d != 10*time.Second {
+			t.Errorf("Execute took %v; wanted %v", d, 10*time.Second)
+		}
+
+		if err := New(3).WithMaxElapsed(-time.Second).Err(); !errors.Is(err, ErrNegativeDuration) {
+			t.Errorf("Err() == %v; wanted %v", err, ErrNegativeDuration)
+		}
+	})
+}
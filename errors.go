@@ -2,18 +2,209 @@
 
 package rerun
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
 const (
+	ErrAlgorithmContract = Error("algorithm contract violated")
 	ErrAttemptsExhausted = Error("all attempts exhausted")
+	ErrBudgetExhausted   = Error("retry budget exhausted")
+	ErrConflictingFields = Error("conflicting fields")
 	ErrDoRetry           = Error("retry attempt")
+	ErrEmptySchedule     = Error("empty schedule")
+	ErrInvalidJitter     = Error("invalid jitter")
+	ErrInvalidMultiplier = Error("invalid multiplier")
+	ErrInvalidShape      = Error("invalid shape")
+	ErrInvalidSmoothing  = Error("invalid hint smoothing")
+	ErrInvalidUnits      = Error("invalid delay units")
 	ErrNegativeDuration  = Error("negative duration")
+	ErrNestedExecute     = Error("nested execution disallowed")
 	ErrNilAlgorithm      = Error("nil algorithm")
 	ErrNoFunction        = Error("no function defined")
 	ErrNoLogBase         = Error("no log base specified")
+	ErrScheduleTooLong   = Error("schedule exceeds context deadline")
+	ErrTerminated        = Error("terminated by signal")
 	ErrTooFewIterations  = Error("too few iterations")
+	ErrTooFewSamples     = Error("too few samples")
+	ErrUnlimited         = Error("unlimited iterations")
+	ErrZeroValue         = Error("value cannot be zero")
 )
 
+// Error is the type of this package's sentinel errors. Since a sentinel is a
+// constant it cannot carry any context of its own; use its Wrap method to
+// associate it with an underlying cause.
 type Error string
 
 func (e Error) Error() string {
 	return string(e)
 }
+
+// Wrap returns an error that wraps both the receiver and cause such that
+// errors.Is and errors.As will match either of them. If cause is nil, the
+// receiver is returned unaltered.
+func (e Error) Wrap(cause error) error {
+	return wrapError(e, cause)
+}
+
+// wrapError returns an error pairing sentinel with the underlying cause.
+func wrapError(sentinel Error, cause error) error {
+	if cause == nil {
+		return sentinel
+	}
+	return &wrappedError{sentinel: sentinel, cause: cause}
+}
+
+// wrappedError pairs one of the package's sentinel errors with an underlying
+// cause.
+type wrappedError struct {
+	sentinel Error
+	cause    error
+}
+
+func (e *wrappedError) Error() string {
+	return e.sentinel.Error() + ": " + e.cause.Error()
+}
+
+func (e *wrappedError) Unwrap() []error {
+	return []error{e.sentinel, e.cause}
+}
+
+// ValidationError is returned by the OK methods of this package's Algorithms
+// (and other configuration types) to describe exactly which setting is at
+// fault. It wraps one of the package's sentinel errors (most often
+// ErrNegativeDuration) so that it still satisfies errors.Is against it.
+type ValidationError struct {
+	// Field names the offending setting, qualified by its type name (e.g.
+	// "LinearDelay.Start"). It is empty if the problem was found in a
+	// calculated wait period rather than a single field.
+	Field string
+
+	// Iteration is the iteration number for which an invalid wait period
+	// was calculated. It is zero if Field is set.
+	Iteration uint
+
+	// Value is the offending field's value, or the invalid wait period
+	// calculated for Iteration.
+	Value any
+
+	// Err is the underlying sentinel error.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("invalid %s (%v): %v", e.Field, e.Value, e.Err)
+	}
+	return fmt.Sprintf("invalid wait for iteration %d (%v): %v", e.Iteration, e.Value, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// fieldError returns a *ValidationError describing a problem with the named
+// field.
+func fieldError(field string, value any, err error) error {
+	return &ValidationError{Field: field, Value: value, Err: err}
+}
+
+// waitError returns a *ValidationError describing the negative wait period d
+// calculated for iteration n.
+func waitError(n uint, d time.Duration) error {
+	return &ValidationError{Iteration: n, Value: d, Err: ErrNegativeDuration}
+}
+
+// joinErrors returns nil if all errs are nil, the sole non-nil error if there
+// is only one, or all non-nil errors joined using errors.Join otherwise.
+func joinErrors(errs ...error) error {
+	var (
+		first error
+		count int
+	)
+
+	for _, err := range errs {
+		if err != nil {
+			if count++; count == 1 {
+				first = err
+			}
+		}
+	}
+
+	if count > 1 {
+		return errors.Join(errs...)
+	}
+
+	return first
+}
+
+// IsExhausted reports whether err indicates that Execute gave up because it
+// ran out of attempts (ErrAttemptsExhausted) or of retry budget
+// (ErrBudgetExhausted).
+func IsExhausted(err error) bool {
+	return errors.Is(err, ErrAttemptsExhausted) || errors.Is(err, ErrBudgetExhausted)
+}
+
+// IsRetrySignal reports whether err asks Execute for a retry; that is, if it
+// is (or wraps) ErrDoRetry or an error created by RetryAfter -- and is not
+// marked as permanent (see Permanent) or returned by Execute upon giving up
+// (see IsExhausted), which wraps the error of the final attempt.
+func IsRetrySignal(err error) bool {
+	return errors.Is(err, ErrDoRetry) && !IsPermanent(err) && !IsExhausted(err)
+}
+
+// IsPermanent reports whether err is (or wraps) an error created by
+// Permanent.
+func IsPermanent(err error) bool {
+	var pe *PermanentError
+	return errors.As(err, &pe)
+}
+
+// IsConfigError reports whether err was caused by an invalid configuration
+// -- such as an invalid Algorithm, missing Func or too few iterations -- as
+// opposed to a failure arising while executing.
+func IsConfigError(err error) bool {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return true
+	}
+
+	for _, target := range []error{
+		ErrAlgorithmContract,
+		ErrConflictingFields,
+		ErrEmptySchedule,
+		ErrInvalidJitter,
+		ErrInvalidMultiplier,
+		ErrInvalidShape,
+		ErrInvalidSmoothing,
+		ErrInvalidUnits,
+		ErrNegativeDuration,
+		ErrNilAlgorithm,
+		ErrNoFunction,
+		ErrNoLogBase,
+		ErrTooFewIterations,
+		ErrZeroValue,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsGaveUpOnContext reports whether err indicates that Execute gave up
+// because its Context was canceled, its deadline was exceeded or it was
+// interrupted by a signal (see NotifyContext). This includes a *TimedOutError
+// but, since Execute returns context.Cause(ctx), not an arbitrary cause given
+// to a context.CancelCauseFunc.
+func IsGaveUpOnContext(err error) bool {
+	var te *TimedOutError
+	return errors.As(err, &te) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, ErrTerminated)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidationError(t *testing.T) {
+	for _, tc := range []struct {
+		algo Algorithm
+		n    uint
+		want ValidationError
+	}{
+		{
+			LinearDelay{Start: -time.Second},
+			5,
+			ValidationError{Field: "LinearDelay.Start", Value: -time.Second, Err: ErrNegativeDuration},
+		},
+		{
+			LinearDelay{Base: 100 * time.Millisecond, Slope: float64(-25 * time.Millisecond)},
+			7,
+			ValidationError{Iteration: 6, Value: -25 * time.Millisecond, Err: ErrNegativeDuration},
+		},
+		{
+			ExponentialDelay{Base: time.Second},
+			3,
+			ValidationError{Field: "ExponentialDelay.Multiplier", Value: float64(0), Err: ErrInvalidMultiplier},
+		},
+	} {
+		err := tc.algo.OK(tc.n)
+
+		if !errors.Is(err, tc.want.Err) {
+			t.Errorf("%#v.OK(%d) == %v; wanted error wrapping %v", tc.algo, tc.n, err, tc.want.Err)
+		}
+
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Errorf("%#v.OK(%d) == %v; wanted a *ValidationError", tc.algo, tc.n, err)
+			continue
+		}
+
+		if ve.Field != tc.want.Field || ve.Iteration != tc.want.Iteration || ve.Value != tc.want.Value {
+			t.Errorf("%#v.OK(%d) == %+v; wanted %+v", tc.algo, tc.n, *ve, tc.want)
+		}
+	}
+}
+
+func TestValidationErrorsJoined(t *testing.T) {
+	for _, tc := range []struct {
+		algo Algorithm
+		want []string
+	}{
+		{
+			LinearDelay{Start: -time.Second, Base: 100 * time.Millisecond, Slope: float64(-25 * time.Millisecond)},
+			[]string{"LinearDelay.Start", ""},
+		},
+		{
+			LogarithmicDelay{Start: -time.Second, Units: Millisecond, Coefficient: 1},
+			[]string{"LogarithmicDelay.Start", "LogarithmicDelay.Amplifier"},
+		},
+		{
+			ExponentialDelay{Start: -time.Second, Base: -time.Second, Max: -time.Second},
+			[]string{"ExponentialDelay.Start", "ExponentialDelay.Base", "ExponentialDelay.Max", "ExponentialDelay.Multiplier"},
+		},
+	} {
+		err := tc.algo.OK(10)
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Errorf("%#v.OK(10) == %v; wanted joined errors", tc.algo, err)
+			continue
+		}
+
+		var got []string
+		for _, e := range joined.Unwrap() {
+			var ve *ValidationError
+			if errors.As(e, &ve) {
+				got = append(got, ve.Field)
+			}
+		}
+
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%#v.OK(10) reported problems with %q; wanted %q", tc.algo, got, tc.want)
+		}
+	}
+}
+
+func TestErrorWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := ErrAttemptsExhausted.Wrap(cause)
+
+	if !errors.Is(err, ErrAttemptsExhausted) || !errors.Is(err, cause) {
+		t.Errorf("errors.Is(%v, ...) failed to match both the sentinel and its cause", err)
+	}
+
+	if got, want := err.Error(), "all attempts exhausted: connection refused"; got != want {
+		t.Errorf("Error() == %q; wanted %q", got, want)
+	}
+
+	if err := ErrDoRetry.Wrap(nil); err != ErrDoRetry {
+		t.Errorf("ErrDoRetry.Wrap(nil) == %#v; wanted ErrDoRetry", err)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	errOther := errors.New("other")
+
+	for _, tc := range []struct {
+		err                                 error
+		exhausted, retry, config, onContext bool
+	}{
+		{nil, false, false, false, false},
+		{errOther, false, false, false, false},
+		{ErrAttemptsExhausted, true, false, false, false},
+		{fmt.Errorf("giving up: %w", ErrAttemptsExhausted), true, false, false, false},
+		{fmt.Errorf("giving up: %w", ErrBudgetExhausted), true, false, false, false},
+		{ErrDoRetry.Wrap(errOther), false, true, false, false},
+		{RetryAfter(time.Second), false, true, false, false},
+		{Permanent(ErrDoRetry.Wrap(errOther)), false, false, false, false},
+		{New(1).WithAlgorithm(FixedDelay(-1)).Err(), false, false, true, false},
+		{ErrNoFunction, false, false, true, false},
+		{context.Canceled, false, false, false, true},
+		{&TimedOutError{Cause: errOther}, false, false, false, true},
+		{&TimedOutError{Cause: errOther, Last: RetryAfterCause(time.Second, ErrDoRetry.Wrap(errOther))}, false, false, false, true},
+		{&SignalError{Signal: os.Interrupt}, false, false, false, true},
+	} {
+		if got := IsExhausted(tc.err); got != tc.exhausted {
+			t.Errorf("IsExhausted(%v) == %v; wanted %v", tc.err, got, tc.exhausted)
+		}
+
+		if got := IsRetrySignal(tc.err); got != tc.retry {
+			t.Errorf("IsRetrySignal(%v) == %v; wanted %v", tc.err, got, tc.retry)
+		}
+
+		if got := IsConfigError(tc.err); got != tc.config {
+			t.Errorf("IsConfigError(%v) == %v; wanted %v", tc.err, got, tc.config)
+		}
+
+		if got := IsGaveUpOnContext(tc.err); got != tc.onContext {
+			t.Errorf("IsGaveUpOnContext(%v) == %v; wanted %v", tc.err, got, tc.onContext)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// execution holds the state of a single call to Execute that is reachable
+// through the Context passed to its Func and hooks.
+type execution struct {
+	// id uniquely identifies the execution (see ExecutionIDFromContext).
+	id string
+
+	// name is the name of the executing Rerun (see WithName).
+	name string
+
+	// combined is the product of the iterations of this execution and all
+	// of those enclosing it (see NestingPolicy).
+	combined uint
+
+	scratch scratchpad
+
+	// release, if not nil, releases the reservation made with a Coordinator
+	// for the retry being scheduled. It is used only by the goroutine
+	// running Execute.
+	release func()
+
+	// registered reports whether the execution was recorded for
+	// ActiveRuns (see register).
+	registered bool
+
+	mu   sync.Mutex
+	info RunInfo // see ActiveRuns
+}
+
+type executionKey struct{}
+
+// withExecution returns a Context derived from ctx carrying the state for a
+// new execution of the receiver, started at the given time, along with that
+// state.
+func (r Rerun) withExecution(ctx context.Context, started time.Time) (context.Context, *execution) {
+	combined := r.iterations
+	if outer := executionFrom(ctx); outer != nil {
+		if r.iterations > math.MaxUint/outer.combined {
+			combined = math.MaxUint
+		} else {
+			combined *= outer.combined
+		}
+	}
+
+	id := newExecutionID()
+	e := &execution{
+		id:       id,
+		name:     r.name,
+		combined: combined,
+		info:     RunInfo{ID: id, Name: r.name, Iterations: r.iterations, Started: started},
+	}
+
+	return context.WithValue(ctx, executionKey{}, e), e
+}
+
+// reserved records release as the means of releasing a reservation made for
+// the retry being scheduled, should it be refused (see refused).
+func (e *execution) reserved(release func()) {
+	e.release = release
+}
+
+// refused releases any reservation recorded for a retry that has been
+// refused.
+func (e *execution) refused() {
+	if e.release != nil {
+		e.release()
+		e.release = nil
+	}
+}
+
+// executionFrom returns the innermost execution reachable from ctx, or nil if
+// ctx did not originate from Execute.
+func executionFrom(ctx context.Context) *execution {
+	e, _ := ctx.Value(executionKey{}).(*execution)
+	return e
+}
+
+// newExecutionID returns a new, random execution ID of 16 hex digits.
+func newExecutionID() string {
+	return fmt.Sprintf("%016x", rand.Uint64())
+}
+
+// ExecutionIDFromContext returns the ID uniquely identifying the execution
+// ctx belongs to; that is, when ctx is passed to a ContextFunc or hook by
+// Execute. Every call to Execute is assigned a new, random ID so that all of
+// the attempts of one logical operation may be correlated across logging,
+// tracing and metrics systems. An empty string is returned if ctx did not
+// originate from Execute.
+func ExecutionIDFromContext(ctx context.Context) string {
+	if e := executionFrom(ctx); e != nil {
+		return e.id
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"slices"
+)
+
+// maxAttemptErrors is the largest number of failed attempts retained by an
+// AttemptsError; older failures are discarded (and counted) so that a long
+// running execution (see Progress) cannot accumulate them without bound.
+const maxAttemptErrors = 100
+
+// AttemptsError is returned by Execute when all of its attempts have been
+// exhausted. It describes the failure of each attempt made -- including when
+// it was made and the error it returned -- which is invaluable when
+// diagnosing a failure after the fact.
+//
+// AttemptsError wraps ErrAttemptsExhausted along with the error returned by
+// every attempt it holds, so errors.Is and errors.As match both the sentinel
+// and any of the individual causes. Its message includes that of the error
+// returned by the final attempt (see Last). An AttemptsError is never
+// considered a retry signal (see IsRetrySignal), even though the errors it
+// holds are.
+type AttemptsError struct {
+	// Attempts describes each failed attempt, in the order they were made.
+	// Only the most recent 100 attempts are retained.
+	Attempts []AttemptReport
+
+	// Omitted is the number of earlier attempts discarded from Attempts.
+	Omitted uint
+}
+
+func (e *AttemptsError) Error() string {
+	return fmt.Sprintf("%v (%d attempts)%s", ErrAttemptsExhausted, uint(len(e.Attempts))+e.Omitted, lastSuffix(e.Last()))
+}
+
+// Last returns the error returned by the final attempt, or nil if the
+// receiver holds no attempts.
+func (e *AttemptsError) Last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// Unwrap returns ErrAttemptsExhausted followed by the error returned by each
+// of the receiver's Attempts.
+func (e *AttemptsError) Unwrap() []error {
+	errs := make([]error, 1, len(e.Attempts)+1)
+	errs[0] = ErrAttemptsExhausted
+
+	for _, a := range e.Attempts {
+		errs = append(errs, a.Err)
+	}
+
+	return errs
+}
+
+// lastSuffix returns the suffix used by the errors Execute returns when giving
+// up to describe last, the error returned by the final attempt, so that
+// (say) exhausting retries of a timeout may be told apart from exhausting
+// retries of a server error. A bare ErrDoRetry says nothing useful and adds
+// no suffix.
+func lastSuffix(last error) string {
+	if lastCause(last) == nil {
+		return ""
+	}
+	return ": " + last.Error()
+}
+
+// lastCause returns last unless it is a bare ErrDoRetry, which conveys no
+// cause at all.
+func lastCause(last error) error {
+	if last == ErrDoRetry {
+		return nil
+	}
+	return last
+}
+
+// signalCause returns the cause carried by err if it is a retry signal --
+// ErrDoRetry (whether bare or wrapping a cause) or an error created by
+// RetryAfter or Progress -- and err otherwise. This allows an error
+// describing why Execute gave up to wrap the error of the final attempt
+// without itself appearing to be a retry signal.
+func signalCause(err error) error {
+	for {
+		switch e := err.(type) {
+		case Error:
+			if e == ErrDoRetry {
+				return nil
+			}
+			return err
+		case *wrappedError:
+			if e.sentinel != ErrDoRetry {
+				return err
+			}
+			err = e.cause
+		case *RetryAfterError:
+			err = e.Err
+		case *ProgressError:
+			err = e.Err
+		default:
+			return err
+		}
+	}
+}
+
+// failed records the failed attempt ar, discarding the oldest attempt held
+// by the receiver if it is full.
+func (e *AttemptsError) failed(ar AttemptReport) {
+	if len(e.Attempts) == maxAttemptErrors {
+		e.Attempts = slices.Delete(e.Attempts, 0, 1)
+		e.Omitted++
+	}
+	e.Attempts = append(e.Attempts, ar)
+}
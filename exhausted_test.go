@@ -0,0 +1,89 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestAttemptsError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errs := []error{io.ErrUnexpectedEOF, os.ErrDeadlineExceeded, RetryAfterCause(time.Minute, io.EOF)}
+		r := New(3).WithRetryIf(func(error) bool { return true }).WithFunction(func(i uint) error {
+			return errs[i]
+		})
+
+		start := time.Now()
+		err := r.Execute(context.Background())
+
+		var ae *AttemptsError
+		if !errors.As(err, &ae) {
+			t.Fatalf("Execute() == %v; wanted an *AttemptsError", err)
+		}
+
+		for _, target := range append(errs, ErrAttemptsExhausted, io.EOF) {
+			if !errors.Is(err, target) {
+				t.Errorf("errors.Is(%v, %v) == false", err, target)
+			}
+		}
+
+		if IsRetrySignal(err) {
+			t.Errorf("IsRetrySignal(%v) == true", err)
+		}
+
+		if got, want := err.Error(), "all attempts exhausted (3 attempts): retry attempt after 1m0s: EOF"; got != want {
+			t.Errorf("Error() == %q; wanted %q", got, want)
+		}
+
+		for i, at := range []time.Duration{0, time.Second, 2 * time.Second} {
+			if a := ae.Attempts[i]; a.Iteration != uint(i) || a.Start.Sub(start) != at || a.Err != errs[i] {
+				t.Errorf("Attempts[%d] == %+v; wanted iteration %d at %v failing with %v", i, a, i, at, errs[i])
+			}
+		}
+
+		err = New(maxAttemptErrors + 50).WithAlgorithm(FixedDelay(0)).WithFunction(func(uint) error {
+			return ErrDoRetry
+		}).Execute(context.Background())
+
+		if !errors.As(err, &ae) || len(ae.Attempts) != maxAttemptErrors || ae.Omitted != 50 || ae.Attempts[0].Iteration != 50 {
+			t.Errorf("Execute() == %v; wanted the last %d of %d attempts", err, maxAttemptErrors, maxAttemptErrors+50)
+		}
+	})
+}
+
+func TestExhaustionWrapsLast(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errServer := errors.New("500 Internal Server Error")
+		fn := func(uint) error { return ErrDoRetry.Wrap(errServer) }
+
+		for _, tc := range []struct {
+			name string
+			r    *Rerun
+			want string
+		}{
+			{"attempts", New(3), "all attempts exhausted (3 attempts): retry attempt: 500 Internal Server Error"},
+			{"budget", New(3).WithBudget(NewRetryBudget(0, time.Hour)), "retry budget exhausted: retry attempt: 500 Internal Server Error"},
+			{"cost", New(3).WithCost(func(Attempt) float64 { return 1 }, 1), "retry budget exhausted: retry 1 costing 1 would exceed limit of 1 (1 spent): retry attempt: 500 Internal Server Error"},
+			{"cost budget", New(3).WithCost(func(Attempt) float64 { return 1 }, 0).WithBudget(NewRetryBudget(0, time.Hour)), "retry budget exhausted: retry 1 costing 1 refused by shared budget: retry attempt: 500 Internal Server Error"},
+			{"elapsed", New(3).WithMaxElapsed(time.Millisecond), "retry budget exhausted: retry 1 after 1s would exceed limit of 1ms (0s elapsed): retry attempt: 500 Internal Server Error"},
+		} {
+			err := tc.r.WithFunction(fn).Execute(context.Background())
+
+			if !IsExhausted(err) || !errors.Is(err, errServer) || IsRetrySignal(err) {
+				t.Errorf("%s: Execute() == %v; wanted exhaustion wrapping %v", tc.name, err, errServer)
+			}
+
+			if got := err.Error(); got != tc.want {
+				t.Errorf("%s: Error() == %q; wanted %q", tc.name, got, tc.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ExponentialDelay defines a delay Algorithm imposing wait periods that grow
+// geometrically with each retry, optionally capped at a maximum value, such
+// that the wait before retry n is:
+//
+//	min(Base · Multiplier^(n-1), Max)
+//
+// This is the classic "exponential backoff" schedule. For example, a Base of
+// 100ms with a Multiplier of 2 and a Max of 1s produces waits of 100ms,
+// 200ms, 400ms, 800ms, 1s, 1s, and so on.
+//
+// If MultiplierMax is set, each waiting period is instead the one before it
+// multiplied by a growth factor drawn afresh for each retry, so each wait
+// depends upon the one before it. As for DecorrelatedJitter, Execute then
+// obtains a fresh instance holding its own state for each execution (see
+// Reset). An instance not obtained from Reset holds no state and is safe for
+// concurrent use; each call to its Wait method draws a new chain of factors
+// up to the given iteration.
+type ExponentialDelay struct {
+	// Start defines the warmup time Rerun uses before its first call to a Func.
+	// A negative value will cause the OK method to return ErrNegativeDuration.
+	Start time.Duration
+
+	// Base defines the waiting period before the first rerun attempt. A
+	// negative value will cause the OK method to return ErrNegativeDuration.
+	Base time.Duration
+
+	// Multiplier is the factor by which each waiting period exceeds the one
+	// before it. It must be positive; values less than 1 result in a
+	// decreasing schedule.
+	Multiplier float64
+
+	// Max is the upper bound for any waiting period. A zero value imposes no
+	// upper bound while a negative value causes the OK method to return
+	// ErrNegativeDuration.
+	Max time.Duration
+
+	// MultiplierMax, if non-zero, turns Multiplier into the lower bound of
+	// a range from which the growth factor is randomly drawn for each retry
+	// (e.g. a Multiplier of 1.5 with a MultiplierMax of 2.5). This
+	// decorrelates fleets of clients sharing the same Base and Max, and is
+	// a cheap alternative to full jitter for some workloads. It cannot be
+	// less than Multiplier.
+	MultiplierMax float64
+
+	// Rand is the source of randomness used when MultiplierMax is set. If
+	// nil, the top-level functions from math/rand/v2 are used.
+	Rand Random
+
+	state *exponentialState
+}
+
+// exponentialState holds the wait most recently returned for an iteration
+// by an ExponentialDelay having a MultiplierMax.
+type exponentialState struct {
+	n    uint
+	wait time.Duration
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the Algorithm interface.
+func (ed ExponentialDelay) OK(uint) error {
+	var errs []error
+
+	if ed.Start < 0 {
+		errs = append(errs, fieldError("ExponentialDelay.Start", ed.Start, ErrNegativeDuration))
+	}
+
+	if ed.Base < 0 {
+		errs = append(errs, fieldError("ExponentialDelay.Base", ed.Base, ErrNegativeDuration))
+	}
+
+	if ed.Max < 0 {
+		errs = append(errs, fieldError("ExponentialDelay.Max", ed.Max, ErrNegativeDuration))
+	}
+
+	if ed.Multiplier <= 0 || math.IsInf(ed.Multiplier, 0) || math.IsNaN(ed.Multiplier) {
+		errs = append(errs, fieldError("ExponentialDelay.Multiplier", ed.Multiplier, ErrInvalidMultiplier))
+	}
+
+	if ed.MultiplierMax != 0 && !(ed.MultiplierMax >= ed.Multiplier && !math.IsInf(ed.MultiplierMax, 0)) {
+		errs = append(errs, fieldError("ExponentialDelay.MultiplierMax", ed.MultiplierMax, ErrInvalidMultiplier))
+	}
+
+	return joinErrors(errs...)
+}
+
+// Warmup returns the value of the receiver's Start field in order to satisfy
+// the Algorithm interface.
+func (ed ExponentialDelay) Warmup() time.Duration {
+	return ed.Start
+}
+
+// Wait calculates the waiting period for the given iteration number. Values
+// too large to be represented by a time.Duration are truncated to the
+// receiver's Max (if any) or the largest possible Duration otherwise.
+//
+// If MultiplierMax is set and the receiver was obtained from Reset, the wait
+// is derived from the one it last returned if that was for iteration n-1,
+// which is always the case when called by Execute; otherwise, a new chain of
+// waits is drawn, beginning at iteration 1.
+// Wait is part of the Algorithm interface.
+func (ed ExponentialDelay) Wait(n uint) time.Duration {
+	if n == 0 || ed.Base == 0 {
+		return 0
+	}
+
+	if ed.Deterministic() {
+		return ed.bound(float64(ed.Base) * math.Pow(ed.Multiplier, float64(n-1)))
+	}
+
+	var s exponentialState
+	if ed.state != nil && ed.state.n < n {
+		s = *ed.state
+	}
+
+	for s.n < n {
+		if s.n == 0 {
+			s.n, s.wait = 1, ed.bound(float64(ed.Base))
+			continue
+		}
+		s.n, s.wait = s.n+1, ed.bound(float64(s.wait)*ed.multiplier())
+	}
+
+	if ed.state != nil {
+		*ed.state = s
+	}
+
+	return s.wait
+}
+
+// bound returns d as a Duration no greater than the receiver's Max (if any).
+func (ed ExponentialDelay) bound(d float64) time.Duration {
+	if ed.Max > 0 && d > float64(ed.Max) {
+		return ed.Max
+	}
+
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(d)
+}
+
+// multiplier returns a growth factor drawn from the range Multiplier to
+// MultiplierMax.
+func (ed ExponentialDelay) multiplier() float64 {
+	return ed.Multiplier + (ed.MultiplierMax-ed.Multiplier)*RandomFloat64(ed.Rand)
+}
+
+// Reset returns a copy of the receiver holding new, empty state for use by a
+// single execution, or the receiver itself if it has no MultiplierMax.
+// This method implements the Resetter interface.
+func (ed ExponentialDelay) Reset() Algorithm {
+	if !ed.Deterministic() {
+		ed.state = new(exponentialState)
+	}
+	return ed
+}
+
+// Deterministic reports whether the receiver's growth factor is fixed (i.e.
+// MultiplierMax is unset).
+func (ed ExponentialDelay) Deterministic() bool {
+	return ed.MultiplierMax <= ed.Multiplier
+}
+
+func (ed ExponentialDelay) String() string {
+	var limit string
+	if ed.Max > 0 {
+		limit = " cap " + ed.Max.String()
+	}
+	mult := fmt.Sprint(ed.Multiplier)
+	if ed.MultiplierMax > ed.Multiplier {
+		mult += fmt.Sprintf("–%g", ed.MultiplierMax)
+	}
+	return fmt.Sprintf("exponential %v×%s%s%s", ed.Base, mult, limit, warmupString(ed.Start))
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExponentialDelayMultiplierRange(t *testing.T) {
+	ed := ExponentialDelay{Base: time.Second, Multiplier: 1.5, MultiplierMax: 2.5, Rand: NewRandom(7)}
+
+	if err := CheckAlgorithm(ed, 10); err != nil {
+		t.Fatalf("CheckAlgorithm() == %v", err)
+	}
+
+	if ed.Deterministic() {
+		t.Errorf("Deterministic() == true; wanted false")
+	}
+
+	seen := make(map[time.Duration]bool)
+	for range 50 {
+		d := ed.Wait(3)
+		if d < 2250*time.Millisecond || d > 6250*time.Millisecond {
+			t.Errorf("Wait(3) == %v; wanted between 2.25s and 6.25s", d)
+		}
+		seen[d] = true
+	}
+
+	if len(seen) < 2 {
+		t.Errorf("Wait(3) never varied")
+	}
+
+	if got, want := ed.String(), "exponential 1s×1.5–2.5"; got != want {
+		t.Errorf("String() == %q; wanted %q", got, want)
+	}
+
+	ed.MultiplierMax = 1
+	if err := ed.OK(3); !errors.Is(err, ErrInvalidMultiplier) {
+		t.Errorf("OK() with MultiplierMax < Multiplier returned %v; wanted %v", err, ErrInvalidMultiplier)
+	}
+}
+
+func TestExponentialDelayMultiplierPerRetry(t *testing.T) {
+	ed := ExponentialDelay{Base: time.Millisecond, Multiplier: 1.5, MultiplierMax: 2.5, Rand: NewRandom(11)}
+
+	for range 20 {
+		algo := ed.Reset()
+
+		// Each wait is the one before it grown by a factor within range.
+		prev := algo.Wait(1)
+		for n := uint(2); n <= 15; n++ {
+			d := algo.Wait(n)
+			if r := float64(d) / float64(prev); r < 1.5-1e-6 || r > 2.5+1e-6 {
+				t.Fatalf("Wait(%d)/Wait(%d) == %v/%v == %.3f; wanted between 1.5 and 2.5", n, n-1, d, prev, r)
+			}
+			prev = d
+		}
+	}
+
+	// Once Max is reached, it is not exceeded.
+	ed.Max = 100 * time.Millisecond
+	algo := ed.Reset()
+	for n := uint(1); n <= 30; n++ {
+		if d := algo.Wait(n); d > ed.Max {
+			t.Errorf("Wait(%d) == %v; wanted no more than %v", n, d, ed.Max)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package failrate provides small, reusable primitives for tracking the rate
+// at which operations fail -- the raw material for adaptive Algorithms,
+// circuit breakers and error classifiers. A Window counts successes and
+// failures over a sliding window of time while an EWMA maintains an
+// exponentially weighted moving average of the failure rate.
+//
+// Each type is safe for concurrent use and implements Recorder, allowing it
+// to observe every attempt made by a rerun.Rerun through the Hooks returned
+// by Hooks.
+package failrate
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// Recorder is implemented by each of the trackers in this package.
+type Recorder interface {
+	// Record notes the outcome of a single operation: a success if err is
+	// nil and a failure otherwise.
+	Record(err error)
+}
+
+// Hooks returns rerun.Hooks recording the outcome of every attempt made by
+// a Rerun with each of the given Recorders:
+//
+//	w := failrate.NewWindow(time.Minute, 6)
+//	r := rerun.New(5).WithNotify(failrate.Hooks(w))
+func Hooks(recs ...Recorder) rerun.Hooks {
+	return rerun.Hooks{
+		OnAttemptEnd: func(_ context.Context, ar rerun.AttemptReport) {
+			for _, rec := range recs {
+				rec.Record(ar.Err)
+			}
+		},
+	}
+}
+
+// Window counts successes and failures over a sliding window of time. The
+// window is divided into a fixed number of buckets such that, as time
+// passes, the counts of whole buckets expire at once. More buckets make
+// for a smoother slide at the cost of a little memory.
+//
+// A Window must be created by NewWindow.
+type Window struct {
+	width time.Duration // of each bucket
+
+	mu      sync.Mutex
+	buckets []bucket
+}
+
+type bucket struct {
+	n         int64 // bucket number (time / width)
+	successes uint64
+	failures  uint64
+}
+
+// NewWindow returns a new Window spanning size, divided into the given
+// number of buckets. A non-positive number of buckets is treated as 1 and a
+// size smaller than the number of buckets is increased to match.
+func NewWindow(size time.Duration, buckets int) *Window {
+	buckets = max(buckets, 1)
+	return &Window{
+		width:   max(size/time.Duration(buckets), 1),
+		buckets: make([]bucket, buckets),
+	}
+}
+
+// Record notes the outcome of a single operation: a success if err is nil
+// and a failure otherwise.
+func (w *Window) Record(err error) {
+	n := w.current()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	b := &w.buckets[n%int64(len(w.buckets))]
+	if b.n != n {
+		*b = bucket{n: n}
+	}
+
+	if err == nil {
+		b.successes++
+	} else {
+		b.failures++
+	}
+}
+
+// Counts returns the number of successes and failures recorded within the
+// window.
+func (w *Window) Counts() (successes, failures uint64) {
+	n := w.current()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, b := range w.buckets {
+		if n-b.n < int64(len(w.buckets)) {
+			successes += b.successes
+			failures += b.failures
+		}
+	}
+
+	return successes, failures
+}
+
+// Rate returns the fraction of operations recorded within the window that
+// failed, or zero if there were none.
+func (w *Window) Rate() float64 {
+	s, f := w.Counts()
+	if s+f == 0 {
+		return 0
+	}
+	return float64(f) / float64(s+f)
+}
+
+func (w *Window) current() int64 {
+	return time.Now().UnixNano() / int64(w.width)
+}
+
+// EWMA maintains an exponentially weighted moving average of the failure
+// rate, in which the weight of each outcome decays by half over a given
+// period of time. Unlike a Window, recent outcomes always count for more
+// than older ones and there is no abrupt expiry.
+//
+// An EWMA must be created by NewEWMA.
+type EWMA struct {
+	halfLife time.Duration
+
+	mu       sync.Mutex
+	last     time.Time
+	failures float64 // decayed weight of failures
+	total    float64 // decayed weight of all outcomes
+}
+
+// NewEWMA returns a new EWMA in which the weight of each outcome halves over
+// each halfLife. A non-positive halfLife causes only the latest outcome to
+// be considered.
+func NewEWMA(halfLife time.Duration) *EWMA {
+	return &EWMA{halfLife: halfLife}
+}
+
+// Record notes the outcome of a single operation: a success if err is nil
+// and a failure otherwise.
+func (e *EWMA) Record(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.decay(time.Now())
+
+	e.total++
+	if err != nil {
+		e.failures++
+	}
+}
+
+// Rate returns the weighted fraction of recorded outcomes that were
+// failures, or zero if none have been recorded.
+func (e *EWMA) Rate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// n.b. Decay affects both weights equally so needn't be applied here.
+	if e.total == 0 {
+		return 0
+	}
+	return e.failures / e.total
+}
+
+// Samples returns the current, decayed weight of all recorded outcomes,
+// which may be used to disregard the Rate until enough have been recorded
+// for it to be meaningful.
+func (e *EWMA) Samples() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.halfLife > 0 {
+		e.decay(time.Now())
+	}
+	return e.total
+}
+
+// decay applies the decay accrued between the last update and now. The
+// receiver's mutex must be held.
+func (e *EWMA) decay(now time.Time) {
+	switch {
+	case e.halfLife <= 0:
+		e.failures, e.total = 0, 0
+
+	case !e.last.IsZero():
+		if elapsed := now.Sub(e.last); elapsed > 0 {
+			f := math.Exp2(-float64(elapsed) / float64(e.halfLife))
+			e.failures *= f
+			e.total *= f
+		}
+	}
+
+	e.last = now
+}
@@ -0,0 +1,99 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package failrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+var errFail = errors.New("fail")
+
+func TestWindow(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		w := NewWindow(time.Minute, 6)
+
+		w.Record(nil)
+		w.Record(errFail)
+		time.Sleep(30 * time.Second)
+		w.Record(errFail)
+		w.Record(errFail)
+
+		if s, f := w.Counts(); s != 1 || f != 3 {
+			t.Errorf("Counts() == (%d, %d); wanted (1, 3)", s, f)
+		}
+
+		if got := w.Rate(); got != 0.75 {
+			t.Errorf("Rate() == %v; wanted 0.75", got)
+		}
+
+		// The first two outcomes expire after a minute.
+		time.Sleep(30 * time.Second)
+		if s, f := w.Counts(); s != 0 || f != 2 {
+			t.Errorf("Counts() == (%d, %d) after 1m; wanted (0, 2)", s, f)
+		}
+
+		time.Sleep(time.Hour)
+		if got := w.Rate(); got != 0 {
+			t.Errorf("Rate() == %v after 1h; wanted 0", got)
+		}
+	})
+}
+
+func TestEWMA(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		e := NewEWMA(time.Minute)
+
+		if got := e.Rate(); got != 0 {
+			t.Errorf("Rate() == %v with no outcomes; wanted 0", got)
+		}
+
+		e.Record(errFail)
+		time.Sleep(time.Minute)
+		e.Record(nil)
+
+		// The failure now carries half the weight of the success.
+		if got, want := e.Rate(), 1.0/3; got != want {
+			t.Errorf("Rate() == %v; wanted %v", got, want)
+		}
+
+		time.Sleep(time.Minute)
+		if got := e.Samples(); got != 0.75 {
+			t.Errorf("Samples() == %v; wanted 0.75", got)
+		}
+	})
+}
+
+func TestHooks(t *testing.T) {
+	w := NewWindow(time.Hour, 1)
+	e := NewEWMA(time.Hour)
+
+	r := rerun.New(3).
+		WithAlgorithm(rerun.FixedDelay(0)).
+		WithNotify(Hooks(w, e)).
+		WithFunction(func(i uint) error {
+			if i < 2 {
+				return rerun.ErrDoRetry
+			}
+			return nil
+		})
+
+	if err := r.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() == %v", err)
+	}
+
+	if s, f := w.Counts(); s != 1 || f != 2 {
+		t.Errorf("Counts() == (%d, %d); wanted (1, 2)", s, f)
+	}
+
+	if got := e.Rate(); got < 0.66 || got > 0.67 {
+		t.Errorf("Rate() == %v; wanted ~0.667", got)
+	}
+}
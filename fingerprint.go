@@ -0,0 +1,203 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// ErrorGroup summarizes those attempts in a Report whose errors share the
+// same fingerprint.
+type ErrorGroup struct {
+	// Fingerprint is the value shared by all errors in the group.
+	Fingerprint string
+
+	// Count is the number of attempts whose error had this Fingerprint.
+	Count int
+
+	// Err is the first error having this Fingerprint.
+	Err error
+
+	// First and Last are the start times of the first and last attempts
+	// whose error had this Fingerprint.
+	First time.Time
+	Last  time.Time
+}
+
+// ErrorGroups returns the errors of the receiver's failed attempts grouped by
+// the fingerprint returned by fp, so that callers may see at a glance that,
+// e.g., 7 attempts timed out and 2 suffered connection resets. Groups are
+// ordered by descending Count and then by first occurrence. If fp is nil,
+// Fingerprint is used; other functions may instead group errors by some
+// application-specific classification. Successful attempts are ignored.
+func (rp *Report) ErrorGroups(fp func(error) string) []ErrorGroup {
+	if rp == nil {
+		return nil
+	}
+
+	if fp == nil {
+		fp = Fingerprint
+	}
+
+	var groups []ErrorGroup
+	index := make(map[string]int)
+
+	for _, a := range rp.Attempts {
+		if a.Err == nil {
+			continue
+		}
+
+		key := fp(a.Err)
+		if i, ok := index[key]; ok {
+			groups[i].Count++
+			groups[i].Last = a.Start
+			continue
+		}
+
+		index[key] = len(groups)
+		groups = append(groups, ErrorGroup{Fingerprint: key, Count: 1, Err: a.Err, First: a.Start, Last: a.Start})
+	}
+
+	slices.SortStableFunc(groups, func(a, b ErrorGroup) int {
+		return b.Count - a.Count
+	})
+
+	return groups
+}
+
+// OutcomeBucket counts the outcomes of the attempts in a Report that started
+// within a single interval of time.
+type OutcomeBucket struct {
+	// Start and End delimit the interval covered by this bucket.
+	Start time.Time
+	End   time.Time
+
+	// Counts holds the number of attempts in this bucket for each error
+	// fingerprint. Successful attempts are counted under the empty string.
+	Counts map[string]int
+}
+
+// Histogram divides the time spanned by the receiver's attempts into n equal
+// intervals and counts the outcomes of the attempts starting within each,
+// keyed by the fingerprint returned by fp (or by Fingerprint if fp is nil).
+// This shows how failure modes evolved over the course of an execution,
+// e.g. connection resets giving way to timeouts. Histogram returns nil for
+// a Report having no attempts; if n is less than 1, a single bucket is used.
+func (rp *Report) Histogram(n int, fp func(error) string) []OutcomeBucket {
+	if rp == nil || len(rp.Attempts) == 0 {
+		return nil
+	}
+
+	if fp == nil {
+		fp = Fingerprint
+	}
+
+	n = max(n, 1)
+	first := rp.Attempts[0].Start
+	span := rp.Attempts[len(rp.Attempts)-1].Start.Sub(first)
+	width := span / time.Duration(n)
+
+	buckets := make([]OutcomeBucket, n)
+	for i := range buckets {
+		buckets[i] = OutcomeBucket{
+			Start:  first.Add(time.Duration(i) * width),
+			End:    first.Add(time.Duration(i+1) * width),
+			Counts: make(map[string]int),
+		}
+	}
+	buckets[n-1].End = first.Add(span)
+
+	for _, a := range rp.Attempts {
+		var i int
+		if span > 0 {
+			i = min(int(float64(n)*float64(a.Start.Sub(first))/float64(span)), n-1)
+		}
+
+		var key string
+		if a.Err != nil {
+			key = fp(a.Err)
+		}
+		buckets[i].Counts[key]++
+	}
+
+	return buckets
+}
+
+// Fingerprint returns a string identifying the kind of error err represents,
+// such that errors of the same kind share a fingerprint even if their
+// messages differ. It finds the innermost error wrapped by err -- following
+// the first branch of any joined errors other than ErrDoRetry -- and returns
+// its type. If that error is a sentinel value (i.e. it was created
+// by errors.New or its type is not a struct) its message is also included.
+// For example:
+//
+//	*net.DNSError
+//	syscall.Errno: connection reset by peer
+//	*errors.errorString: EOF
+//
+// Fingerprint returns an empty string for a nil error.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	leaf := innermost(err)
+
+	t := reflect.TypeOf(leaf)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct && !errorsNew(leaf) {
+		return fmt.Sprintf("%T", leaf)
+	}
+
+	return fmt.Sprintf("%T: %v", leaf, leaf)
+}
+
+var errorStringType = reflect.TypeOf(errors.New(""))
+
+func errorsNew(err error) bool {
+	return reflect.TypeOf(err) == errorStringType
+}
+
+// innermost returns the deepest error wrapped by err, following the first
+// branch of any joined errors other than ErrDoRetry.
+func innermost(err error) error {
+	for {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			next := u.Unwrap()
+			if next == nil {
+				return err
+			}
+			err = next
+
+		case interface{ Unwrap() []error }:
+			branches := u.Unwrap()
+			if len(branches) == 0 {
+				return err
+			}
+
+			next := branches[0]
+			for _, b := range branches {
+				if b != nil && b != ErrDoRetry {
+					next = b
+					break
+				}
+			}
+
+			if next == nil {
+				return err
+			}
+			err = next
+
+		default:
+			return err
+		}
+	}
+}
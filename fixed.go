@@ -2,7 +2,11 @@
 
 package rerun
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 const (
 	Fixed1s    = FixedDelay(time.Second)
@@ -14,7 +18,7 @@ type FixedDelay time.Duration
 
 func (fd FixedDelay) OK(uint) error {
 	if fd < 0 {
-		return ErrNegativeDuration
+		return fieldError("FixedDelay", time.Duration(fd), ErrNegativeDuration)
 	}
 	return nil
 }
@@ -23,6 +27,93 @@ func (FixedDelay) Warmup() time.Duration {
 	return 0
 }
 
-func (fd FixedDelay) Wait(uint) time.Duration {
+func (fd FixedDelay) Wait(n uint) time.Duration {
+	if n == 0 {
+		return 0
+	}
 	return time.Duration(fd)
 }
+
+func (fd FixedDelay) String() string {
+	return "fixed " + time.Duration(fd).String()
+}
+
+// Fixed is a richer variant of FixedDelay for cases where a bare Duration
+// cannot express what's needed: a warmup period and random jitter. Each
+// waiting period is chosen uniformly from the range
+// Delay ± Delay·JitterFraction so that many clients sharing the same
+// configuration don't retry in lockstep.
+type Fixed struct {
+	// Delay is the nominal waiting period before each retry. A negative
+	// value will cause the OK method to return ErrNegativeDuration.
+	Delay time.Duration
+
+	// Start defines the warmup time Rerun uses before its first call to a Func.
+	// A negative value will cause the OK method to return ErrNegativeDuration.
+	Start time.Duration
+
+	// JitterFraction is the largest fraction of Delay by which any waiting
+	// period may differ from it. It must be in the range 0 to 1, inclusive;
+	// otherwise, the OK method will return ErrInvalidJitter. Zero disables
+	// jitter altogether.
+	JitterFraction float64
+
+	// Rand is the source of randomness for jitter. If nil, the top-level
+	// functions from math/rand/v2 are used.
+	Rand Random
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the Algorithm interface.
+func (f Fixed) OK(uint) error {
+	var errs []error
+
+	if f.Delay < 0 {
+		errs = append(errs, fieldError("Fixed.Delay", f.Delay, ErrNegativeDuration))
+	}
+
+	if f.Start < 0 {
+		errs = append(errs, fieldError("Fixed.Start", f.Start, ErrNegativeDuration))
+	}
+
+	if !(f.JitterFraction >= 0 && f.JitterFraction <= 1) {
+		errs = append(errs,
+			fieldError("Fixed.JitterFraction", f.JitterFraction, ErrInvalidJitter))
+	}
+
+	return joinErrors(errs...)
+}
+
+// Warmup returns the value of the receiver's Start field in order to satisfy
+// the Algorithm interface.
+func (f Fixed) Warmup() time.Duration {
+	return f.Start
+}
+
+// Wait returns the receiver's Delay, randomly adjusted by up to its
+// JitterFraction, for any iteration but zero.
+// Wait is part of the Algorithm interface.
+func (f Fixed) Wait(n uint) time.Duration {
+	if n == 0 || f.JitterFraction == 0 {
+		return FixedDelay(f.Delay).Wait(n)
+	}
+
+	spread := f.JitterFraction * float64(f.Delay)
+	d := float64(f.Delay) - spread + 2*spread*RandomFloat64(f.Rand)
+
+	return saturate(math.Round(d))
+}
+
+// Deterministic reports whether the receiver applies no jitter.
+func (f Fixed) Deterministic() bool {
+	return f.JitterFraction == 0
+}
+
+func (f Fixed) String() string {
+	var jitter string
+	if f.JitterFraction != 0 {
+		jitter = fmt.Sprintf(" ±%g%%", f.JitterFraction*100)
+	}
+	return "fixed " + f.Delay.String() + jitter + warmupString(f.Start)
+}
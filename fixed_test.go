@@ -0,0 +1,46 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFixed(t *testing.T) {
+	f := Fixed{Delay: time.Second, Start: time.Minute, JitterFraction: 0.2, Rand: NewRandom(1)}
+
+	if err := CheckAlgorithm(f, 100); err != nil {
+		t.Fatalf("CheckAlgorithm() == %v", err)
+	}
+
+	var varied bool
+	for i := uint(1); i < 100; i++ {
+		d := f.Wait(i)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Errorf("Wait(%d) == %v; wanted 1s ±20%%", i, d)
+		}
+		varied = varied || d != time.Second
+	}
+
+	if !varied {
+		t.Errorf("Wait never varied from 1s")
+	}
+
+	if got, want := f.String(), "fixed 1s ±20%, warmup 1m0s"; got != want {
+		t.Errorf("String() == %q; wanted %q", got, want)
+	}
+
+	if err := (Fixed{Delay: time.Second, JitterFraction: 1.5}).OK(3); !errors.Is(err, ErrInvalidJitter) {
+		t.Errorf("OK() with JitterFraction 1.5 returned %v; wanted %v", err, ErrInvalidJitter)
+	}
+}
+
+func TestFixedSaturates(t *testing.T) {
+	f := Fixed{Delay: math.MaxInt64, JitterFraction: 0.5, Rand: highRandom{}}
+	if got := f.Wait(1); got != math.MaxInt64 {
+		t.Errorf("Wait(1) == %v; wanted %v", got, time.Duration(math.MaxInt64))
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+	"github.com/olympiclabs/rerun/reruntest"
+)
+
+func TestGoldenSchedules(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		algo rerun.Algorithm
+	}{
+		{"fixed", rerun.Fixed500ms},
+		{"linear", rerun.LinearDelay{Start: 50 * time.Millisecond, Base: 100 * time.Millisecond, Slope: float64(25 * time.Millisecond)}},
+		{"exponential", rerun.ExponentialDelay{Base: 100 * time.Millisecond, Multiplier: 2, Max: 5 * time.Second}},
+		{"logarithmic", rerun.LogarithmicDelay{Units: rerun.Millisecond, Amplifier: 300, Coefficient: 20, Modifier: -14, VerticalOffset: -400}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reruntest.AssertGoldenSchedule(t, tc.algo, 10, "testdata/"+tc.name+".golden")
+		})
+	}
+}
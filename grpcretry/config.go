@@ -0,0 +1,167 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package grpcretry provides integration between rerun and gRPC clients.
+package grpcretry
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/olympiclabs/rerun"
+)
+
+const ErrIncompatibleAlgorithm = rerun.Error("algorithm not expressible as a gRPC retry policy")
+
+// Codes is a status-code classifier identifying which gRPC status codes
+// should be retried.
+type Codes []codes.Code
+
+// DefaultCodes are the status codes retried if no others are specified.
+var DefaultCodes = Codes{codes.Unavailable, codes.ResourceExhausted}
+
+// Retryable reports whether c is one of the receiver's status codes.
+func (cs Codes) Retryable(c codes.Code) bool {
+	for _, rc := range cs {
+		if rc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the receiver's codes as they are spelled in a gRPC service
+// config (e.g. "UNAVAILABLE").
+func (cs Codes) names() []string {
+	n := make([]string, len(cs))
+	for i, c := range cs {
+		if s, ok := codeNames[c]; ok {
+			n[i] = s
+		} else {
+			n[i] = strconv.FormatUint(uint64(c), 10)
+		}
+	}
+	return n
+}
+
+var codeNames = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// maxAttempts is the greatest MaxAttempts honored by gRPC.
+const maxAttempts = 5
+
+// RetryPolicy mirrors the "retryPolicy" object of a gRPC service config.
+type RetryPolicy struct {
+	MaxAttempts          uint     `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// NewRetryPolicy returns the gRPC RetryPolicy equivalent to r when retrying
+// the status codes in cs. If cs is empty, DefaultCodes are used.
+//
+// Only a Rerun whose Algorithm is an ExponentialDelay having no warmup
+// period, a positive Base and Max, and no MultiplierMax can be expressed as a
+// RetryPolicy; any other Algorithm results in ErrIncompatibleAlgorithm.
+//
+// Since gRPC rejects a MaxAttempts below 2 and treats any above 5 as 5, the
+// receiver's iterations are capped at 5 (as are those of a Rerun created
+// with Forever) and fewer than 2 result in ErrTooFewIterations.
+//
+// Take note that gRPC applies "full jitter" to each backoff -- choosing a
+// random wait between zero and the value calculated by ExponentialDelay.
+func NewRetryPolicy(r *rerun.Rerun, cs Codes) (*RetryPolicy, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.Iterations() < 2 {
+		return nil, rerun.ErrTooFewIterations
+	}
+
+	var ed rerun.ExponentialDelay
+
+	switch a := r.Algorithm().(type) {
+	case rerun.ExponentialDelay:
+		ed = a
+	case *rerun.ExponentialDelay:
+		ed = *a
+	default:
+		return nil, ErrIncompatibleAlgorithm
+	}
+
+	if ed.Start != 0 || ed.Base <= 0 || ed.Max <= 0 || !ed.Deterministic() {
+		return nil, ErrIncompatibleAlgorithm
+	}
+
+	if len(cs) == 0 {
+		cs = DefaultCodes
+	}
+
+	return &RetryPolicy{
+		MaxAttempts:          min(r.Iterations(), maxAttempts),
+		InitialBackoff:       jsonDuration(ed.Base),
+		MaxBackoff:           jsonDuration(ed.Max),
+		BackoffMultiplier:    ed.Multiplier,
+		RetryableStatusCodes: cs.names(),
+	}, nil
+}
+
+// MethodName identifies the gRPC methods to which a method config applies.
+// An empty Method matches all methods of Service, and a zero MethodName
+// matches all methods of all services.
+type MethodName struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method,omitempty"`
+}
+
+// ServiceConfig returns the JSON encoding of a gRPC service config applying
+// the RetryPolicy equivalent to r (see NewRetryPolicy) to the given methods.
+// If no methods are specified, the policy applies to all methods.
+func ServiceConfig(r *rerun.Rerun, cs Codes, methods ...MethodName) ([]byte, error) {
+	rp, err := NewRetryPolicy(r, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(methods) == 0 {
+		methods = []MethodName{{}}
+	}
+
+	type methodConfig struct {
+		Name        []MethodName `json:"name"`
+		RetryPolicy *RetryPolicy `json:"retryPolicy"`
+	}
+
+	return json.Marshal(struct {
+		MethodConfig []methodConfig `json:"methodConfig"`
+	}{
+		MethodConfig: []methodConfig{{Name: methods, RetryPolicy: rp}},
+	})
+}
+
+// jsonDuration formats d in the JSON representation of a protobuf Duration.
+func jsonDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
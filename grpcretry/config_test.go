@@ -0,0 +1,68 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package grpcretry
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestServiceConfig(t *testing.T) {
+	r := rerun.New(4).WithAlgorithm(rerun.ExponentialDelay{
+		Base:       100 * time.Millisecond,
+		Multiplier: 1.5,
+		Max:        30 * time.Second,
+	})
+
+	got, err := ServiceConfig(r, Codes{codes.Unavailable, codes.Aborted}, MethodName{Service: "pkg.Svc"})
+	if err != nil {
+		t.Fatalf("ServiceConfig() returned unexpected error: %v", err)
+	}
+
+	want := `{"methodConfig":[{"name":[{"service":"pkg.Svc"}],"retryPolicy":{` +
+		`"maxAttempts":4,"initialBackoff":"0.1s","maxBackoff":"30s","backoffMultiplier":1.5,` +
+		`"retryableStatusCodes":["UNAVAILABLE","ABORTED"]}}]}`
+
+	if string(got) != want {
+		t.Errorf("ServiceConfig() ==\n\t%s\nwanted\n\t%s", got, want)
+	}
+}
+
+func TestNewRetryPolicyIncompatible(t *testing.T) {
+	for _, algo := range []rerun.Algorithm{
+		rerun.Fixed1s,
+		rerun.ExponentialDelay{Base: time.Second, Multiplier: 2},
+		rerun.ExponentialDelay{Start: time.Second, Base: time.Second, Multiplier: 2, Max: time.Minute},
+	} {
+		if _, err := NewRetryPolicy(rerun.New(3).WithAlgorithm(algo), nil); err != ErrIncompatibleAlgorithm {
+			t.Errorf("NewRetryPolicy(%#v) returned error %v; wanted %v", algo, err, ErrIncompatibleAlgorithm)
+		}
+	}
+}
+
+func TestNewRetryPolicyMaxAttempts(t *testing.T) {
+	algo := rerun.ExponentialDelay{Base: time.Second, Multiplier: 2, Max: time.Minute}
+
+	for _, tc := range []struct {
+		iterations uint
+		want       uint
+	}{
+		{2, 2},
+		{5, 5},
+		{10, 5},
+		{rerun.Forever, 5},
+	} {
+		rp, err := NewRetryPolicy(rerun.New(tc.iterations).WithAlgorithm(algo), nil)
+		if err != nil || rp.MaxAttempts != tc.want {
+			t.Errorf("NewRetryPolicy(New(%d)) == %+v, %v; wanted MaxAttempts of %d", tc.iterations, rp, err, tc.want)
+		}
+	}
+
+	if _, err := NewRetryPolicy(rerun.New(1).WithAlgorithm(algo), nil); err != rerun.ErrTooFewIterations {
+		t.Errorf("NewRetryPolicy(New(1)) returned error %v; wanted %v", err, rerun.ErrTooFewIterations)
+	}
+}
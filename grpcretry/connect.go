@@ -0,0 +1,121 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package grpcretry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// MinConnectTimeout is the minimum time the gRPC connection backoff protocol
+// allows for each connection attempt. It is not enforced by ConnectionBackoff;
+// a Func making connection attempts should give each one at least this long,
+// or its waiting period before the next attempt if that is longer.
+const MinConnectTimeout = 20 * time.Second
+
+// ConnectionBackoff is a rerun.Algorithm implementing the backoff schedule
+// of the gRPC connection backoff protocol (as published in the gRPC
+// repository's doc/connection-backoff.md), allowing custom transports to
+// claim conformance by configuration:
+//
+//	r := rerun.New(n).WithAlgorithm(grpcretry.DefaultConnectionBackoff)
+//
+// The wait before the first retry is exactly BaseDelay. Thereafter, the wait
+// before retry n is BaseDelay·Multiplier^(n-1), capped at MaxDelay, then
+// randomized by up to ±Jitter of its value. As the protocol requires, the
+// schedule is reset following a successful connection since each call to
+// Execute begins it anew.
+type ConnectionBackoff struct {
+	// BaseDelay is the wait before the first retry; the protocol's
+	// INITIAL_BACKOFF.
+	BaseDelay time.Duration
+
+	// Multiplier is the factor by which each wait exceeds the one before it;
+	// the protocol's MULTIPLIER. It cannot be less than 1.
+	Multiplier float64
+
+	// Jitter is the fraction of each wait by which it is randomized; the
+	// protocol's JITTER. It must be from 0 to 1.
+	Jitter float64
+
+	// MaxDelay is the upper bound of each wait (before jitter is applied);
+	// the protocol's MAX_BACKOFF.
+	MaxDelay time.Duration
+
+	// Rand is the source of jitter. If nil, the top-level functions from
+	// math/rand/v2 are used.
+	Rand rerun.Random
+}
+
+// DefaultConnectionBackoff holds the parameters mandated by the gRPC
+// connection backoff protocol.
+var DefaultConnectionBackoff = ConnectionBackoff{
+	BaseDelay:  time.Second,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   120 * time.Second,
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the rerun.Algorithm interface.
+func (cb ConnectionBackoff) OK(uint) error {
+	var errs []error
+
+	invalid := func(field string, value any, err error) {
+		errs = append(errs, &rerun.ValidationError{Field: "ConnectionBackoff." + field, Value: value, Err: err})
+	}
+
+	if cb.BaseDelay < 0 {
+		invalid("BaseDelay", cb.BaseDelay, rerun.ErrNegativeDuration)
+	}
+
+	if cb.Multiplier < 1 || math.IsInf(cb.Multiplier, 0) || math.IsNaN(cb.Multiplier) {
+		invalid("Multiplier", cb.Multiplier, rerun.ErrInvalidMultiplier)
+	}
+
+	if cb.Jitter < 0 || cb.Jitter > 1 || math.IsNaN(cb.Jitter) {
+		invalid("Jitter", cb.Jitter, rerun.ErrInvalidJitter)
+	}
+
+	if cb.MaxDelay < cb.BaseDelay {
+		invalid("MaxDelay", cb.MaxDelay, rerun.ErrConflictingFields)
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// Warmup always returns zero since the protocol makes its first connection
+// attempt immediately.
+// This method contributes to implementing the rerun.Algorithm interface.
+func (ConnectionBackoff) Warmup() time.Duration {
+	return 0
+}
+
+// Wait returns the (jittered) waiting period before retry n.
+// This method contributes to implementing the rerun.Algorithm interface.
+func (cb ConnectionBackoff) Wait(n uint) time.Duration {
+	switch n {
+	case 0:
+		return 0
+	case 1:
+		return cb.BaseDelay
+	}
+
+	d := math.Min(float64(cb.BaseDelay)*math.Pow(cb.Multiplier, float64(n-1)), float64(cb.MaxDelay))
+	d *= 1 + cb.Jitter*(rerun.RandomFloat64(cb.Rand)*2-1)
+
+	return time.Duration(math.Max(d, 0))
+}
+
+func (cb ConnectionBackoff) String() string {
+	return fmt.Sprintf("gRPC connection backoff %v×%g cap %v ±%g%%", cb.BaseDelay, cb.Multiplier, cb.MaxDelay, cb.Jitter*100)
+}
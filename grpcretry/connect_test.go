@@ -0,0 +1,59 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package grpcretry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestConnectionBackoff(t *testing.T) {
+	// The parameters given by doc/connection-backoff.md.
+	spec := ConnectionBackoff{BaseDelay: time.Second, Multiplier: 1.6, Jitter: 0.2, MaxDelay: 120 * time.Second}
+	if DefaultConnectionBackoff != spec {
+		t.Fatalf("DefaultConnectionBackoff == %+v; wanted %+v", DefaultConnectionBackoff, spec)
+	}
+
+	if err := rerun.CheckAlgorithm(DefaultConnectionBackoff, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	unjittered := DefaultConnectionBackoff
+	unjittered.Jitter = 0
+
+	ms := time.Millisecond
+	want := []time.Duration{1000 * ms, 1600 * ms, 2560 * ms, 4096 * ms, 6553600 * time.Microsecond}
+	for i, w := range want {
+		if got := unjittered.Wait(uint(i + 1)); got != w {
+			t.Errorf("Wait(%d) == %v; wanted %v", i+1, got, w)
+		}
+	}
+
+	if got := unjittered.Wait(50); got != 120*time.Second {
+		t.Errorf("Wait(50) == %v; wanted %v", got, 120*time.Second)
+	}
+
+	cb := DefaultConnectionBackoff
+	cb.Rand = rerun.NewRandom(1)
+
+	// The first retry is never jittered; later ones vary by up to ±20%.
+	for range 1000 {
+		if got := cb.Wait(1); got != time.Second {
+			t.Fatalf("Wait(1) == %v; wanted %v", got, time.Second)
+		}
+
+		if got := cb.Wait(50); got < 96*time.Second || got > 144*time.Second {
+			t.Fatalf("Wait(50) == %v; wanted 96s–144s", got)
+		}
+	}
+
+	bad := ConnectionBackoff{BaseDelay: time.Second, Multiplier: 0.5, Jitter: 2}
+	for _, want := range []error{rerun.ErrInvalidMultiplier, rerun.ErrInvalidJitter, rerun.ErrConflictingFields} {
+		if err := bad.OK(2); !errors.Is(err, want) {
+			t.Errorf("OK() == %v; wanted %v", err, want)
+		}
+	}
+}
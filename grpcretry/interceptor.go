@@ -0,0 +1,110 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package grpcretry
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor retrying each
+// call, according to the policy defined by r, whenever it fails with one of
+// the status codes in cs (or DefaultCodes if cs is empty). Unlike gRPC's own
+// retry support (see ServiceConfig), any Algorithm may be used.
+//
+// Each call's Context bounds its retries; an attempt already underway when
+// its deadline passes fails with DEADLINE_EXCEEDED, as usual. When a failure
+// carries a RetryInfo detail, the retry delay it requests replaces the
+// waiting period calculated by r's Algorithm (see rerun.RetryAfter). Should
+// r's iterations be exhausted, the error from the final attempt is returned
+// so that callers may inspect its status as usual.
+func UnaryClientInterceptor(r *rerun.Rerun, cs Codes) grpc.UnaryClientInterceptor {
+	if len(cs) == 0 {
+		cs = DefaultCodes
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var last error
+
+		err := r.WithFunction(func(uint) error {
+			last = invoker(ctx, method, req, reply, cc, opts...)
+			return retryError(ctx, cs, last)
+		}).Execute(ctx)
+
+		return callError(ctx, err, last)
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor retrying the
+// establishment of each stream according to the policy defined by r, as
+// described for UnaryClientInterceptor. Errors occurring once a stream has
+// been established -- including those reported by its first call to RecvMsg
+// -- cannot be retried transparently and are left to the caller.
+func StreamClientInterceptor(r *rerun.Rerun, cs Codes) grpc.StreamClientInterceptor {
+	if len(cs) == 0 {
+		cs = DefaultCodes
+	}
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		var (
+			stream grpc.ClientStream
+			last   error
+		)
+
+		err := r.WithFunction(func(uint) error {
+			stream, last = streamer(ctx, desc, cc, method, opts...)
+			return retryError(ctx, cs, last)
+		}).Execute(ctx)
+
+		if err = callError(ctx, err, last); err != nil {
+			return nil, err
+		}
+
+		return stream, nil
+	}
+}
+
+// retryError returns the error through which an attempt failing with err
+// should be reported to Execute: a retry signal (honoring any RetryInfo
+// detail) if its status code is one of cs, or err itself otherwise.
+func retryError(ctx context.Context, cs Codes, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || !cs.Retryable(st.Code()) {
+		return err
+	}
+
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok && ri.GetRetryDelay() != nil {
+			if delay := ri.GetRetryDelay().AsDuration(); delay >= 0 {
+				return rerun.RetryAfterCause(delay, err)
+			}
+		}
+	}
+
+	return rerun.ErrDoRetry.Wrap(err)
+}
+
+// callError returns the error a call should return given the error returned
+// by Execute and that from the final attempt.
+func callError(ctx context.Context, err, last error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, rerun.ErrAttemptsExhausted):
+		return last
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	default:
+		return err
+	}
+}
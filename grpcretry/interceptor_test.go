@@ -0,0 +1,96 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package grpcretry
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		throttled, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(5 * time.Second),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			start = time.Now()
+			times []time.Duration
+		)
+
+		results := []error{throttled.Err(), status.Error(codes.Unavailable, "down"), nil}
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			times = append(times, time.Since(start))
+			err := results[0]
+			results = results[1:]
+			return err
+		}
+
+		r := rerun.New(3).WithAlgorithm(rerun.FixedDelay(time.Second))
+		ui := UnaryClientInterceptor(r, nil)
+
+		if err := ui(context.Background(), "/svc/Method", nil, nil, nil, invoker); err != nil {
+			t.Fatalf("interceptor returned %v", err)
+		}
+
+		if want := []time.Duration{0, 5 * time.Second, 6 * time.Second}; len(times) != 3 || times[1] != want[1] || times[2] != want[2] {
+			t.Errorf("attempts made at %v; wanted %v", times, want)
+		}
+
+		// Exhaustion returns the final status.
+		results = []error{status.Error(codes.Unavailable, "down"), status.Error(codes.Unavailable, "still down"), status.Error(codes.Unavailable, "gone")}
+		if err := ui(context.Background(), "/svc/Method", nil, nil, nil, invoker); status.Code(err) != codes.Unavailable || status.Convert(err).Message() != "gone" {
+			t.Errorf("interceptor returned %v; wanted final UNAVAILABLE status", err)
+		}
+
+		// Codes not given are not retried.
+		results = []error{status.Error(codes.NotFound, "missing")}
+		if err := ui(context.Background(), "/svc/Method", nil, nil, nil, invoker); status.Code(err) != codes.NotFound || len(results) != 0 {
+			t.Errorf("interceptor returned %v with %d results unused; wanted NOT_FOUND after 1 attempt", err, len(results))
+		}
+
+		// The call's deadline bounds its retries.
+		results = []error{status.Error(codes.Unavailable, "down"), nil}
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		if err := ui(ctx, "/svc/Method", nil, nil, nil, invoker); status.Code(err) != codes.DeadlineExceeded {
+			t.Errorf("interceptor returned %v; wanted DEADLINE_EXCEEDED", err)
+		}
+	})
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls int
+		streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			if calls++; calls < 3 {
+				return nil, status.Error(codes.Unavailable, "down")
+			}
+			return new(fakeStream), nil
+		}
+
+		si := StreamClientInterceptor(rerun.New(3).WithAlgorithm(rerun.FixedDelay(time.Second)), Codes{codes.Unavailable})
+
+		cs, err := si(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Stream", streamer)
+		if err != nil || cs == nil || calls != 3 {
+			t.Errorf("interceptor returned %v, %v after %d calls; wanted a stream after 3", cs, err, calls)
+		}
+	})
+}
+
+type fakeStream struct {
+	grpc.ClientStream
+}
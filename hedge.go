@@ -0,0 +1,292 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ExecuteHedged is an alternative to Execute which, rather than waiting for
+// each attempt to fail before making the next, makes speculative ("hedged")
+// attempts concurrently. After its warmup period, ExecuteHedged begins the
+// first attempt and then, each time the waiting period calculated by the
+// receiver's Algorithm elapses without any attempt succeeding, begins
+// another alongside those still underway -- until the receiver's iterations
+// have all been started. An attempt failing with an error calling for a retry
+// (see Execute) causes the next attempt to begin immediately instead.
+//
+// ExecuteHedged returns nil as soon as any attempt succeeds. Should an
+// attempt fail with an error calling for no retry, ExecuteHedged returns
+// that error at once. In either case, the Context of every attempt still
+// underway is canceled; only a ContextFunc (see WithContextFunction) can
+// observe this and so abandon its work. Once all attempts have failed, an
+// *AttemptsError is returned, just as for Execute. ExecuteHedged does not
+// wait for canceled attempts to return; each is nevertheless reported to
+// OnAttemptEnd (see Hooks) when it does, from its own goroutine and perhaps
+// after ExecuteHedged has returned.
+//
+// Hedging trades additional load for lower tail latency and so is best
+// reserved for idempotent, read-only operations whose latency varies widely.
+// Since attempts overlap, the Func must be safe for concurrent use.
+//
+// Attempts are reported to the receiver's hooks (see WithNotify), which are
+// called from the goroutine running ExecuteHedged, as for Execute, but for the
+// reports of abandoned attempts described above; OnRetry is called as each
+// hedged attempt begins. RetryAfter hints, Progress errors and attempt
+// timeouts are treated as plain retry signals, as are panics when the receiver
+// is configured using WithRetryOnPanic (though without counting them against
+// its limit). The receiver's NestingPolicy, deadline checks (see
+// WithDeadlineWarning and WithStrictDeadline), time scaling and WithoutSleep
+// are honored as for Execute. Waiting periods are obtained just as Execute
+// obtains them (see AlgorithmCtx and AlgorithmErr), though each is
+// calculated as the attempt before it begins rather than after that attempt
+// fails. A negative waiting period, or an error from WaitErr, begins no
+// hedged attempt and, should a failure call for the attempt it precedes,
+// causes ExecuteHedged to return ErrNegativeDuration or that error. Retry budgets, cost limits and
+// WithMaxElapsed are not consulted, since hedged attempts are not made in
+// response to failures. If panic recovery is disabled (see
+// WithoutPanicRecovery), a panicking Func crashes the program. ExecuteHedged
+// returns ErrUnlimited for a receiver created with Forever.
+func (r Rerun) ExecuteHedged(ctx context.Context) (err error) {
+	var (
+		last error      // the most recent error returned by the Func
+		exec *execution // the execution's state, once begun
+		made uint       // the iteration number of the most recent result
+		why  Reason     // the reason for the final decision (see Hooks)
+	)
+
+	r.stats.execution()
+
+	defer func() {
+		select {
+		default:
+		case <-ctx.Done():
+			err, why = r.contextError(ctx, last), ReasonContextDone
+		}
+
+		if exec != nil {
+			r.hooks.decide(ctx, Decision{Iteration: made, Reason: why, Err: err})
+			r.hooks.giveUp(ctx, err)
+		}
+	}()
+
+	switch {
+	case r.function == nil:
+		return ErrNoFunction
+	case r.iterations < 2:
+		return ErrTooFewIterations
+	case r.unlimited():
+		return ErrUnlimited
+	}
+
+	if err = r.Err(); err != nil {
+		return err
+	}
+
+	if r.iterations, err = r.nesting.iterations(ctx, r.iterations); err != nil {
+		return err
+	}
+
+	if m, ok := MaxAttemptsFromContext(ctx); ok {
+		r.iterations = min(r.iterations, m)
+	}
+
+	if err = r.checkDeadline(ctx); err != nil {
+		return err
+	}
+
+	// n.b. r is a copy so this affects only the current execution.
+	r.algorithm = reset(r.algorithm)
+	r.hooks = r.hooks.join(r.logHooks()).join(r.metricsHooks())
+	r.function = r.wrap(r.function)
+
+	clk := r.clk()
+	started := clk.Now()
+
+	ctx, exec = r.withExecution(ctx, started)
+	exec.register()
+	defer exec.unregister()
+
+	warmup := r.warmup(ctx, started)
+	r.hooks.warmup(ctx, warmup)
+	if err = r.pause(ctx, clk, nil, warmup); err != nil {
+		why = ReasonWaitError
+		return err
+	}
+
+	h := &hedger{r: &r, clk: clk, started: started, results: make(chan hedgeResult)}
+
+	hctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		timer    Timer
+		upcoming time.Duration // the waiting period before the next attempt
+		waitErr  error         // why no waiting period could be calculated
+		failures AttemptsError
+	)
+
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	// next begins the next attempt, after the given waiting period, and
+	// arms the timer for the one to follow it (if any).
+	next := func(wait time.Duration) {
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+
+		if h.launched > 0 {
+			r.hooks.retry(ctx, h.launched, last, wait)
+		}
+
+		exec.attempting(h.launched)
+		h.launch(hctx, wait)
+
+		if h.launched < r.iterations {
+			a := Attempt{
+				Iteration:  h.launched,
+				Iterations: r.iterations,
+				Started:    started,
+				Last:       last,
+				r:          &r,
+			}
+
+			// n.b. A waiting period that is negative (or could not be
+			// calculated) arms no timer; it is instead rejected should a
+			// failure call for the next attempt.
+			if upcoming, waitErr = r.waitContext(ctx, a); waitErr == nil && upcoming < 0 {
+				waitErr = ErrNegativeDuration
+			}
+
+			if waitErr == nil {
+				timer = clk.NewTimer(r.delay(upcoming))
+			}
+		}
+	}
+
+	next(warmup)
+
+	for h.pending > 0 {
+		var fire <-chan time.Time
+		if timer != nil {
+			fire = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-fire:
+			timer = nil
+			next(upcoming)
+
+		case res := <-h.results:
+			h.pending--
+
+			if end := r.hooks.OnAttemptEnd; end != nil {
+				end(res.ctx, res.ar)
+			}
+
+			exec.attempted(res.ar.Err)
+			err, last, made = res.ar.Err, res.ar.Err, res.ar.Iteration
+
+			var perm *PermanentError
+
+			switch {
+			case err == nil:
+				r.coldStart.succeeded(clk.Now())
+				why = ReasonSuccess
+				return nil
+
+			case errors.As(err, &perm):
+				why = ReasonPermanent
+				return perm.Err
+
+			case res.panicked && !r.retryPanics:
+				why = ReasonPanic
+				return err
+
+			case IsRetrySignal(err),
+				res.timedOut,
+				res.panicked,
+				r.retryIf != nil && r.retryIf(err):
+				failures.failed(res.ar)
+				if h.launched < r.iterations {
+					if waitErr != nil {
+						why = ReasonWaitError
+						return waitErr
+					}
+					next(0)
+				}
+
+			default:
+				why = ReasonNonRetryable
+				return err
+			}
+		}
+	}
+
+	r.stats.exhaustion()
+	why = ReasonExhausted
+
+	return &failures
+}
+
+// hedger launches the concurrent attempts of ExecuteHedged.
+type hedger struct {
+	r       *Rerun
+	clk     Clock
+	started time.Time
+
+	launched uint // the number of attempts begun
+	pending  uint // the number of attempts yet to report their results
+
+	results chan hedgeResult
+}
+
+// hedgeResult is reported by each attempt made by ExecuteHedged.
+type hedgeResult struct {
+	ctx      context.Context // as given to hooks
+	ar       AttemptReport
+	panicked bool
+	timedOut bool // see WithAttemptTimeout
+}
+
+// launch begins the next attempt in a new goroutine, under a Context derived
+// from ctx, reporting its result unless ctx becomes done first -- in which
+// case the result is instead passed directly to OnAttemptEnd.
+func (h *hedger) launch(ctx context.Context, wait time.Duration) {
+	a := Attempt{Iteration: h.launched, Iterations: h.r.iterations, Started: h.started, Wait: wait, r: h.r}
+	h.launched++
+	h.pending++
+
+	vctx, actx, cancel := h.r.attemptContext(ctx, a)
+
+	h.r.stats.attempt(a.Iteration)
+	h.r.hooks.attempt(vctx, a.Iteration)
+
+	go func() {
+		defer cancel()
+
+		res := hedgeResult{ctx: vctx, ar: AttemptReport{Iteration: a.Iteration, Start: h.clk.Now()}}
+		res.panicked, res.ar.Err = h.r.runFunction(actx, a.Iteration)
+		res.ar.End = h.clk.Now()
+		res.ar.Latency = res.ar.End.Sub(res.ar.Start)
+		res.timedOut = actx.Err() != nil && ctx.Err() == nil
+
+		select {
+		case h.results <- res:
+		case <-ctx.Done():
+			if end := h.r.hooks.OnAttemptEnd; end != nil {
+				end(res.ctx, res.ar)
+			}
+		}
+	}()
+}
@@ -0,0 +1,222 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestExecuteHedged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			canceled []uint
+		)
+
+		// The first attempt hangs, the second fails quickly and the third
+		// succeeds after 500ms; the first is then canceled.
+		r := New(4).WithAlgorithm(Fixed{Start: time.Second, Delay: 2 * time.Second}).WithContextFunction(func(ctx context.Context, i uint) error {
+			d := map[uint]time.Duration{0: time.Hour, 1: 100 * time.Millisecond, 2: 500 * time.Millisecond}[i]
+
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				mu.Lock()
+				canceled = append(canceled, i)
+				mu.Unlock()
+				return ctx.Err()
+			}
+
+			if i == 1 {
+				return ErrDoRetry
+			}
+			return nil
+		})
+
+		start := time.Now()
+		if err := r.ExecuteHedged(context.Background()); err != nil {
+			t.Fatalf("ExecuteHedged() == %v", err)
+		}
+
+		// n.b. 1s warmup, attempt 1 begins 2s later and fails at 3.1s when
+		// attempt 2 begins immediately.
+		if got, want := time.Since(start), 3600*time.Millisecond; got != want {
+			t.Errorf("ExecuteHedged() took %v; wanted %v", got, want)
+		}
+
+		synctest.Wait()
+		if len(canceled) != 1 || canceled[0] != 0 {
+			t.Errorf("canceled attempts == %v; wanted [0]", canceled)
+		}
+
+		if s := r.Stats(); s.Attempts != 3 || s.Exhaustions != 0 {
+			t.Errorf("Stats() == %+v; wanted 3 attempts", s)
+		}
+	})
+}
+
+func TestExecuteHedgedFailure(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errDenied := errors.New("denied")
+
+		var calls int
+		r := New(3).WithAlgorithm(FixedDelay(time.Second)).WithFunction(func(uint) error {
+			calls++
+			time.Sleep(100 * time.Millisecond)
+			return ErrDoRetry
+		})
+
+		err := r.ExecuteHedged(context.Background())
+		if ae := new(AttemptsError); !errors.As(err, &ae) || len(ae.Attempts) != 3 || calls != 3 {
+			t.Errorf("ExecuteHedged() == %v after %d calls; wanted exhaustion after 3", err, calls)
+		}
+
+		start := time.Now()
+		err = r.WithContextFunction(func(ctx context.Context, i uint) error {
+			if i == 0 {
+				<-ctx.Done()
+			}
+			return errDenied
+		}).ExecuteHedged(context.Background())
+		if err != errDenied || time.Since(start) != time.Second {
+			t.Errorf("ExecuteHedged() == %v after %v; wanted %v after 1s", err, time.Since(start), errDenied)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+		err = r.WithContextFunction(func(ctx context.Context, _ uint) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).ExecuteHedged(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) != 2500*time.Millisecond {
+			t.Errorf("ExecuteHedged() == %v after %v; wanted %v after 1.5s", err, time.Since(start)-time.Second, context.DeadlineExceeded)
+		}
+
+		if err := New(Forever).WithFunction(func(uint) error { return nil }).ExecuteHedged(context.Background()); err != ErrUnlimited {
+			t.Errorf("ExecuteHedged() == %v; wanted %v", err, ErrUnlimited)
+		}
+	})
+}
+
+func TestExecuteHedgedAbandoned(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var (
+			mu    sync.Mutex
+			ended []uint
+		)
+
+		// Attempts 0 and 1 hang until canceled once attempt 2 succeeds.
+		r := New(3).
+			WithAlgorithm(FixedDelay(time.Second)).
+			WithNotify(Hooks{OnAttemptEnd: func(_ context.Context, ar AttemptReport) {
+				mu.Lock()
+				ended = append(ended, ar.Iteration)
+				mu.Unlock()
+			}}).
+			WithContextFunction(func(ctx context.Context, i uint) error {
+				if i < 2 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return nil
+			})
+
+		if err := r.ExecuteHedged(context.Background()); err != nil {
+			t.Fatalf("ExecuteHedged() == %v", err)
+		}
+
+		synctest.Wait()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		slices.Sort(ended)
+		if want := []uint{0, 1, 2}; !slices.Equal(ended, want) {
+			t.Errorf("OnAttemptEnd called for %v; wanted %v", ended, want)
+		}
+	})
+}
+
+// badWait is a badDelay whose warmup period is valid.
+type badWait struct{ badDelay }
+
+func (badWait) Warmup() time.Duration { return 0 }
+
+func TestExecuteHedgedPolicies(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		retry := func(uint) error {
+			time.Sleep(100 * time.Millisecond)
+			return ErrDoRetry
+		}
+
+		// WithoutSleep begins every hedged attempt at once.
+		start := time.Now()
+		err := New(3).WithAlgorithm(FixedDelay(time.Hour)).WithoutSleep().WithFunction(retry).ExecuteHedged(context.Background())
+		if !IsExhausted(err) || time.Since(start) != 100*time.Millisecond {
+			t.Errorf("ExecuteHedged() == %v after %v; wanted exhaustion after 100ms", err, time.Since(start))
+		}
+
+		// A negative waiting period is rejected once a failure calls for
+		// the attempt it precedes.
+		err = New(3).WithAlgorithm(badWait{}).WithFunction(retry).ExecuteHedged(context.Background())
+		if err != ErrNegativeDuration {
+			t.Errorf("ExecuteHedged() == %v; wanted %v", err, ErrNegativeDuration)
+		}
+
+		// So too is an error from an AlgorithmErr.
+		var made atomic.Int32
+		err = New(5).WithAlgorithm(blackoutDelay{}).WithFunction(func(i uint) error {
+			made.Add(1)
+			return retry(i)
+		}).ExecuteHedged(context.Background())
+		if err != errBlackout || made.Load() != 3 {
+			t.Errorf("ExecuteHedged() == %v after %d calls; wanted %v after 3", err, made.Load(), errBlackout)
+		}
+
+		// An AlgorithmCtx is given the Context and the Attempt to follow.
+		dctx, dcancel := context.WithTimeout(context.Background(), time.Minute)
+		defer dcancel()
+
+		dd := &deadlineDelay{FixedDelay: FixedDelay(time.Hour)}
+		err = New(3).WithAlgorithm(dd).WithFunction(retry).ExecuteHedged(dctx)
+		if !IsExhausted(err) {
+			t.Errorf("ExecuteHedged() == %v; wanted exhaustion", err)
+		}
+
+		if len(dd.attempts) != 2 || dd.attempts[0].Iteration != 1 || dd.attempts[1].Iteration != 2 {
+			t.Errorf("WaitContext called for %+v; wanted iterations 1 and 2", dd.attempts)
+		}
+
+		// A strict deadline is enforced before any attempt is made.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		var de *DeadlineError
+		err = New(3).WithAlgorithm(FixedDelay(time.Hour)).WithStrictDeadline().WithFunction(retry).ExecuteHedged(ctx)
+		if !errors.As(err, &de) {
+			t.Errorf("ExecuteHedged() == %v; wanted a *DeadlineError", err)
+		}
+
+		// Without WithRetryOnPanic, a panic is not retried even if its value
+		// calls for a retry.
+		var calls int
+		err = New(3).WithAlgorithm(FixedDelay(time.Second)).WithFunction(func(uint) error {
+			calls++
+			panic(ErrDoRetry)
+		}).ExecuteHedged(context.Background())
+
+		var pe *PanicError
+		if !errors.As(err, &pe) || calls != 1 {
+			t.Errorf("ExecuteHedged() == %v after %d calls; wanted a *PanicError after 1", err, calls)
+		}
+	})
+}
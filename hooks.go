@@ -0,0 +1,121 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"time"
+)
+
+// Hooks holds optional callbacks through which Execute reports its progress.
+// Nil fields are ignored. Each hook is called synchronously from the goroutine
+// running Execute and is passed a Context derived from the one given to
+// Execute (which carries the execution's scratchpad; see ScratchKey).
+type Hooks struct {
+	// OnWarmup is called with the warmup period Execute is about to impose
+	// before its first attempt, if any (see Algorithm.Warmup).
+	OnWarmup func(ctx context.Context, warmup time.Duration)
+
+	// OnAttempt is called with the iteration number of each attempt just
+	// before the Func is called. The Attempt being made is available from
+	// the given Context (see AttemptFromContext).
+	OnAttempt func(ctx context.Context, n uint)
+
+	// OnAttemptEnd is called after every call to the Func -- whether it
+	// succeeded, failed or panicked -- making it suitable for releasing any
+	// per-attempt resources such as temporary files, connections or spans.
+	// The given AttemptReport describes the attempt's outcome; if the Func
+	// panicked, its Err is a *PanicError. When panic recovery is disabled
+	// (see WithoutPanicRecovery), OnAttemptEnd is called before the panic
+	// resumes.
+	OnAttemptEnd func(context.Context, AttemptReport)
+
+	// OnDecision is called with an explanation of each decision Execute
+	// makes following an attempt: before waiting for a retry, or before
+	// returning. See Decision.
+	OnDecision func(context.Context, Decision)
+
+	// OnRetry is called before each waiting period preceding a retry with
+	// the iteration number of that retry, the error returned by the attempt
+	// that failed and the computed waiting period (before any time scaling;
+	// see WithTimeScale).
+	OnRetry func(ctx context.Context, n uint, err error, wait time.Duration)
+
+	// OnGiveUp is called with the error Execute is about to return whenever
+	// it fails after having begun an execution -- whatever the reason, be it
+	// a non-retryable error, exhaustion or a done Context -- making it a
+	// natural point for alerting.
+	OnGiveUp func(ctx context.Context, err error)
+}
+
+func (h Hooks) attempt(ctx context.Context, n uint) {
+	if h.OnAttempt != nil {
+		h.OnAttempt(ctx, n)
+	}
+}
+
+func (h Hooks) retry(ctx context.Context, n uint, err error, wait time.Duration) {
+	if h.OnRetry != nil {
+		h.OnRetry(ctx, n, err, wait)
+	}
+}
+
+func (h Hooks) giveUp(ctx context.Context, err error) {
+	if h.OnGiveUp != nil && err != nil {
+		h.OnGiveUp(ctx, err)
+	}
+}
+
+// WithNotify returns a pointer to its receiver after attaching the given
+// Hooks, replacing any attached previously. Hooks installed by other options
+// (such as WithLogger) are unaffected.
+func (r Rerun) WithNotify(h Hooks) *Rerun {
+	r.hooks = h
+	return &r
+}
+
+// join returns Hooks calling each of the receiver's hooks followed by the
+// corresponding hook of o.
+func (h Hooks) join(o Hooks) Hooks {
+	j := Hooks{
+		OnWarmup:     join2(h.OnWarmup, o.OnWarmup),
+		OnAttempt:    join2(h.OnAttempt, o.OnAttempt),
+		OnAttemptEnd: join2(h.OnAttemptEnd, o.OnAttemptEnd),
+		OnDecision:   join2(h.OnDecision, o.OnDecision),
+		OnRetry:      h.OnRetry,
+		OnGiveUp:     join2(h.OnGiveUp, o.OnGiveUp),
+	}
+
+	switch {
+	case h.OnRetry == nil:
+		j.OnRetry = o.OnRetry
+	case o.OnRetry != nil:
+		j.OnRetry = func(ctx context.Context, n uint, err error, wait time.Duration) {
+			h.OnRetry(ctx, n, err, wait)
+			o.OnRetry(ctx, n, err, wait)
+		}
+	}
+
+	return j
+}
+
+// join2 returns a hook calling a then b, or either alone if the other is nil.
+func join2[T any](a, b func(context.Context, T)) func(context.Context, T) {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+
+	return func(ctx context.Context, v T) {
+		a(ctx, v)
+		b(ctx, v)
+	}
+}
+
+func (h Hooks) warmup(ctx context.Context, d time.Duration) {
+	if h.OnWarmup != nil && d > 0 {
+		h.OnWarmup(ctx, d)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestOnAttemptEnd(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var ends []AttemptReport
+	hooks := Hooks{
+		OnAttemptEnd: func(ctx context.Context, ar AttemptReport) {
+			if got := NameFromContext(ctx); got != "cleanup" {
+				t.Errorf("NameFromContext() == %q; wanted %q", got, "cleanup")
+			}
+			ends = append(ends, ar)
+		},
+	}
+
+	r := New(4).WithName("cleanup").WithAlgorithm(FixedDelay(0)).WithNotify(hooks).WithRetryOnPanic(0)
+
+	err := r.WithFunction(func(i uint) error {
+		switch i {
+		case 0:
+			return ErrDoRetry
+		case 1:
+			panic("oops")
+		case 2:
+			return errBoom
+		}
+		return nil
+	}).Execute(context.Background())
+
+	if err != errBoom {
+		t.Errorf("Execute() == %v; wanted %v", err, errBoom)
+	}
+
+	if len(ends) != 3 {
+		t.Fatalf("OnAttemptEnd called %d times; wanted 3", len(ends))
+	}
+
+	var pe *PanicError
+	if ends[0].Err != ErrDoRetry || !errors.As(ends[1].Err, &pe) || ends[2].Err != errBoom {
+		t.Errorf("OnAttemptEnd errors == [%v %v %v]; wanted [%v <panic> %v]",
+			ends[0].Err, ends[1].Err, ends[2].Err, ErrDoRetry, errBoom)
+	}
+
+	for i, ar := range ends {
+		if ar.Iteration != uint(i) {
+			t.Errorf("ends[%d].Iteration == %d; wanted %d", i, ar.Iteration, i)
+		}
+	}
+}
+
+func TestOnAttemptEndWithoutPanicRecovery(t *testing.T) {
+	var called bool
+
+	defer func() {
+		if got := recover(); got != "fatal" {
+			t.Errorf("recovered %v; wanted %q", got, "fatal")
+		}
+
+		if !called {
+			t.Errorf("OnAttemptEnd not called before panic resumed")
+		}
+	}()
+
+	New(3).WithoutPanicRecovery().
+		WithNotify(Hooks{OnAttemptEnd: func(context.Context, AttemptReport) { called = true }}).
+		WithFunction(func(uint) error { panic("fatal") }).
+		Execute(context.Background())
+
+	t.Errorf("Execute() returned normally; wanted a panic")
+}
+
+func TestExecutionID(t *testing.T) {
+	ids := make(map[string]int)
+
+	r := New(3).WithAlgorithm(FixedDelay(0)).
+		WithNotify(Hooks{OnAttemptEnd: func(ctx context.Context, _ AttemptReport) {
+			ids[ExecutionIDFromContext(ctx)]++
+		}}).
+		WithContextFunction(func(ctx context.Context, i uint) error {
+			ids[ExecutionIDFromContext(ctx)]++
+			return ErrDoRetry
+		})
+
+	rp1, _ := r.ExecuteReport(context.Background())
+	rp2, _ := r.ExecuteReport(context.Background())
+
+	if rp1.ExecutionID == "" || rp1.ExecutionID == rp2.ExecutionID {
+		t.Fatalf("ExecutionIDs %q and %q are not unique", rp1.ExecutionID, rp2.ExecutionID)
+	}
+
+	if len(ids) != 2 || ids[rp1.ExecutionID] != 6 || ids[rp2.ExecutionID] != 6 {
+		t.Errorf("IDs seen from Context == %v; wanted 6 each of %q and %q", ids, rp1.ExecutionID, rp2.ExecutionID)
+	}
+
+	if id := ExecutionIDFromContext(context.Background()); id != "" {
+		t.Errorf("ExecutionIDFromContext(Background) == %q; wanted empty", id)
+	}
+}
+
+func TestOnDecision(t *testing.T) {
+	var got []string
+	hooks := Hooks{OnDecision: func(_ context.Context, d Decision) {
+		got = append(got, d.String())
+	}}
+
+	r := New(4).WithAlgorithm(FixedDelay(0)).WithNotify(hooks)
+
+	r.WithFunction(func(i uint) error {
+		switch i {
+		case 0:
+			return ErrDoRetry
+		case 1:
+			return RetryAfter(0)
+		}
+		return nil
+	}).Execute(context.Background())
+
+	r.WithCost(func(Attempt) float64 { return 1 }, 1).
+		WithFunction(func(uint) error { return ErrDoRetry }).
+		Execute(context.Background())
+
+	want := []string{
+		"retrying: reason=retry signal, wait=0s source=algorithm (retry attempt)",
+		"retrying: reason=retry-after hint, wait=0s source=retry-after (retry attempt after 0s)",
+		"succeeded",
+		"giving up: budget exhausted (retry budget exhausted: retry 1 costing 1 would exceed limit of 1 (1 spent))",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("decisions:\n\t%s\nwanted:\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
+
+func TestRetryHooks(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var got []string
+		hooks := Hooks{
+			OnAttempt: func(ctx context.Context, n uint) {
+				if a, ok := AttemptFromContext(ctx); !ok || a.Iteration != n {
+					t.Errorf("OnAttempt(%d) called without its Attempt", n)
+				}
+				got = append(got, fmt.Sprintf("attempt %d", n))
+			},
+			OnRetry: func(_ context.Context, n uint, err error, wait time.Duration) {
+				got = append(got, fmt.Sprintf("retry %d after %v (%v)", n, wait, err))
+			},
+			OnGiveUp: func(_ context.Context, err error) {
+				got = append(got, fmt.Sprintf("give up (%v)", err))
+			},
+		}
+
+		errFlaky := errors.New("flaky")
+		r := New(3).WithAlgorithm(LinearDelay{Base: time.Second, SlopeDuration: time.Second}).WithNotify(hooks)
+
+		r.WithFunction(func(i uint) error {
+			if i == 0 {
+				return ErrDoRetry.Wrap(errFlaky)
+			}
+			return nil
+		}).Execute(context.Background())
+
+		r.WithFunction(func(uint) error { return ErrDoRetry }).Execute(context.Background())
+
+		want := []string{
+			"attempt 0",
+			"retry 1 after 1s (retry attempt: flaky)",
+			"attempt 1",
+			"attempt 0",
+			"retry 1 after 1s (retry attempt)",
+			"attempt 1",
+			"retry 2 after 2s (retry attempt)",
+			"attempt 2",
+			"give up (all attempts exhausted (3 attempts))",
+		}
+
+		if !slices.Equal(got, want) {
+			t.Errorf("hooks:\n\t%s\nwanted:\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+		}
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package httpretry
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// StatusError describes a response deemed retryable by a Transport. It is
+// the cause recorded for each such attempt (see rerun.RetryAfterCause).
+type StatusError struct {
+	// StatusCode is the response's status code (e.g. 503).
+	StatusCode int
+
+	// Status is the response's status (e.g. "503 Service Unavailable").
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("retryable HTTP status %d", e.StatusCode)
+	}
+	return "retryable HTTP status " + e.Status
+}
+
+// RetryAfter returns the waiting period requested by the Retry-After header
+// of resp (as of now), and false if it has none or it cannot be parsed. See
+// ParseRetryAfter.
+func RetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	return ParseRetryAfter(resp.Header.Get("Retry-After"), now)
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, which may take
+// the form of either a number of seconds (e.g. "120") or an HTTP-date (e.g.
+// "Fri, 31 Dec 1999 23:59:59 GMT"), and returns the waiting period it
+// requests as of now. A date in the past requests no wait at all. False is
+// returned if value is empty or cannot be parsed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.ParseUint(value, 10, 63); err == nil {
+		// n.b. Saturate rather than overflow on absurd values.
+		if secs > uint64(time.Duration(1<<63-1)/time.Second) {
+			return 1<<63 - 1, true
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(t.Sub(now), 0), true
+}
+
+// retryError returns the error by which a Transport requests a retry after
+// the retryable response resp: a rerun.RetryAfterError when resp carries a
+// usable Retry-After header, which replaces the waiting period calculated by
+// the Transport's Algorithm, or a plain retry signal otherwise.
+func retryError(resp *http.Response) error {
+	err := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	if d, ok := RetryAfter(resp, time.Now()); ok {
+		return rerun.RetryAfterCause(d, err)
+	}
+	return rerun.ErrDoRetry.Wrap(err)
+}
@@ -0,0 +1,110 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{" 5 ", 5 * time.Second, true},
+		{"99999999999999999", 1<<63 - 1, true},
+		{"-1", 0, false},
+		{"1.5", 0, false},
+		{"soon", 0, false},
+		{"Fri, 01 Mar 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Friday, 01-Mar-24 12:01:00 GMT", time.Minute, true},
+		{"Fri Mar  1 12:00:10 2024", 10 * time.Second, true},
+		{"Fri, 01 Mar 2024 11:00:00 GMT", 0, true},
+	}
+
+	for _, tc := range tests {
+		if got, ok := ParseRetryAfter(tc.value, now); got != tc.want || ok != tc.ok {
+			t.Errorf("ParseRetryAfter(%q) == %v, %t; wanted %v, %t", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransportRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var (
+			start = time.Now()
+			times []time.Duration
+			n     int
+		)
+
+		base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			times = append(times, time.Since(start))
+
+			resp := &http.Response{StatusCode: 200, Status: "200 OK", Header: make(http.Header), Body: http.NoBody}
+			switch n++; n {
+			case 1:
+				resp.StatusCode, resp.Status = 429, "429 Too Many Requests"
+				resp.Header.Set("Retry-After", "7")
+			case 2:
+				resp.StatusCode, resp.Status = 503, "503 Service Unavailable"
+				resp.Header.Set("Retry-After", time.Now().Add(20*time.Second).UTC().Format(http.TimeFormat))
+			case 3:
+				resp.StatusCode, resp.Status = 503, "503 Service Unavailable"
+				resp.Header.Set("Retry-After", "whenever")
+			}
+			return resp, nil
+		})
+
+		var causes []error
+		r := rerun.New(5).WithAlgorithm(rerun.FixedDelay(time.Second)).WithNotify(rerun.Hooks{
+			OnRetry: func(_ context.Context, _ uint, err error, _ time.Duration) { causes = append(causes, err) },
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+		resp, err := (&Transport{Base: base, Rerun: r}).RoundTrip(req)
+		if err != nil || resp.StatusCode != 200 {
+			t.Fatalf("RoundTrip() == %v, %v; wanted 200, <nil>", resp, err)
+		}
+
+		want := []time.Duration{0, 7 * time.Second, 27 * time.Second, 28 * time.Second}
+		if len(times) != len(want) {
+			t.Fatalf("requests made at %v; wanted %v", times, want)
+		}
+		for i := range want {
+			if times[i] != want[i] {
+				t.Errorf("requests made at %v; wanted %v", times, want)
+				break
+			}
+		}
+
+		var se *StatusError
+		if len(causes) != 3 || !errors.As(causes[0], &se) || se.StatusCode != 429 {
+			t.Errorf("retry causes == %v; wanted 3 beginning with a 429 *StatusError", causes)
+		}
+		if got := causes[2].Error(); !strings.Contains(got, "503 Service Unavailable") {
+			t.Errorf("retry cause == %q; wanted it to mention the status", got)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package httpretry provides an http.RoundTripper retrying failed requests
+// according to a rerun.Rerun policy:
+//
+//	client := &http.Client{
+//		Transport: &httpretry.Transport{Rerun: rerun.New(5).WithAlgorithm(algo)},
+//	}
+//
+// Only requests that may safely be sent more than once are retried; see
+// Transport for details.
+package httpretry
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// maxDrain is the most that will be read from the body of a response that is
+// to be retried in order to allow its connection to be reused; larger bodies
+// are simply closed.
+const maxDrain = 64 << 10
+
+// DefaultRerun is the policy used by a Transport having none of its own: up
+// to 4 attempts with exponential backoff (100ms, 200ms, 400ms) and full
+// jitter.
+var DefaultRerun = rerun.New(4).WithAlgorithm(rerun.WithJitter(rerun.ExponentialDelay{
+	Base:       100 * time.Millisecond,
+	Multiplier: 2,
+	Max:        5 * time.Second,
+}, rerun.FullJitter))
+
+// DefaultRetryable is the response classifier used by a Transport having
+// none of its own. It reports whether resp has a status of 429 (Too Many
+// Requests) or any 5xx.
+func DefaultRetryable(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// Transport is an http.RoundTripper that sends each request using Base,
+// retrying according to Rerun whenever the request fails with a transport
+// error or its response is deemed retryable. A retryable response carrying
+// a Retry-After header is retried after the waiting period it requests (see
+// RetryAfter) rather than that calculated by the Algorithm of Rerun, whose
+// hint smoothing (see rerun.Rerun.WithHintSmoothing) may be used to bound
+// it.
+//
+// Before each retry, the body of the previous response is drained and
+// closed so that its connection may be reused. When retries are exhausted by
+// a retryable response, that response is returned (with a nil error) just
+// as if no retries were made.
+//
+// By default, a request is only retried if it is idempotent -- its method is
+// GET, HEAD, OPTIONS, TRACE, PUT or DELETE, or it carries an Idempotency-Key
+// or X-Idempotency-Key header (as for http.Transport) -- and its body (if
+// any) can be rewound using its GetBody field, which http.NewRequest sets
+// for common body types. Any other request is sent exactly once unless
+// RetryUnsafe is set.
+//
+// A Transport is safe for concurrent use provided its Rerun (including its
+// Algorithm) is, too.
+type Transport struct {
+	// Base is the RoundTripper used to make each attempt. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Rerun is the retry policy. Its Func is disregarded and each execution
+	// uses the Context of the request being sent. If nil, DefaultRerun is
+	// used.
+	Rerun *rerun.Rerun
+
+	// Retryable reports whether a response calls for a retry. If nil,
+	// DefaultRetryable is used.
+	Retryable func(*http.Response) bool
+
+	// RetryUnsafe permits retrying requests that would otherwise be sent
+	// only once; i.e. those that are not idempotent or whose bodies cannot
+	// be rewound. Bodies lacking GetBody are buffered in memory so they may
+	// be resent. Set this only when the server is known to tolerate
+	// duplicate requests.
+	RetryUnsafe bool
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base()
+
+	switch {
+	case t.RetryUnsafe:
+		var err error
+		if req, err = rewindable(req); err != nil {
+			return nil, err
+		}
+	case !idempotent(req) || !canRewind(req):
+		return base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	retryable := t.retryable()
+
+	var resp *http.Response
+
+	err := t.rerun().WithFunction(func(i uint) error {
+		r := req
+		if i > 0 {
+			drain(resp)
+			resp = nil
+
+			var err error
+			if r, err = rewind(req); err != nil {
+				return rerun.Permanent(err)
+			}
+		}
+
+		var err error
+		if resp, err = base.RoundTrip(r); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			return rerun.ErrDoRetry.Wrap(err)
+		}
+
+		if retryable(resp) {
+			return retryError(resp)
+		}
+
+		return nil
+	}).Execute(ctx)
+
+	switch {
+	case err == nil:
+		return resp, nil
+	case resp != nil && rerun.IsExhausted(err):
+		return resp, nil
+	default:
+		drain(resp)
+		return nil, err
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) rerun() *rerun.Rerun {
+	if t.Rerun != nil {
+		return t.Rerun
+	}
+	return DefaultRerun
+}
+
+func (t *Transport) retryable() func(*http.Response) bool {
+	if t.Retryable != nil {
+		return t.Retryable
+	}
+	return DefaultRetryable
+}
+
+// idempotent reports whether req may be sent more than once without ill
+// effect.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	// n.b. This matches the behavior of http.Transport.
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	_, ok := req.Header["X-Idempotency-Key"]
+
+	return ok
+}
+
+// canRewind reports whether the body of req (if any) may be sent again.
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindable returns req if its body (if any) may be sent again or, if not,
+// a copy of req whose body has been buffered in memory.
+func rewindable(req *http.Request) (*http.Request, error) {
+	if canRewind(req) {
+		return req, nil
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	r := req.Clone(req.Context())
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}
+	r.Body, _ = r.GetBody()
+
+	return r, nil
+}
+
+// rewind returns a copy of req, with a fresh body, for use by a retry.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+
+	return r, nil
+}
+
+// drain reads (a bounded amount of) and closes the body of resp, if any, so
+// that its connection may be reused.
+func drain(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.CopyN(io.Discard, resp.Body, maxDrain)
+	resp.Body.Close()
+}
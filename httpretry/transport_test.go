@@ -0,0 +1,184 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package httpretry
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// fakeBody records whether it was read to completion and closed.
+type fakeBody struct {
+	io.Reader
+	drained, closed bool
+}
+
+func (fb *fakeBody) Read(p []byte) (int, error) {
+	n, err := fb.Reader.Read(p)
+	if err == io.EOF {
+		fb.drained = true
+	}
+	return n, err
+}
+
+func (fb *fakeBody) Close() error {
+	fb.closed = true
+	return nil
+}
+
+// fakeTransport responds to each request with the next of its statuses (or
+// with errNetwork for a zero status), recording the bodies of the requests
+// and its responses.
+type fakeTransport struct {
+	statuses []int
+	requests []string
+	bodies   []*fakeBody
+}
+
+var errNetwork = errors.New("connection reset")
+
+func (ft *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		body = string(b)
+	}
+	ft.requests = append(ft.requests, body)
+
+	status := ft.statuses[0]
+	ft.statuses = ft.statuses[1:]
+	if status == 0 {
+		return nil, errNetwork
+	}
+
+	fb := &fakeBody{Reader: strings.NewReader(http.StatusText(status))}
+	ft.bodies = append(ft.bodies, fb)
+
+	return &http.Response{StatusCode: status, Body: fb, Request: req}, nil
+}
+
+func newTransport(statuses ...int) (*Transport, *fakeTransport) {
+	ft := &fakeTransport{statuses: statuses}
+	return &Transport{Base: ft, Rerun: rerun.New(3).WithAlgorithm(rerun.FixedDelay(0))}, ft
+}
+
+func TestTransport(t *testing.T) {
+	tr, ft := newTransport(503, 0, 200)
+
+	req, _ := http.NewRequest(http.MethodPut, "http://example.com/", strings.NewReader("payload"))
+	resp, err := tr.RoundTrip(req)
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("RoundTrip() == %v, %v; wanted 200, <nil>", resp, err)
+	}
+
+	if got := strings.Join(ft.requests, ","); got != "payload,payload,payload" {
+		t.Errorf("request bodies == %q; wanted the payload 3 times", got)
+	}
+
+	if fb := ft.bodies[0]; !fb.drained || !fb.closed {
+		t.Errorf("retried response drained=%t, closed=%t; wanted both true", fb.drained, fb.closed)
+	}
+	if fb := ft.bodies[1]; fb.closed {
+		t.Error("final response was closed")
+	}
+}
+
+func TestTransportExhausted(t *testing.T) {
+	tr, ft := newTransport(500, 429, 503)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil || resp.StatusCode != 503 {
+		t.Fatalf("RoundTrip() == %v, %v; wanted 503, <nil>", resp, err)
+	}
+
+	if b, _ := io.ReadAll(resp.Body); string(b) != "Service Unavailable" {
+		t.Errorf("final response body == %q; wanted %q", b, "Service Unavailable")
+	}
+
+	if len(ft.requests) != 3 {
+		t.Errorf("made %d requests; wanted 3", len(ft.requests))
+	}
+
+	tr, _ = newTransport(0, 0, 0)
+	if _, err := tr.RoundTrip(req); !errors.Is(err, errNetwork) || !rerun.IsExhausted(err) {
+		t.Errorf("RoundTrip() == %v; wanted exhaustion wrapping %v", err, errNetwork)
+	}
+}
+
+func TestTransportNotRetryable(t *testing.T) {
+	tr, ft := newTransport(404)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if resp, err := tr.RoundTrip(req); err != nil || resp.StatusCode != 404 || len(ft.requests) != 1 {
+		t.Errorf("RoundTrip() == %v, %v after %d requests; wanted 404, <nil> after 1", resp, err, len(ft.requests))
+	}
+
+	tr, ft = newTransport(200)
+	tr.Retryable = func(resp *http.Response) bool { return resp.StatusCode == 404 }
+	if resp, err := tr.RoundTrip(req); err != nil || resp.StatusCode != 200 {
+		t.Errorf("RoundTrip() == %v, %v; wanted 200, <nil>", resp, err)
+	}
+}
+
+func TestTransportUnsafe(t *testing.T) {
+	post := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com/", strings.NewReader("payload"))
+		return req
+	}
+
+	unrewindable := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPut, "http://example.com/", io.NopCloser(strings.NewReader("payload")))
+		return req
+	}
+
+	keyed := func() *http.Request {
+		req := post()
+		req.Header.Set("Idempotency-Key", "abc123")
+		return req
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		unsafe bool
+		want   int
+	}{
+		{"POST", post(), false, 1},
+		{"POST with Idempotency-Key", keyed(), false, 2},
+		{"POST with RetryUnsafe", post(), true, 2},
+		{"body without GetBody", unrewindable(), false, 1},
+		{"body without GetBody with RetryUnsafe", unrewindable(), true, 2},
+	}
+
+	for _, tc := range tests {
+		tr, ft := newTransport(503, 200)
+		tr.RetryUnsafe = tc.unsafe
+
+		resp, err := tr.RoundTrip(tc.req)
+		if err != nil {
+			t.Errorf("%s: RoundTrip() failed: %v", tc.name, err)
+			continue
+		}
+
+		if len(ft.requests) != tc.want {
+			t.Errorf("%s: made %d requests; wanted %d", tc.name, len(ft.requests), tc.want)
+		}
+
+		for i, body := range ft.requests {
+			if body != "payload" {
+				t.Errorf("%s: request %d had body %q; wanted %q", tc.name, i, body, "payload")
+			}
+		}
+
+		if tc.want == 1 && resp.StatusCode != 503 {
+			t.Errorf("%s: got status %d; wanted 503", tc.name, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// JitterMode defines how WithJitter perturbs each waiting period d of the
+// Algorithm it wraps. Use FullJitter, EqualJitter or ProportionalJitter.
+type JitterMode struct {
+	kind     jitterKind
+	fraction float64
+	rng      Random
+}
+
+type jitterKind int
+
+const (
+	fullJitter jitterKind = iota + 1
+	equalJitter
+	proportionalJitter
+)
+
+var (
+	// FullJitter chooses each waiting period uniformly from the range 0 to
+	// d, spreading retries out as much as possible.
+	FullJitter = JitterMode{kind: fullJitter}
+
+	// EqualJitter chooses each waiting period uniformly from the range d/2
+	// to d, retaining half of the wrapped Algorithm's progression.
+	EqualJitter = JitterMode{kind: equalJitter}
+)
+
+// ProportionalJitter returns a JitterMode choosing each waiting period
+// uniformly from the range d ± d·fraction. The fraction must be in the
+// range 0 to 1, inclusive; otherwise, the OK method of the Algorithm
+// returned by WithJitter returns ErrInvalidJitter.
+func ProportionalJitter(fraction float64) JitterMode {
+	return JitterMode{kind: proportionalJitter, fraction: fraction}
+}
+
+// WithRandom returns a copy of the receiver drawing its randomness from rng.
+// If rng is nil, the top-level functions from math/rand/v2 are used.
+func (jm JitterMode) WithRandom(rng Random) JitterMode {
+	jm.rng = rng
+	return jm
+}
+
+func (jm JitterMode) String() string {
+	switch jm.kind {
+	case fullJitter:
+		return "full jitter"
+	case equalJitter:
+		return "equal jitter"
+	case proportionalJitter:
+		return fmt.Sprintf("±%g%% jitter", jm.fraction*100)
+	default:
+		return "no jitter"
+	}
+}
+
+// WithJitter returns an Algorithm perturbing each waiting period of algo
+// according to mode, so that many clients sharing the same configuration
+// don't retry in lockstep. Since the result is itself an Algorithm, it
+// composes with any other. The warmup period of algo is retained as is.
+func WithJitter(algo Algorithm, mode JitterMode) Algorithm {
+	return &jittered{algo: algo, mode: mode}
+}
+
+// jittered is the Algorithm returned by WithJitter.
+type jittered struct {
+	algo Algorithm
+	mode JitterMode
+}
+
+// OK returns an error if algo is nil or its OK method returns an error, or if
+// the receiver's JitterMode is invalid. Since no JitterMode can reduce a
+// waiting period by more than its full value, a valid receiver never
+// produces a negative waiting period. All problems found are reported
+// together (using errors.Join).
+func (j *jittered) OK(n uint) error {
+	var errs []error
+
+	if j.algo == nil {
+		errs = append(errs, ErrNilAlgorithm)
+	} else {
+		errs = append(errs, j.algo.OK(n))
+	}
+
+	switch j.mode.kind {
+	case fullJitter, equalJitter:
+	case proportionalJitter:
+		if f := j.mode.fraction; !(f >= 0 && f <= 1) {
+			errs = append(errs, fieldError("ProportionalJitter", f, ErrInvalidJitter))
+		}
+	default:
+		errs = append(errs, fieldError("JitterMode", j.mode, ErrInvalidJitter))
+	}
+
+	return joinErrors(errs...)
+}
+
+func (j *jittered) Warmup() time.Duration {
+	return j.algo.Warmup()
+}
+
+func (j *jittered) Wait(n uint) time.Duration {
+	d, _ := j.waitUsing(n, plainWait)
+	return d
+}
+
+// WaitContext perturbs the waiting period preceding the given Attempt, as
+// returned by the WaitContext method of the wrapped Algorithm if it is an
+// AlgorithmCtx. This method implements the AlgorithmCtx interface.
+func (j *jittered) WaitContext(ctx context.Context, a Attempt) time.Duration {
+	d, _ := j.waitUsing(a.Iteration, contextWait(ctx, a))
+	return d
+}
+
+// WaitErr perturbs the waiting period preceding retry n, as returned by the
+// WaitErr method of the wrapped Algorithm if it is an AlgorithmErr, or
+// returns the error it reports. This method implements the AlgorithmErr
+// interface.
+func (j *jittered) WaitErr(n uint) (time.Duration, error) {
+	return j.waitUsing(n, errWait)
+}
+
+func (j *jittered) wrapped() []Algorithm {
+	return []Algorithm{j.algo}
+}
+
+func (j *jittered) waitUsing(n uint, wait waitFunc) (time.Duration, error) {
+	w, err := wait(j.algo, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if w <= 0 {
+		return w, nil
+	}
+
+	d := float64(w)
+
+	r := RandomFloat64(j.mode.rng)
+
+	switch j.mode.kind {
+	case fullJitter:
+		d *= r
+	case equalJitter:
+		d = d/2 + d/2*r
+	case proportionalJitter:
+		spread := j.mode.fraction * d
+		d = d - spread + 2*spread*r
+	}
+
+	return saturate(math.Round(d)), nil
+}
+
+// saturate converts d to a Duration, returning the largest possible Duration
+// should d exceed it.
+func saturate(d float64) time.Duration {
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// Deterministic reports whether the wrapped Algorithm is Deterministic and no
+// jitter is actually applied (i.e. a ProportionalJitter of zero).
+func (j *jittered) Deterministic() bool {
+	return j.mode.kind == proportionalJitter && j.mode.fraction == 0 && isDeterministic(j.algo)
+}
+
+// Reset returns a copy of the receiver wrapping a reset copy of its
+// Algorithm. This method implements the Resetter interface.
+func (j *jittered) Reset() Algorithm {
+	return &jittered{algo: reset(j.algo), mode: j.mode}
+}
+
+func (j *jittered) String() string {
+	return fmt.Sprintf("%v with %v", j.algo, j.mode)
+}
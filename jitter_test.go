@@ -0,0 +1,65 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWithJitter(t *testing.T) {
+	base := ExponentialDelay{Base: time.Second, Multiplier: 2, Max: time.Minute}
+	rng := NewRandom(42)
+
+	for _, tc := range []struct {
+		mode   JitterMode
+		lo, hi float64 // bounds as fractions of the wrapped wait
+	}{
+		{FullJitter.WithRandom(rng), 0, 1},
+		{EqualJitter.WithRandom(rng), 0.5, 1},
+		{ProportionalJitter(0.25).WithRandom(rng), 0.75, 1.25},
+	} {
+		algo := WithJitter(base, tc.mode)
+
+		if err := CheckAlgorithm(algo, 10); err != nil {
+			t.Errorf("%v: %v", algo, err)
+		}
+
+		for n := uint(1); n < 10; n++ {
+			d := float64(base.Wait(n))
+			for range 100 {
+				if got := float64(algo.Wait(n)); got < tc.lo*d || got > tc.hi*d {
+					t.Fatalf("%v: Wait(%d) == %v; wanted %v–%v", algo, n, time.Duration(got), time.Duration(tc.lo*d), time.Duration(tc.hi*d))
+				}
+			}
+		}
+	}
+
+	for _, mode := range []JitterMode{ProportionalJitter(1.5), ProportionalJitter(-0.1), {}} {
+		if err := WithJitter(base, mode).OK(10); !errors.Is(err, ErrInvalidJitter) {
+			t.Errorf("OK() with %v == %v; wanted %v", mode, err, ErrInvalidJitter)
+		}
+	}
+
+	if err := WithJitter(nil, FullJitter).OK(10); !errors.Is(err, ErrNilAlgorithm) {
+		t.Errorf("OK() with nil Algorithm == %v; wanted %v", err, ErrNilAlgorithm)
+	}
+
+	if !isDeterministic(WithJitter(base, ProportionalJitter(0))) || isDeterministic(WithJitter(base, FullJitter)) {
+		t.Error("Deterministic() mismatch")
+	}
+}
+
+// highRandom is a Random always returning a value just short of 1.
+type highRandom struct{}
+
+func (highRandom) Float64() float64 { return 1 - 1e-9 }
+
+func TestWithJitterSaturates(t *testing.T) {
+	algo := WithJitter(FixedDelay(math.MaxInt64), ProportionalJitter(0.5).WithRandom(highRandom{}))
+	if got := algo.Wait(1); got != math.MaxInt64 {
+		t.Errorf("Wait(1) == %v; wanted %v", got, time.Duration(math.MaxInt64))
+	}
+}
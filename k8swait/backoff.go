@@ -0,0 +1,199 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package k8swait provides compatibility between rerun and the Backoff type
+// from k8s.io/apimachinery/pkg/util/wait, allowing operators and controllers
+// to keep their existing tuned values when migrating to rerun.
+package k8swait
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+const ErrIncompatibleAlgorithm = rerun.Error("algorithm not expressible as a wait.Backoff")
+
+// Backoff mirrors wait.Backoff field-for-field so that values of either type
+// may be converted directly to the other without this package depending on
+// Kubernetes:
+//
+//	b := k8swait.Backoff(kb)    // kb is a wait.Backoff
+//	kb := wait.Backoff(b)
+//
+// Backoff implements the rerun.Algorithm interface with the same schedule as
+// wait.Backoff.Step: the wait before retry n is Duration·Factor^(n-1), capped
+// at Cap (if positive), with up to Jitter·wait added at random. A zero Factor
+// results in a constant wait.
+//
+// Take note that wait.Backoff stops iterating once the Cap has been reached
+// whereas a Rerun continues to retry (waiting Cap between attempts) until its
+// iterations are exhausted.
+type Backoff struct {
+	Duration time.Duration
+	Factor   float64
+	Jitter   float64
+	Steps    int
+	Cap      time.Duration
+}
+
+// Rerun returns a new Rerun configured for the receiver's Steps using the
+// receiver as its Algorithm.
+func (b Backoff) Rerun() *rerun.Rerun {
+	steps := b.Steps
+	if steps < 0 {
+		steps = 0
+	}
+	return rerun.New(uint(steps)).WithAlgorithm(b)
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the rerun.Algorithm interface.
+func (b Backoff) OK(uint) error {
+	var errs []error
+
+	invalid := func(field string, value any, err error) {
+		errs = append(errs, &rerun.ValidationError{Field: "Backoff." + field, Value: value, Err: err})
+	}
+
+	if b.Duration < 0 {
+		invalid("Duration", b.Duration, rerun.ErrNegativeDuration)
+	}
+
+	if b.Factor < 0 || math.IsInf(b.Factor, 0) || math.IsNaN(b.Factor) {
+		invalid("Factor", b.Factor, rerun.ErrInvalidMultiplier)
+	}
+
+	if b.Jitter < 0 {
+		invalid("Jitter", b.Jitter, rerun.ErrInvalidJitter)
+	}
+
+	if b.Cap < 0 {
+		invalid("Cap", b.Cap, rerun.ErrNegativeDuration)
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// Warmup always returns zero since wait.Backoff has no warmup period.
+// This method contributes to implementing the rerun.Algorithm interface.
+func (Backoff) Warmup() time.Duration {
+	return 0
+}
+
+// Wait returns the (possibly jittered) waiting period before retry n.
+// This method contributes to implementing the rerun.Algorithm interface.
+func (b Backoff) Wait(n uint) time.Duration {
+	return b.wait(n, nil)
+}
+
+// Deterministic reports whether the receiver's waiting periods are free of
+// jitter, allowing a Rerun to precompute them.
+func (b Backoff) Deterministic() bool {
+	return b.Jitter <= 0
+}
+
+// WithRandom returns a RandomBackoff equivalent to the receiver but drawing
+// its jitter from rng.
+func (b Backoff) WithRandom(rng rerun.Random) RandomBackoff {
+	return RandomBackoff{Backoff: b, Rand: rng}
+}
+
+func (b Backoff) wait(n uint, rng rerun.Random) time.Duration {
+	d := b.exponential().Wait(n)
+	if b.Jitter > 0 {
+		d += time.Duration(rerun.RandomFloat64(rng) * b.Jitter * float64(d))
+	}
+	return d
+}
+
+// RandomBackoff is a Backoff whose jitter is drawn from a specific
+// rerun.Random, making its schedule reproducible. Since wait.Backoff has no
+// such field, this is a separate type so that Backoff remains convertible.
+type RandomBackoff struct {
+	Backoff
+	Rand rerun.Random
+}
+
+// Rerun returns a new Rerun configured for the receiver's Steps using the
+// receiver as its Algorithm.
+func (rb RandomBackoff) Rerun() *rerun.Rerun {
+	return rb.Backoff.Rerun().WithAlgorithm(rb)
+}
+
+// Wait returns the (possibly jittered) waiting period before retry n.
+// This method contributes to implementing the rerun.Algorithm interface.
+func (rb RandomBackoff) Wait(n uint) time.Duration {
+	return rb.wait(n, rb.Rand)
+}
+
+func (b Backoff) exponential() rerun.ExponentialDelay {
+	f := b.Factor
+	if f == 0 {
+		f = 1
+	}
+	return rerun.ExponentialDelay{Base: b.Duration, Multiplier: f, Max: b.Cap}
+}
+
+// FromRerun returns the Backoff equivalent to r. This is only possible if
+// r's Algorithm is a Backoff, a FixedDelay, or an ExponentialDelay having no
+// warmup period or MultiplierMax; any other Algorithm results in
+// ErrIncompatibleAlgorithm.
+func FromRerun(r *rerun.Rerun) (Backoff, error) {
+	if err := r.Err(); err != nil {
+		return Backoff{}, err
+	}
+
+	b := Backoff{Steps: int(r.Iterations())}
+
+	switch a := r.Algorithm().(type) {
+	case Backoff:
+		a.Steps = b.Steps
+		return a, nil
+
+	case RandomBackoff:
+		a.Steps = b.Steps
+		return a.Backoff, nil
+
+	case rerun.FixedDelay:
+		b.Duration = time.Duration(a)
+		b.Factor = 1
+
+	case rerun.ExponentialDelay:
+		if a.Start != 0 || !a.Deterministic() {
+			return Backoff{}, ErrIncompatibleAlgorithm
+		}
+		b.Duration = a.Base
+		b.Factor = a.Multiplier
+		b.Cap = a.Max
+
+	default:
+		return Backoff{}, ErrIncompatibleAlgorithm
+	}
+
+	return b, nil
+}
+
+func (b Backoff) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "k8s backoff %v×%g", b.Duration, b.Factor)
+
+	if b.Cap > 0 {
+		fmt.Fprintf(&sb, " cap %v", b.Cap)
+	}
+
+	if b.Jitter > 0 {
+		fmt.Fprintf(&sb, ", jitter %g", b.Jitter)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package k8swait
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestBackoffWait(t *testing.T) {
+	b := Backoff{Duration: 10 * time.Millisecond, Factor: 2, Steps: 6, Cap: 50 * time.Millisecond}
+
+	want := []time.Duration{0, 10, 20, 40, 50, 50}
+	for n, w := range want {
+		if got := b.Wait(uint(n)); got != w*time.Millisecond {
+			t.Errorf("Wait(%d) == %v; wanted %v", n, got, w*time.Millisecond)
+		}
+	}
+}
+
+func TestRandomBackoffReproducible(t *testing.T) {
+	b := Backoff{Duration: time.Second, Factor: 1.5, Jitter: 0.5, Steps: 10}
+
+	rb1 := b.WithRandom(rerun.NewRandom(42))
+	rb2 := b.WithRandom(rerun.NewRandom(42))
+
+	for n := uint(1); n < 10; n++ {
+		w1, w2 := rb1.Wait(n), rb2.Wait(n)
+		if w1 != w2 {
+			t.Errorf("Wait(%d) == %v and %v from identically seeded sources", n, w1, w2)
+		}
+
+		if base := b.exponential().Wait(n); w1 < base || w1 > base+base/2 {
+			t.Errorf("Wait(%d) == %v; wanted value in [%v, %v]", n, w1, base, base+base/2)
+		}
+	}
+}
+
+func TestFromRerun(t *testing.T) {
+	r := rerun.New(5).WithAlgorithm(rerun.ExponentialDelay{Base: time.Second, Multiplier: 2, Max: time.Minute})
+
+	got, err := FromRerun(r)
+	if err != nil {
+		t.Fatalf("FromRerun() returned unexpected error: %v", err)
+	}
+
+	want := Backoff{Duration: time.Second, Factor: 2, Steps: 5, Cap: time.Minute}
+	if got != want {
+		t.Errorf("FromRerun() == %+v; wanted %+v", got, want)
+	}
+}
+
+func TestBackoffSimulation(t *testing.T) {
+	b := Backoff{Duration: time.Second, Factor: 2, Jitter: 0.5, Steps: 5}
+
+	ss, err := rerun.SimulateSchedule(b.WithRandom(rerun.NewRandom(1)), 5, 1000)
+	if err != nil {
+		t.Fatalf("SimulateSchedule() returned unexpected error: %v", err)
+	}
+
+	// Unjittered waits are 1s, 2s, 4s and 8s; jitter adds up to 50% more.
+	if lo, hi := 15*time.Second, 22500*time.Millisecond; ss.Total.Min < lo || ss.Total.Max > hi {
+		t.Errorf("Total ranges from %v to %v; wanted values within [%v, %v]", ss.Total.Min, ss.Total.Max, lo, hi)
+	}
+
+	if !(ss.Total.Min <= ss.Total.P50 && ss.Total.P50 <= ss.Total.P95 && ss.Total.P95 <= ss.Total.P99 && ss.Total.P99 <= ss.Total.Max) {
+		t.Errorf("Total percentiles out of order: %+v", ss.Total)
+	}
+}
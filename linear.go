@@ -2,7 +2,11 @@
 
 package rerun
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // LinearDelay defines a delay Algorithm imposing wait periods along a
 // straight line using that old familiar formula you learned in high
@@ -11,7 +15,7 @@ import "time"
 //   - y is the wait period duration for a given iteration -- or
 //     the return value from the Wait method
 //
-//   - m is the slope of the line defined by the Slope field
+//   - m is the slope of the line defined by the Slope (or SlopeDuration) field
 //
 //   - x is 1 less than the current iteration number passed to Wait
 //
@@ -29,7 +33,8 @@ import "time"
 // used with a Rerun of 5 iterations would be fine -- since the final
 // waiting period would be 25ms. But 7 iterations would cause a problem
 // since the final waiting period then be -25ms and, last I checked,
-// time travel is not possible (yet).
+// time travel is not possible (yet). Unless, that is, ClampToZero is set,
+// in which case those later retries would be made immediately.
 type LinearDelay struct {
 	// Start defines the warmup time Rerun uses before its first call to a Func.
 	// This value may be zero or positive but a negative value will cause the
@@ -47,26 +52,45 @@ type LinearDelay struct {
 	// Wait would result in a negative Duration value.
 	// i.e. It's the "m" in ""y = mx + b".
 	Slope float64
+
+	// SlopeDuration is an alternative to Slope for the common case where
+	// each waiting period should exceed the one before it by a fixed
+	// Duration (e.g. 250ms per retry). Only one of Slope or SlopeDuration
+	// may be non-zero; otherwise, the OK method returns ErrConflictingFields.
+	SlopeDuration time.Duration
+
+	// ClampToZero causes any waiting period below zero to be treated as
+	// zero rather than causing the OK method to fail. This allows for
+	// schedules that decrease down to immediate retries.
+	ClampToZero bool
 }
 
 // OK returns an error if its receiver is il-defined or it defines a line that
-// cannot be used for the given number of iterations.
+// cannot be used for the given number of iterations. All problems found are
+// reported together (using errors.Join).
 // This method contributes to implementing the Algorithm interface.
 func (ld LinearDelay) OK(n uint) error {
+	var errs []error
+
 	if ld.Start < 0 {
-		return ErrNegativeDuration
+		errs = append(errs, fieldError("LinearDelay.Start", ld.Start, ErrNegativeDuration))
 	}
 
-	if ld.Wait(n) >= 0 {
-		return nil
+	if ld.Base < 0 {
+		errs = append(errs, fieldError("LinearDelay.Base", ld.Base, ErrNegativeDuration))
 	}
 
-	for i := uint(1); i < n; i++ {
-		if ld.Wait(i) < 0 {
-			return ErrNegativeDuration
+	if ld.Slope != 0 && ld.SlopeDuration != 0 {
+		errs = append(errs, fieldError("LinearDelay.SlopeDuration", ld.SlopeDuration, ErrConflictingFields))
+	}
+
+	if ld.Base >= 0 && n > 1 {
+		if i, ok := ld.firstNegative(n - 1); ok {
+			errs = append(errs, waitError(i, ld.Wait(i)))
 		}
 	}
-	return nil
+
+	return joinErrors(errs...)
 }
 
 // Warmup returns the  value of the receiver's Warm field in order to satisfy
@@ -83,5 +107,62 @@ func (ld LinearDelay) Wait(n uint) time.Duration {
 		return 0
 	}
 
-	return time.Duration(ld.Slope*(float64(n)-1)) + ld.Base
+	// n.b. Products beyond the range of a Duration -- as may be reached by
+	// an unlimited Rerun (see Forever) -- are saturated rather than left to
+	// overflow.
+	var d time.Duration
+	switch p := ld.slope() * (float64(n) - 1); {
+	case p >= float64(math.MaxInt64)-float64(ld.Base):
+		return math.MaxInt64
+	case p <= float64(math.MinInt64)-float64(ld.Base):
+		d = math.MinInt64
+	default:
+		d = time.Duration(p) + ld.Base
+	}
+
+	if d < 0 && ld.ClampToZero {
+		return 0
+	}
+
+	return d
+}
+
+// firstNegative returns the first iteration, from 1 to last inclusive, for
+// which Wait returns a negative value. Since Wait(1) is the receiver's
+// non-negative Base and successive waits move monotonically along its line,
+// any negative waits form a suffix of that range, the start of which is found
+// by binary search.
+func (ld LinearDelay) firstNegative(last uint) (uint, bool) {
+	if ld.Wait(last) >= 0 {
+		return 0, false
+	}
+
+	// Wait(lo) is always non-negative while Wait(hi) is always negative.
+	lo, hi := uint(1), last
+	for hi-lo > 1 {
+		if mid := lo + (hi-lo)/2; ld.Wait(mid) < 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, true
+}
+
+// slope returns the receiver's slope in nanoseconds per retry, as given by
+// either its Slope or SlopeDuration field.
+func (ld LinearDelay) slope() float64 {
+	if ld.SlopeDuration != 0 {
+		return float64(ld.SlopeDuration)
+	}
+	return ld.Slope
+}
+
+func (ld LinearDelay) String() string {
+	var clamp string
+	if ld.ClampToZero {
+		clamp = " (clamped)"
+	}
+	return fmt.Sprintf("linear %v%+v/retry%s%s", ld.Base, time.Duration(ld.slope()), clamp, warmupString(ld.Start))
 }
@@ -0,0 +1,61 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLinearDelaySlopeDuration(t *testing.T) {
+	ld := LinearDelay{Base: 100 * time.Millisecond, SlopeDuration: 250 * time.Millisecond}
+
+	for i, want := range []time.Duration{0, 100 * time.Millisecond, 350 * time.Millisecond, 600 * time.Millisecond} {
+		if got := ld.Wait(uint(i)); got != want {
+			t.Errorf("Wait(%d) == %v; wanted %v", i, got, want)
+		}
+	}
+
+	ld.Slope = 1
+	if err := ld.OK(4); !errors.Is(err, ErrConflictingFields) {
+		t.Errorf("OK() with Slope and SlopeDuration returned %v; wanted %v", err, ErrConflictingFields)
+	}
+}
+
+func TestLinearDelayClampToZero(t *testing.T) {
+	ld := LinearDelay{Base: 100 * time.Millisecond, SlopeDuration: -40 * time.Millisecond}
+
+	if err := ld.OK(6); !errors.Is(err, ErrNegativeDuration) {
+		t.Errorf("OK() without ClampToZero returned %v; wanted %v", err, ErrNegativeDuration)
+	}
+
+	ld.ClampToZero = true
+	if err := ld.OK(6); err != nil {
+		t.Errorf("OK() with ClampToZero returned %v", err)
+	}
+
+	for i, want := range []time.Duration{0, 100 * time.Millisecond, 60 * time.Millisecond, 20 * time.Millisecond, 0, 0} {
+		if got := ld.Wait(uint(i)); got != want {
+			t.Errorf("Wait(%d) == %v; wanted %v", i, got, want)
+		}
+	}
+}
+
+func TestLinearDelayOKUnlimited(t *testing.T) {
+	ld := LinearDelay{Base: time.Second, SlopeDuration: time.Hour}
+	if err := ld.OK(Forever); err != nil {
+		t.Errorf("OK(Forever) returned %v; wanted nil", err)
+	}
+
+	if got := ld.Wait(Forever); got != math.MaxInt64 {
+		t.Errorf("Wait(Forever) == %v; wanted %v", got, time.Duration(math.MaxInt64))
+	}
+
+	ld.SlopeDuration = -time.Nanosecond // negative from retry 1e9+2
+	want := waitError(1e9+2, -time.Nanosecond)
+	if err := ld.OK(Forever); err == nil || err.Error() != want.Error() {
+		t.Errorf("OK(Forever) returned %v; wanted %v", err, want)
+	}
+}
@@ -3,6 +3,7 @@
 package rerun
 
 import (
+	"fmt"
 	"math"
 	"time"
 )
@@ -25,11 +26,11 @@ import (
 //   - D: Denominator Field
 //
 // Since none of the above fields are of type time.Duration, the Units field
-// should be used to ensure the return value from Wait is interpreted at the
+// must be used to ensure the return value from Wait is interpreted at the
 // intended resolution.
 type LogarithmicDelay struct {
 	Start time.Duration
-	Units delayUnits
+	Units DelayUnits
 
 	Amplifier      float64
 	Coefficient    float64
@@ -38,35 +39,100 @@ type LogarithmicDelay struct {
 	Denominator    float64
 }
 
-// OK checks the validity of the receiver's fields then calculates a wait time
-// for each iteration value from 1 to n inclusive. If any field has an invalid
-// value or if a wait time is calculated to be less than zero, an error is
-// returned.
+// OK checks the validity of the receiver's fields and ensures that a valid,
+// non-negative wait time would be calculated for each iteration value from 1
+// to n-1 inclusive. If any field has an invalid value or any wait time would
+// be invalid, an error is returned. All such problems are reported together
+// (using errors.Join), with only the first invalid wait time included. Since
+// wait times are monotonic, this needn't calculate each one in turn.
 //
 // The field rules for this type are:
 //
 //   - The Start field cannot be less than zero.
 //
-//   - The Amplifier, Coefficient, and Denominator fields cannot be zero but may
-//     be positive or negative. Albeit, negative values are likely to generate
-//     negative wait times, which will also cause an error.
+//   - The Units field must be one of the basic DelayUnits (see
+//     DelayUnits.OK).
+//
+//   - The Amplifier and Coefficient fields cannot be zero but may be positive
+//     or negative. Albeit, negative values are likely to generate negative
+//     wait times, which will also cause an error.
+//
+//   - The Denominator field may be positive or negative; a zero Denominator
+//     is treated as 1.
 //
 //   - The Modifier and VerticalOffset fields may contain any value that do not
 //     result in a negative calculated wait time.
 //
 // OK contributes to implementing the Algorithm interface.
 func (ld LogarithmicDelay) OK(n uint) error {
+	var errs []error
+
 	if ld.Start < 0 {
-		return ErrNegativeDuration
+		errs = append(errs, fieldError("LogarithmicDelay.Start", ld.Start, ErrNegativeDuration))
+	}
+
+	if ld.Units.OK() != nil {
+		errs = append(errs, fieldError("LogarithmicDelay.Units", time.Duration(ld.Units), ErrInvalidUnits))
+	}
+
+	if ld.Amplifier == 0 {
+		errs = append(errs, fieldError("LogarithmicDelay.Amplifier", ld.Amplifier, ErrZeroValue))
 	}
 
-	for i := uint(1); i < n; i++ {
-		if ld.Wait(i) < 0 {
-			return ErrNegativeDuration
+	if ld.Coefficient == 0 {
+		errs = append(errs, fieldError("LogarithmicDelay.Coefficient", ld.Coefficient, ErrZeroValue))
+	}
+
+	if n > 1 {
+		if i, ok := ld.firstInvalid(n - 1); ok {
+			errs = append(errs, waitError(i, ld.Wait(i)))
 		}
 	}
 
-	return nil
+	return joinErrors(errs...)
+}
+
+// invalid reports whether iteration i lies outside the domain of the
+// receiver's log function or results in a negative wait time.
+func (ld LogarithmicDelay) invalid(i uint) bool {
+	return ld.Coefficient*float64(i)+ld.Modifier <= 0 || ld.Wait(i) < 0
+}
+
+// firstInvalid returns the first iteration, from 1 to last inclusive, for
+// which invalid returns true. Since the argument to the log function is
+// linear in the iteration number and the log function is monotonic, each of
+// the conditions checked by invalid holds over either a prefix or a suffix
+// of that range. So, if the first iteration is valid, any invalid iterations
+// form a suffix, the start of which is found by binary search -- making this
+// O(log n) rather than O(n).
+func (ld LogarithmicDelay) firstInvalid(last uint) (uint, bool) {
+	if ld.invalid(1) {
+		return 1, true
+	}
+
+	if !ld.invalid(last) {
+		return 0, false
+	}
+
+	// invalid(lo) is always false while invalid(hi) is always true.
+	lo, hi := uint(1), last
+	for hi-lo > 1 {
+		if mid := lo + (hi-lo)/2; ld.invalid(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, true
+}
+
+// denominator returns the receiver's Denominator, or 1 if it is zero.
+func (ld LogarithmicDelay) denominator() float64 {
+	if ld.Denominator == 0 {
+		return 1
+	}
+	return ld.Denominator
 }
 
 func (ld LogarithmicDelay) Warmup() time.Duration {
@@ -78,6 +144,12 @@ func (ld LogarithmicDelay) Wait(n uint) time.Duration {
 		return 0
 	}
 
-	d := time.Duration(ld.Amplifier*math.Log(ld.Coefficient*float64(n)+ld.Modifier) + ld.VerticalOffset)
+	d := time.Duration((ld.Amplifier*math.Log(ld.Coefficient*float64(n)+ld.Modifier) + ld.VerticalOffset) / ld.denominator())
 	return time.Duration(ld.Units) * d
 }
+
+func (ld LogarithmicDelay) String() string {
+	return fmt.Sprintf("logarithmic (%g·ln(%g·n%+g)%+g)/%g×%v%s",
+		ld.Amplifier, ld.Coefficient, ld.Modifier, ld.VerticalOffset, ld.denominator(),
+		ld.Units, warmupString(ld.Start))
+}
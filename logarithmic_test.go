@@ -3,7 +3,11 @@
 package rerun
 
 import (
+	"errors"
+	"math"
+	"math/rand/v2"
 	"testing"
+	"time"
 )
 
 func TestLogarithmicDelay(t *testing.T) {
@@ -19,3 +23,103 @@ func TestLogarithmicDelay(t *testing.T) {
 		t.Logf("%3d: %v", i, ld.Wait(i))
 	}
 }
+
+func TestLogarithmicDelayDenominator(t *testing.T) {
+	ld := LogarithmicDelay{Units: Millisecond, Amplifier: 300, Coefficient: 20, Modifier: -14, VerticalOffset: -400}
+	halved := ld
+	halved.Denominator = 2
+
+	if err := ld.OK(10); err != nil {
+		t.Errorf("OK(10) with a zero Denominator returned %v; wanted nil", err)
+	}
+
+	for i := uint(1); i < 10; i++ {
+		if got, want := ld.Wait(i), time.Duration(float64(halved.Wait(i))*2); got < want-time.Millisecond || got > want+time.Millisecond {
+			t.Errorf("Wait(%d) == %v with a zero Denominator; wanted ~%v", i, got, want)
+		}
+	}
+}
+
+// bruteFirstInvalid is the test oracle for LogarithmicDelay.firstInvalid; it
+// checks every iteration in turn.
+func bruteFirstInvalid(ld LogarithmicDelay, last uint) (uint, bool) {
+	for i := uint(1); i <= last; i++ {
+		if ld.Coefficient*float64(i)+ld.Modifier <= 0 || ld.Wait(i) < 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func TestLogarithmicDelayFirstInvalid(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	param := func() float64 {
+		if v := math.Round((rng.Float64()*2-1)*400) / 4; v != 0 {
+			return v
+		}
+		return 1
+	}
+
+	for range 5000 {
+		ld := LogarithmicDelay{
+			Units:          Millisecond,
+			Amplifier:      param(),
+			Coefficient:    param(),
+			Modifier:       param() * 4,
+			VerticalOffset: param() * 4,
+			Denominator:    param() / 10,
+		}
+		last := 1 + rng.UintN(300)
+
+		wi, wok := bruteFirstInvalid(ld, last)
+		if gi, gok := ld.firstInvalid(last); gi != wi || gok != wok {
+			t.Fatalf("%v: firstInvalid(%d) == (%d, %t); wanted (%d, %t)", ld, last, gi, gok, wi, wok)
+		}
+	}
+}
+
+func TestLogarithmicDelayOKUnlimited(t *testing.T) {
+	ld := LogarithmicDelay{Units: Millisecond, Amplifier: 300, Coefficient: 20, Modifier: -14}
+	if err := ld.OK(math.MaxUint); err != nil {
+		t.Errorf("OK(MaxUint) returned %v; wanted nil", err)
+	}
+
+	ld.VerticalOffset = -2000 // negative until ln(20x-14) > 6.67
+	want := waitError(1, ld.Wait(1))
+	if err := ld.OK(math.MaxUint); err == nil || err.Error() != want.Error() {
+		t.Errorf("OK(MaxUint) returned %v; wanted %v", err, want)
+	}
+
+	ld.Amplifier, ld.VerticalOffset = -1, 10 // negative once ln(20x-14) > 10
+	wi, _ := bruteFirstInvalid(ld, 10000)
+	want = waitError(wi, ld.Wait(wi))
+	if err := ld.OK(math.MaxUint); err == nil || err.Error() != want.Error() {
+		t.Errorf("OK(MaxUint) returned %v; wanted %v", err, want)
+	}
+}
+
+func TestParseDelayUnits(t *testing.T) {
+	for s, want := range map[string]DelayUnits{
+		"ns": Nanosecond,
+		"µs": Microsecond,
+		"us": Microsecond,
+		"ms": Millisecond,
+		"s":  Second,
+		"m":  Minute,
+		"h":  Hour,
+	} {
+		if got, err := ParseDelayUnits(s); err != nil || got != want {
+			t.Errorf("ParseDelayUnits(%q) == (%v, %v); wanted (%v, nil)", s, got, err, want)
+		}
+	}
+
+	for _, s := range []string{"", "d", "2s", "1m"} {
+		if _, err := ParseDelayUnits(s); !errors.Is(err, ErrInvalidUnits) {
+			t.Errorf("ParseDelayUnits(%q) returned %v; wanted %v", s, err, ErrInvalidUnits)
+		}
+	}
+
+	if err := (LogarithmicDelay{Units: DelayUnits(3 * time.Second), Amplifier: 1, Coefficient: 1}).OK(3); !errors.Is(err, ErrInvalidUnits) {
+		t.Errorf("OK() with invalid Units returned %v; wanted %v", err, ErrInvalidUnits)
+	}
+}
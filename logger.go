@@ -0,0 +1,112 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// WithLogger returns a pointer to its receiver after configuring Execute to
+// emit structured records to l describing the progress of each execution:
+//
+//   - its warmup period (at slog.LevelDebug),
+//   - each attempt, as it begins and ends (at slog.LevelDebug),
+//   - each waiting period computed before a retry (at slog.LevelInfo),
+//   - each panic recovered from the Func (at slog.LevelWarn), and
+//   - its final outcome: success (at slog.LevelDebug) or giving up (at
+//     slog.LevelWarn).
+//
+// Every record carries the execution's ID (see ExecutionIDFromContext), the
+// Rerun's name (if any; see WithName) and the time elapsed since Execute was
+// called; those concerning a single attempt also carry its iteration number.
+// Records are emitted using the Context of the execution so that handlers
+// may extract tracing information from it.
+//
+// The logger is driven by the same plumbing as the Hooks attached using
+// WithNotify and is called alongside (after) them. Passing nil disables
+// logging.
+func (r Rerun) WithLogger(l *slog.Logger) *Rerun {
+	r.logger = l
+	return &r
+}
+
+// logHooks returns the Hooks through which the receiver's logger (if any)
+// is driven.
+func (r Rerun) logHooks() Hooks {
+	if r.logger == nil {
+		return Hooks{}
+	}
+
+	lg := &execLogger{l: r.logger, clk: r.clk()}
+
+	return Hooks{
+		OnWarmup: func(ctx context.Context, d time.Duration) {
+			lg.log(ctx, slog.LevelDebug, "rerun warmup", slog.Duration("wait", d))
+		},
+
+		OnAttempt: func(ctx context.Context, n uint) {
+			lg.log(ctx, slog.LevelDebug, "rerun attempt", slog.Uint64("attempt", uint64(n)))
+		},
+
+		OnAttemptEnd: func(ctx context.Context, ar AttemptReport) {
+			var pe *PanicError
+			if errors.As(ar.Err, &pe) {
+				lg.log(ctx, slog.LevelWarn, "rerun recovered panic",
+					slog.Uint64("attempt", uint64(ar.Iteration)), slog.Any("panic", pe.Value))
+				return
+			}
+
+			attrs := []slog.Attr{slog.Uint64("attempt", uint64(ar.Iteration)), slog.Duration("latency", ar.Latency)}
+			if ar.Err != nil {
+				attrs = append(attrs, slog.Any("error", ar.Err))
+			}
+			lg.log(ctx, slog.LevelDebug, "rerun attempt complete", attrs...)
+		},
+
+		OnRetry: func(ctx context.Context, n uint, err error, wait time.Duration) {
+			lg.log(ctx, slog.LevelInfo, "rerun retrying",
+				slog.Uint64("attempt", uint64(n)), slog.Duration("wait", wait), slog.Any("error", err))
+		},
+
+		OnDecision: func(ctx context.Context, d Decision) {
+			switch {
+			case d.Retry:
+				// n.b. Reported by OnRetry.
+			case d.Err == nil:
+				lg.log(ctx, slog.LevelDebug, "rerun succeeded", slog.Uint64("attempt", uint64(d.Iteration)))
+			default:
+				lg.log(ctx, slog.LevelWarn, "rerun gave up", slog.Uint64("attempt", uint64(d.Iteration)),
+					slog.String("reason", string(d.Reason)), slog.Any("error", d.Err))
+			}
+		},
+	}
+}
+
+// execLogger emits the records described by WithLogger.
+type execLogger struct {
+	l   *slog.Logger
+	clk Clock
+}
+
+// log emits a record, at the given level, with the attributes common to all
+// records (as found in ctx) followed by attrs.
+func (lg *execLogger) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !lg.l.Enabled(ctx, level) {
+		return
+	}
+
+	common := make([]slog.Attr, 0, 3+len(attrs))
+
+	if e := executionFrom(ctx); e != nil {
+		common = append(common, slog.String("execution", e.id))
+		if e.name != "" {
+			common = append(common, slog.String("name", e.name))
+		}
+		common = append(common, slog.Duration("elapsed", lg.clk.Now().Sub(e.runInfo().Started)))
+	}
+
+	lg.l.LogAttrs(ctx, level, msg, append(common, attrs...)...)
+}
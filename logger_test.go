@@ -0,0 +1,81 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			ids = make(map[string]bool)
+		)
+
+		l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				switch a.Key {
+				case slog.TimeKey:
+					return slog.Attr{}
+				case "execution":
+					ids[a.Value.String()] = true
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+
+		var hooked int
+		r := New(3).
+			WithName("payments").
+			WithAlgorithm(Fixed{Start: time.Second, Delay: 2 * time.Second}).
+			WithNotify(Hooks{OnAttempt: func(context.Context, uint) { hooked++ }}).
+			WithLogger(l).
+			WithFunction(func(i uint) error {
+				time.Sleep(100 * time.Millisecond)
+				switch i {
+				case 0:
+					panic("boom")
+				default:
+					return ErrDoRetry.Wrap(errors.New("unavailable"))
+				}
+			}).
+			WithRetryOnPanic(1)
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		want := []string{
+			`level=DEBUG msg="rerun warmup" name=payments elapsed=0s wait=1s`,
+			`level=DEBUG msg="rerun attempt" name=payments elapsed=1s attempt=0`,
+			`level=WARN msg="rerun recovered panic" name=payments elapsed=1.1s attempt=0 panic=boom`,
+			`level=INFO msg="rerun retrying" name=payments elapsed=1.1s attempt=1 wait=2s error="recovered from panic: boom"`,
+			`level=DEBUG msg="rerun attempt" name=payments elapsed=3.1s attempt=1`,
+			`level=DEBUG msg="rerun attempt complete" name=payments elapsed=3.2s attempt=1 latency=100ms error="retry attempt: unavailable"`,
+			`level=INFO msg="rerun retrying" name=payments elapsed=3.2s attempt=2 wait=2s error="retry attempt: unavailable"`,
+			`level=DEBUG msg="rerun attempt" name=payments elapsed=5.2s attempt=2`,
+			`level=DEBUG msg="rerun attempt complete" name=payments elapsed=5.3s attempt=2 latency=100ms error="retry attempt: unavailable"`,
+			`level=WARN msg="rerun gave up" name=payments elapsed=5.3s attempt=2 reason="attempts exhausted" error="all attempts exhausted (3 attempts): retry attempt: unavailable"`,
+		}
+
+		if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("logged:\n\t%s\nwanted:\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+		}
+
+		if len(ids) != 1 || hooked != 3 {
+			t.Errorf("logged %d execution IDs and called OnAttempt %d times; wanted 1 and 3", len(ids), hooked)
+		}
+	})
+}
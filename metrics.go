@@ -0,0 +1,72 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"time"
+)
+
+// MetricsRecorder is implemented by metrics backends to which Execute reports
+// its activity (see WithMetrics). Each method is passed the name of the
+// executing Rerun (see WithName), which is empty if it has none, and is
+// called synchronously from the goroutine running Execute; implementations
+// used by more than one Rerun must be safe for concurrent use.
+//
+// The metrics subpackage provides an implementation backed by Prometheus.
+type MetricsRecorder interface {
+	// Attempt records the end of each call to the Func: its latency and
+	// the error it returned (nil on success, a *PanicError if it panicked).
+	Attempt(ctx context.Context, name string, latency time.Duration, err error)
+
+	// Retry records each retry along with the waiting period that is to
+	// precede it (before any time scaling; see WithTimeScale).
+	Retry(ctx context.Context, name string, wait time.Duration)
+
+	// Outcome records the end of each execution that made at least one
+	// attempt: ReasonSuccess with a nil err when it succeeded or, when it
+	// gave up, the Reason for doing so and the error Execute returns.
+	Outcome(ctx context.Context, name string, reason Reason, err error)
+
+	// Budget records each retry that drew upon the Rerun's RetryBudget (see
+	// WithBudget): whether it was granted a token and the budget's state
+	// afterward, so that its depletion may be alerted upon before retries
+	// begin to fail fast.
+	Budget(ctx context.Context, name string, granted bool, s BudgetStats)
+}
+
+// WithMetrics returns a pointer to its receiver after configuring Execute to
+// report the attempts, retries, budget use and outcome of each execution to
+// m. Like the logger attached by WithLogger, m is (but for its Budget method)
+// driven by the same plumbing as the Hooks attached using WithNotify and is
+// called after them. Passing nil disables metrics.
+func (r Rerun) WithMetrics(m MetricsRecorder) *Rerun {
+	r.metrics = m
+	return &r
+}
+
+// metricsHooks returns the Hooks through which the receiver's MetricsRecorder
+// (if any) is driven.
+func (r Rerun) metricsHooks() Hooks {
+	if r.metrics == nil {
+		return Hooks{}
+	}
+
+	m, name := r.metrics, r.name
+
+	return Hooks{
+		OnAttemptEnd: func(ctx context.Context, ar AttemptReport) {
+			m.Attempt(ctx, name, ar.Latency, ar.Err)
+		},
+
+		OnRetry: func(ctx context.Context, _ uint, _ error, wait time.Duration) {
+			m.Retry(ctx, name, wait)
+		},
+
+		OnDecision: func(ctx context.Context, d Decision) {
+			if !d.Retry {
+				m.Outcome(ctx, name, d.Reason, d.Err)
+			}
+		},
+	}
+}
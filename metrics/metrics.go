@@ -0,0 +1,25 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package metrics provides metrics for rerun executions. Its Prometheus type
+// implements MetricsRecorder, which may be attached to any Rerun using
+// WithMetrics:
+//
+//	p := metrics.NewPrometheus()
+//	prometheus.MustRegister(p)
+//
+//	r := rerun.New(5).WithName("payments").WithMetrics(p)
+//
+// The activity of a RetryBudget or Coordinator, which is shared by many
+// Reruns, is exported separately using NewBudgetCollector or
+// NewCoordinatorCollector.
+//
+// This package lives apart from rerun so that only programs importing it
+// depend upon Prometheus.
+package metrics
+
+import "github.com/olympiclabs/rerun"
+
+// MetricsRecorder is implemented by metrics backends to which Execute reports
+// its activity. It is an alias for rerun.MetricsRecorder, where the full
+// contract is documented.
+type MetricsRecorder = rerun.MetricsRecorder
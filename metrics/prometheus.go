@@ -0,0 +1,235 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// Prometheus is a MetricsRecorder maintaining the following Prometheus
+// metrics, each labeled with the name of the executing Rerun ("name"):
+//
+//   - rerun_attempts_total, a counter of calls made to the Func, further
+//     labeled by their "result": "success", "error" or "panic";
+//   - rerun_retries_total, a counter of retries;
+//   - rerun_give_ups_total, a counter of failed executions, further labeled
+//     by the "reason" they gave up (see rerun.Reason);
+//   - rerun_successes_total, a counter of successful executions;
+//   - rerun_attempt_duration_seconds, a histogram of attempt latencies;
+//   - rerun_wait_seconds, a histogram of the waiting periods preceding
+//     retries;
+//   - rerun_budget_remaining_tokens, a gauge of the tokens left in the
+//     Rerun's RetryBudget (see rerun.Rerun.WithBudget) after its latest
+//     retry; and
+//   - rerun_budget_refused_total, a counter of retries refused for want of
+//     a token.
+//
+// A Prometheus is a prometheus.Collector and must be registered before its
+// metrics are exported. It is safe for concurrent use and may be shared by
+// any number of Reruns.
+type Prometheus struct {
+	attempts  *prometheus.CounterVec
+	retries   *prometheus.CounterVec
+	giveUps   *prometheus.CounterVec
+	successes *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	waits     *prometheus.HistogramVec
+	remaining *prometheus.GaugeVec
+	refused   *prometheus.CounterVec
+}
+
+var _ MetricsRecorder = (*Prometheus)(nil)
+
+// NewPrometheus returns a new Prometheus whose histograms use the default
+// Prometheus buckets (see prometheus.DefBuckets).
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rerun_attempts_total",
+			Help: "Number of calls made to a Rerun's Func.",
+		}, []string{"name", "result"}),
+
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rerun_retries_total",
+			Help: "Number of retries made by a Rerun.",
+		}, []string{"name"}),
+
+		giveUps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rerun_give_ups_total",
+			Help: "Number of executions of a Rerun that failed.",
+		}, []string{"name", "reason"}),
+
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rerun_successes_total",
+			Help: "Number of executions of a Rerun that succeeded.",
+		}, []string{"name"}),
+
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "rerun_attempt_duration_seconds",
+			Help: "Latency of calls made to a Rerun's Func.",
+		}, []string{"name"}),
+
+		waits: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "rerun_wait_seconds",
+			Help: "Waiting periods preceding the retries made by a Rerun.",
+		}, []string{"name"}),
+
+		remaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "rerun_budget_remaining_tokens",
+			Help: "Tokens left in a Rerun's RetryBudget after its latest retry.",
+		}, []string{"name"}),
+
+		refused: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rerun_budget_refused_total",
+			Help: "Number of retries by a Rerun refused by its RetryBudget.",
+		}, []string{"name"}),
+	}
+}
+
+// Attempt implements MetricsRecorder.
+func (p *Prometheus) Attempt(_ context.Context, name string, latency time.Duration, err error) {
+	var pe *rerun.PanicError
+
+	result := "success"
+	switch {
+	case errors.As(err, &pe):
+		result = "panic"
+	case err != nil:
+		result = "error"
+	}
+
+	p.attempts.WithLabelValues(name, result).Inc()
+	p.latency.WithLabelValues(name).Observe(latency.Seconds())
+}
+
+// Retry implements MetricsRecorder.
+func (p *Prometheus) Retry(_ context.Context, name string, wait time.Duration) {
+	p.retries.WithLabelValues(name).Inc()
+	p.waits.WithLabelValues(name).Observe(wait.Seconds())
+}
+
+// Outcome implements MetricsRecorder.
+func (p *Prometheus) Outcome(_ context.Context, name string, reason rerun.Reason, err error) {
+	if err == nil {
+		p.successes.WithLabelValues(name).Inc()
+		return
+	}
+	p.giveUps.WithLabelValues(name, string(reason)).Inc()
+}
+
+// Budget implements MetricsRecorder.
+func (p *Prometheus) Budget(_ context.Context, name string, granted bool, s rerun.BudgetStats) {
+	p.remaining.WithLabelValues(name).Set(s.Tokens)
+	if !granted {
+		p.refused.WithLabelValues(name).Inc()
+	}
+}
+
+func (p *Prometheus) collectors() []prometheus.Collector {
+	return []prometheus.Collector{p.attempts, p.retries, p.giveUps, p.successes, p.latency, p.waits, p.remaining, p.refused}
+}
+
+// Describe implements prometheus.Collector.
+func (p *Prometheus) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range p.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (p *Prometheus) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range p.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// NewBudgetCollector returns a prometheus.Collector exporting the state of b
+// (see rerun.BudgetStats), labeled with the given name, as:
+//
+//   - rerun_budget_tokens, a gauge of the retries currently available;
+//   - rerun_budget_capacity, a gauge of the maximum tokens b may hold;
+//   - rerun_budget_refill_rate, a gauge of the tokens replenished per second;
+//   - rerun_budget_spent_total, a counter of retries that consumed tokens;
+//   - rerun_budget_consumed_tokens_total, a counter of tokens consumed; and
+//   - rerun_budget_rejections_total, a counter of retries refused for want
+//     of a token.
+//
+// Since a RetryBudget is usually shared by many Reruns, it is exported apart
+// from the Prometheus recorder attached to each of them.
+func NewBudgetCollector(name string, b *rerun.RetryBudget) prometheus.Collector {
+	return &statsCollector[rerun.BudgetStats]{
+		stats: b.Stats,
+		metrics: []statsMetric[rerun.BudgetStats]{
+			{budgetDesc("tokens", "Number of retries currently available from a RetryBudget.", name), prometheus.GaugeValue,
+				func(s rerun.BudgetStats) float64 { return s.Tokens }},
+			{budgetDesc("capacity", "Maximum number of tokens a RetryBudget may hold.", name), prometheus.GaugeValue,
+				func(s rerun.BudgetStats) float64 { return s.Capacity }},
+			{budgetDesc("refill_rate", "Number of tokens replenished per second in a RetryBudget.", name), prometheus.GaugeValue,
+				func(s rerun.BudgetStats) float64 { return s.Rate }},
+			{budgetDesc("spent_total", "Number of retries that consumed tokens from a RetryBudget.", name), prometheus.CounterValue,
+				func(s rerun.BudgetStats) float64 { return float64(s.Spent) }},
+			{budgetDesc("consumed_tokens_total", "Number of tokens consumed from a RetryBudget.", name), prometheus.CounterValue,
+				func(s rerun.BudgetStats) float64 { return s.Consumed }},
+			{budgetDesc("rejections_total", "Number of retries refused by a RetryBudget.", name), prometheus.CounterValue,
+				func(s rerun.BudgetStats) float64 { return float64(s.Rejections) }},
+		},
+	}
+}
+
+func budgetDesc(metric, help, name string) *prometheus.Desc {
+	return prometheus.NewDesc("rerun_budget_"+metric, help, nil, prometheus.Labels{"name": name})
+}
+
+// NewCoordinatorCollector returns a prometheus.Collector exporting the
+// activity of c (see rerun.CoordinatorStats), labeled with the given name,
+// as:
+//
+//   - rerun_coordinator_delayed_total, a counter of retries whose waiting
+//     periods were extended; and
+//   - rerun_coordinator_delay_seconds_total, a counter of the additional
+//     waiting time imposed on them.
+func NewCoordinatorCollector(name string, c *rerun.Coordinator) prometheus.Collector {
+	labels := prometheus.Labels{"name": name}
+
+	return &statsCollector[rerun.CoordinatorStats]{
+		stats: c.Stats,
+		metrics: []statsMetric[rerun.CoordinatorStats]{
+			{prometheus.NewDesc("rerun_coordinator_delayed_total", "Number of retries delayed by a Coordinator.", nil, labels), prometheus.CounterValue,
+				func(s rerun.CoordinatorStats) float64 { return float64(s.Delayed) }},
+			{prometheus.NewDesc("rerun_coordinator_delay_seconds_total", "Additional waiting time imposed by a Coordinator.", nil, labels), prometheus.CounterValue,
+				func(s rerun.CoordinatorStats) float64 { return s.Extra.Seconds() }},
+		},
+	}
+}
+
+// statsCollector is a prometheus.Collector exporting metrics derived from a
+// snapshot of type S, taken once per collection.
+type statsCollector[S any] struct {
+	stats   func() S
+	metrics []statsMetric[S]
+}
+
+type statsMetric[S any] struct {
+	desc  *prometheus.Desc
+	typ   prometheus.ValueType
+	value func(S) float64
+}
+
+func (sc *statsCollector[S]) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range sc.metrics {
+		ch <- m.desc
+	}
+}
+
+func (sc *statsCollector[S]) Collect(ch chan<- prometheus.Metric) {
+	s := sc.stats()
+	for _, m := range sc.metrics {
+		ch <- prometheus.MustNewConstMetric(m.desc, m.typ, m.value(s))
+	}
+}
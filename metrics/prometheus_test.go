@@ -0,0 +1,144 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestPrometheus(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		p := NewPrometheus()
+
+		r := rerun.New(3).
+			WithName("payments").
+			WithAlgorithm(rerun.Fixed{Delay: 2 * time.Second}).
+			WithMetrics(p).
+			WithFunction(func(i uint) error {
+				time.Sleep(time.Second)
+				if i == 0 {
+					panic("boom")
+				}
+				return rerun.ErrDoRetry
+			}).
+			WithRetryOnPanic(1)
+
+		if err := r.Execute(context.Background()); !errors.Is(err, rerun.ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, rerun.ErrAttemptsExhausted)
+		}
+
+		if err := r.WithFunction(func(uint) error { return nil }).Execute(context.Background()); err != nil {
+			t.Errorf("Execute() == %v", err)
+		}
+
+		want := `
+# HELP rerun_attempts_total Number of calls made to a Rerun's Func.
+# TYPE rerun_attempts_total counter
+rerun_attempts_total{name="payments",result="error"} 2
+rerun_attempts_total{name="payments",result="panic"} 1
+rerun_attempts_total{name="payments",result="success"} 1
+# HELP rerun_give_ups_total Number of executions of a Rerun that failed.
+# TYPE rerun_give_ups_total counter
+rerun_give_ups_total{name="payments",reason="attempts exhausted"} 1
+# HELP rerun_retries_total Number of retries made by a Rerun.
+# TYPE rerun_retries_total counter
+rerun_retries_total{name="payments"} 2
+# HELP rerun_successes_total Number of executions of a Rerun that succeeded.
+# TYPE rerun_successes_total counter
+rerun_successes_total{name="payments"} 1
+`
+		names := []string{"rerun_attempts_total", "rerun_give_ups_total", "rerun_retries_total", "rerun_successes_total"}
+		if err := testutil.CollectAndCompare(p, strings.NewReader(want), names...); err != nil {
+			t.Error(err)
+		}
+
+		if n := testutil.CollectAndCount(p, "rerun_attempt_duration_seconds", "rerun_wait_seconds"); n != 2 {
+			t.Errorf("collected %d histograms; wanted 2", n)
+		}
+	})
+}
+
+func TestPrometheusBudget(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		p := NewPrometheus()
+		b := rerun.NewRetryBudget(4, time.Hour)
+
+		r := rerun.New(3).
+			WithName("payments").
+			WithAlgorithm(rerun.FixedDelay(0)).
+			WithBudget(b).
+			WithMetrics(p).
+			WithFunction(func(uint) error { return rerun.ErrDoRetry })
+
+		for range 3 {
+			r.Execute(context.Background())
+		}
+
+		want := `
+# HELP rerun_budget_refused_total Number of retries by a Rerun refused by its RetryBudget.
+# TYPE rerun_budget_refused_total counter
+rerun_budget_refused_total{name="payments"} 1
+# HELP rerun_budget_remaining_tokens Tokens left in a Rerun's RetryBudget after its latest retry.
+# TYPE rerun_budget_remaining_tokens gauge
+rerun_budget_remaining_tokens{name="payments"} 0
+`
+		names := []string{"rerun_budget_refused_total", "rerun_budget_remaining_tokens"}
+		if err := testutil.CollectAndCompare(p, strings.NewReader(want), names...); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestBudgetCollector(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := rerun.NewRetryBudget(4, time.Hour)
+		r := rerun.New(3).WithAlgorithm(rerun.FixedDelay(0)).WithBudget(b).WithFunction(func(uint) error {
+			return rerun.ErrDoRetry
+		})
+
+		for range 3 {
+			r.Execute(context.Background())
+		}
+
+		want := `
+# HELP rerun_budget_capacity Maximum number of tokens a RetryBudget may hold.
+# TYPE rerun_budget_capacity gauge
+rerun_budget_capacity{name="shared"} 4
+# HELP rerun_budget_rejections_total Number of retries refused by a RetryBudget.
+# TYPE rerun_budget_rejections_total counter
+rerun_budget_rejections_total{name="shared"} 1
+# HELP rerun_budget_spent_total Number of retries that consumed tokens from a RetryBudget.
+# TYPE rerun_budget_spent_total counter
+rerun_budget_spent_total{name="shared"} 4
+# HELP rerun_budget_tokens Number of retries currently available from a RetryBudget.
+# TYPE rerun_budget_tokens gauge
+rerun_budget_tokens{name="shared"} 0
+`
+		names := []string{"rerun_budget_capacity", "rerun_budget_rejections_total", "rerun_budget_spent_total", "rerun_budget_tokens"}
+		if err := testutil.CollectAndCompare(NewBudgetCollector("shared", b), strings.NewReader(want), names...); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestCoordinatorCollector(t *testing.T) {
+	c := rerun.NewCoordinator(time.Second, 1)
+
+	want := `
+# HELP rerun_coordinator_delayed_total Number of retries delayed by a Coordinator.
+# TYPE rerun_coordinator_delayed_total counter
+rerun_coordinator_delayed_total{name="fleet"} 0
+`
+	if err := testutil.CollectAndCompare(NewCoordinatorCollector("fleet", c), strings.NewReader(want), "rerun_coordinator_delayed_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+type testRecorder []string
+
+func (tr *testRecorder) Attempt(_ context.Context, name string, latency time.Duration, err error) {
+	*tr = append(*tr, fmt.Sprintf("%s: attempt latency=%v err=%v", name, latency, err))
+}
+
+func (tr *testRecorder) Retry(_ context.Context, name string, wait time.Duration) {
+	*tr = append(*tr, fmt.Sprintf("%s: retry wait=%v", name, wait))
+}
+
+func (tr *testRecorder) Outcome(_ context.Context, name string, reason Reason, err error) {
+	*tr = append(*tr, fmt.Sprintf("%s: outcome reason=%s err=%v", name, reason, err))
+}
+
+func (tr *testRecorder) Budget(_ context.Context, name string, granted bool, s BudgetStats) {
+	*tr = append(*tr, fmt.Sprintf("%s: budget granted=%t tokens=%v rejections=%d", name, granted, s.Tokens, s.Rejections))
+}
+
+func TestWithMetrics(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errDenied := errors.New("denied")
+
+		var tr testRecorder
+		r := New(3).
+			WithName("auth").
+			WithAlgorithm(Fixed{Delay: 2 * time.Second}).
+			WithMetrics(&tr).
+			WithFunction(func(i uint) error {
+				time.Sleep(100 * time.Millisecond)
+				switch i {
+				case 0:
+					return ErrDoRetry
+				default:
+					return errDenied
+				}
+			})
+
+		if err := r.Execute(context.Background()); err != errDenied {
+			t.Errorf("Execute() == %v; wanted %v", err, errDenied)
+		}
+
+		if err := r.WithFunction(func(uint) error { return nil }).Execute(context.Background()); err != nil {
+			t.Errorf("Execute() == %v", err)
+		}
+
+		want := []string{
+			"auth: attempt latency=100ms err=retry attempt",
+			"auth: retry wait=2s",
+			"auth: attempt latency=100ms err=denied",
+			"auth: outcome reason=non-retryable error err=denied",
+			"auth: attempt latency=0s err=<nil>",
+			"auth: outcome reason=success err=<nil>",
+		}
+		if !slices.Equal(tr, want) {
+			t.Errorf("recorded:\n\t%q\nwanted:\n\t%q", tr, want)
+		}
+
+		tr = nil
+		if err := r.WithMetrics(nil).Execute(context.Background()); err != errDenied {
+			t.Errorf("Execute() == %v; wanted %v", err, errDenied)
+		}
+		if len(tr) != 0 {
+			t.Errorf("recorded %q after WithMetrics(nil)", tr)
+		}
+	})
+}
+
+func TestWithMetricsBudget(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var tr testRecorder
+		r := New(3).
+			WithName("auth").
+			WithAlgorithm(FixedDelay(0)).
+			WithBudget(NewRetryBudget(1, time.Hour)).
+			WithMetrics(&tr).
+			WithFunction(func(uint) error { return ErrDoRetry })
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrBudgetExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrBudgetExhausted)
+		}
+
+		want := []string{
+			"auth: attempt latency=0s err=retry attempt",
+			"auth: budget granted=true tokens=0 rejections=0",
+			"auth: retry wait=0s",
+			"auth: attempt latency=0s err=retry attempt",
+			"auth: budget granted=false tokens=0 rejections=1",
+			"auth: outcome reason=budget exhausted err=retry budget exhausted",
+		}
+		if !slices.Equal(tr, want) {
+			t.Errorf("recorded:\n\t%q\nwanted:\n\t%q", tr, want)
+		}
+	})
+}
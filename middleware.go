@@ -0,0 +1,39 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+// Middleware wraps the ContextFunc called by Execute for each attempt,
+// allowing cross-cutting concerns -- such as tracing, per-attempt metrics or
+// rate limiting -- to be layered around every attempt without altering the
+// Func itself. The ContextFunc returned is called in place of next, which it
+// should call in turn; the Context it is given carries the current Attempt
+// (see AttemptFromContext).
+//
+// A Middleware wraps the Func alone: waiting periods are not included, while
+// panics raised by the Func pass through it (before being recovered by
+// Execute, unless WithoutPanicRecovery is in effect), so a Middleware that
+// must observe them should use a deferred function.
+type Middleware func(next ContextFunc) ContextFunc
+
+// WithMiddleware returns a pointer to its receiver after adding the given
+// Middleware to those wrapping the receiver's Func. The first Middleware
+// given (to the first call) is the outermost. Passing no Middleware removes
+// all of those added previously.
+func (r Rerun) WithMiddleware(mw ...Middleware) *Rerun {
+	if len(mw) == 0 {
+		r.middleware = nil
+	} else {
+		// n.b. The receiver's slice must not be appended to in place since
+		// it may be shared with other Reruns.
+		r.middleware = append(r.middleware[:len(r.middleware):len(r.middleware)], mw...)
+	}
+	return &r
+}
+
+// wrap returns fn wrapped by all of the receiver's Middleware.
+func (r Rerun) wrap(fn ContextFunc) ContextFunc {
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		fn = r.middleware[i](fn)
+	}
+	return fn
+}
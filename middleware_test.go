@@ -0,0 +1,68 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var got []string
+
+		trace := func(name string) Middleware {
+			return func(next ContextFunc) ContextFunc {
+				return func(ctx context.Context, i uint) error {
+					a, _ := AttemptFromContext(ctx)
+					got = append(got, fmt.Sprintf("%s(%d) after %v", name, i, a.Wait))
+					defer func() { got = append(got, fmt.Sprintf("/%s(%d)", name, i)) }()
+					return next(ctx, i)
+				}
+			}
+		}
+
+		base := New(2).WithAlgorithm(Fixed{Start: time.Second, Delay: 2 * time.Second}).WithMiddleware(trace("outer"))
+		r := base.WithMiddleware(trace("inner")).WithFunction(func(i uint) error {
+			got = append(got, fmt.Sprintf("func(%d)", i))
+			if i == 0 {
+				panic("boom")
+			}
+			return nil
+		}).WithRetryOnPanic(0)
+
+		if err := r.Execute(context.Background()); err != nil {
+			t.Errorf("Execute() == %v", err)
+		}
+
+		want := []string{
+			"outer(0) after 1s", "inner(0) after 1s", "func(0)", "/inner(0)", "/outer(0)",
+			"outer(1) after 2s", "inner(1) after 2s", "func(1)", "/inner(1)", "/outer(1)",
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("calls == %q; wanted %q", got, want)
+		}
+
+		got = nil
+		if err := base.WithMiddleware(trace("other")).WithFunction(func(uint) error { return nil }).Execute(context.Background()); err != nil {
+			t.Errorf("Execute() == %v", err)
+		}
+
+		if want := []string{"outer(0) after 1s", "other(0) after 1s", "/other(0)", "/outer(0)"}; !slices.Equal(got, want) {
+			t.Errorf("calls == %q; wanted %q", got, want)
+		}
+
+		got = nil
+		errNope := errors.New("nope")
+		if err := r.WithMiddleware().WithFunction(func(uint) error { return errNope }).Execute(context.Background()); err != errNope || got != nil {
+			t.Errorf("Execute() == %v calling %q; wanted %v calling nothing", err, got, errNope)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import "context"
+
+// NestingPolicy defines how Execute behaves when called from within the Func
+// of another, enclosing call to Execute. Retrying inside of a retry multiplies
+// the attempts made by each level -- three levels of 5 iterations may call
+// the innermost Func 125 times -- which can quickly turn a small outage into
+// a catastrophic load spike.
+//
+// Nesting is detected through the Context given to Execute, so it is only
+// recognized if the enclosing Func is a ContextFunc that passes its Context
+// along (see WithContextFunction). The zero value allows nesting without
+// restriction.
+type NestingPolicy struct {
+	// Disallow causes a nested Execute to return ErrNestedExecute without
+	// calling its Func.
+	Disallow bool
+
+	// MaxAttempts, if non-zero, limits a nested Execute to at most this
+	// many attempts.
+	MaxAttempts uint
+
+	// MaxCombined, if non-zero, limits the combined number of attempts
+	// across a nested Execute and all of those enclosing it (i.e. the
+	// product of their iterations) by reducing the attempts made by the
+	// nested Execute. A nested Execute always makes at least one attempt.
+	MaxCombined uint
+}
+
+// WithNestingPolicy returns a pointer to its receiver after configuring np to
+// govern calls to Execute made within the Func of another Execute.
+func (r Rerun) WithNestingPolicy(np NestingPolicy) *Rerun {
+	r.nesting = np
+	return &r
+}
+
+// iterations returns the number of iterations that an Execute called with
+// ctx should make in place of n, or ErrNestedExecute if it should make none.
+func (np NestingPolicy) iterations(ctx context.Context, n uint) (uint, error) {
+	outer := executionFrom(ctx)
+	if outer == nil {
+		return n, nil
+	}
+
+	if np.Disallow {
+		return 0, ErrNestedExecute
+	}
+
+	if np.MaxAttempts != 0 && n > np.MaxAttempts {
+		n = np.MaxAttempts
+	}
+
+	if np.MaxCombined != 0 {
+		n = min(n, max(np.MaxCombined/outer.combined, 1))
+	}
+
+	return n, nil
+}
@@ -0,0 +1,96 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNestingPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		np    NestingPolicy
+		calls int
+		err   error
+	}{
+		{"allow", NestingPolicy{}, 3 * 4, ErrAttemptsExhausted},
+		{"disallow", NestingPolicy{Disallow: true}, 0, ErrNestedExecute},
+		{"max-attempts", NestingPolicy{MaxAttempts: 2}, 3 * 2, ErrAttemptsExhausted},
+		{"max-combined", NestingPolicy{MaxCombined: 7}, 3 * 2, ErrAttemptsExhausted},
+		{"max-combined-floor", NestingPolicy{MaxCombined: 2}, 3 * 1, ErrAttemptsExhausted},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+
+			inner := New(4).WithAlgorithm(FixedDelay(0)).WithNestingPolicy(tc.np).
+				WithFunction(func(uint) error {
+					calls++
+					return ErrDoRetry
+				})
+
+			outer := New(3).WithAlgorithm(FixedDelay(0)).
+				WithContextFunction(func(ctx context.Context, _ uint) error {
+					if err := inner.Execute(ctx); !errors.Is(err, tc.err) {
+						t.Errorf("inner Execute() == %v; wanted %v", err, tc.err)
+					}
+					return ErrDoRetry
+				})
+
+			if err := outer.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+				t.Errorf("outer Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+			}
+
+			if calls != tc.calls {
+				t.Errorf("inner Func called %d times; wanted %d", calls, tc.calls)
+			}
+		})
+	}
+
+	if err := New(2).WithNestingPolicy(NestingPolicy{Disallow: true}).
+		WithAlgorithm(FixedDelay(0)).
+		WithFunction(func(uint) error { return nil }).
+		Execute(context.Background()); err != nil {
+		t.Errorf("top-level Execute() == %v; wanted nil", err)
+	}
+}
+
+func TestNestedGiveUp(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		inner *Rerun
+		err   error
+	}{
+		{"attempts", New(2).WithAlgorithm(FixedDelay(0)), ErrDoRetry},
+		{"retry-after", New(2).WithAlgorithm(FixedDelay(0)), RetryAfter(0)},
+		{"elapsed", New(2).WithAlgorithm(FixedDelay(time.Second)).WithMaxElapsed(time.Millisecond), ErrDoRetry},
+		{"cost", New(2).WithAlgorithm(FixedDelay(0)).WithCost(func(Attempt) float64 { return 2 }, 3), ErrDoRetry},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+
+			inner := tc.inner.WithFunction(func(uint) error { return tc.err })
+
+			outer := New(3).WithAlgorithm(FixedDelay(0)).
+				WithContextFunction(func(ctx context.Context, _ uint) error {
+					calls++
+					return inner.Execute(ctx)
+				})
+
+			err := outer.Execute(context.Background())
+			if !IsExhausted(err) {
+				t.Errorf("outer Execute() == %v; wanted an exhaustion error", err)
+			}
+
+			if IsRetrySignal(err) {
+				t.Errorf("IsRetrySignal(%v) == true; wanted false", err)
+			}
+
+			if calls != 1 {
+				t.Errorf("outer Func called %d times; wanted 1", calls)
+			}
+		})
+	}
+}
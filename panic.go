@@ -0,0 +1,57 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import "fmt"
+
+// PanicError is returned by Execute when a panic caused by its Func has been
+// recovered. It carries the value passed to panic along with the stack trace
+// of the panicking goroutine, and may be retrieved using errors.As.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace captured when the panic was recovered, as
+	// formatted by runtime/debug.Stack.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, or nil otherwise.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// WithRetryOnPanic returns a pointer to its receiver after configuring it to
+// treat a panic recovered from its Func as a retryable failure -- just as if
+// the Func had returned ErrDoRetry -- rather than terminating Execute. This
+// is intended for wrapping third-party code known to panic transiently.
+//
+// At most maxPanics panics will be retried during a single call to Execute;
+// the next panic will cause Execute to return its *PanicError. This limit is
+// separate from the receiver's iterations so that a Func which panics on
+// every call fails fast -- after maxPanics+1 attempts -- rather than
+// consuming the full attempt budget. Panics count toward the limit whether
+// or not they are consecutive. A maxPanics of zero imposes no limit beyond
+// the receiver's configured iterations.
+func (r Rerun) WithRetryOnPanic(maxPanics uint) *Rerun {
+	r.retryPanics = true
+	r.maxPanics = maxPanics
+	return &r
+}
+
+// WithoutPanicRecovery returns a pointer to its receiver after disabling the
+// recovery of panics caused by its Func; any such panic will propagate out of
+// Execute normally. This is meant for services relying on crash-and-restart
+// supervision that consider swallowing panics harmful. When in effect, this
+// option supersedes WithRetryOnPanic.
+func (r Rerun) WithoutPanicRecovery() *Rerun {
+	r.noRecover = true
+	return &r
+}
@@ -0,0 +1,109 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestExecutePanic(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	err := New(3).WithFunction(func(uint) error { panic(errBoom) }).Execute(context.Background())
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Execute() returned %v; wanted a *PanicError", err)
+	}
+
+	if !errors.Is(err, errBoom) {
+		t.Errorf("errors.Is(%v, %v) == false; wanted true", err, errBoom)
+	}
+
+	if !bytes.Contains(pe.Stack, []byte("TestExecutePanic")) {
+		t.Errorf("PanicError.Stack does not mention the panicking function:\n%s", pe.Stack)
+	}
+}
+
+func TestExecutePanicRetrySignal(t *testing.T) {
+	var calls int
+	err := New(3).WithFunction(func(uint) error {
+		calls++
+		panic(ErrDoRetry)
+	}).Execute(context.Background())
+
+	if !errors.As(err, new(*PanicError)) {
+		t.Errorf("Execute() returned %v; wanted a *PanicError", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Func called %d times; wanted 1", calls)
+	}
+}
+
+func TestExecuteRetryOnPanic(t *testing.T) {
+	panicky := func(n uint) Func {
+		return func(i uint) error {
+			if i < n {
+				panic("transient")
+			}
+			return nil
+		}
+	}
+
+	r := New(5).WithAlgorithm(FixedDelay(0)).WithRetryOnPanic(2)
+
+	if err := r.WithFunction(panicky(2)).Execute(context.Background()); err != nil {
+		t.Errorf("Execute() with 2 panics returned unexpected error: %v", err)
+	}
+
+	var pe *PanicError
+	if err := r.WithFunction(panicky(3)).Execute(context.Background()); !errors.As(err, &pe) {
+		t.Errorf("Execute() with 3 panics returned %v; wanted a *PanicError", err)
+	}
+
+	if err := r.WithRetryOnPanic(0).WithFunction(panicky(5)).Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+		t.Errorf("Execute() with unlimited panics returned %v; wanted %v", err, ErrAttemptsExhausted)
+	}
+}
+
+func TestExecuteRetryOnPanicFailsFast(t *testing.T) {
+	var calls int
+
+	rp, err := New(10).WithAlgorithm(FixedDelay(0)).WithRetryOnPanic(2).
+		WithFunction(func(i uint) error {
+			calls++
+			if i%2 == 0 {
+				panic("deterministic")
+			}
+			return ErrDoRetry
+		}).
+		ExecuteReport(context.Background())
+
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Value != "deterministic" {
+		t.Errorf("ExecuteReport() returned %v; wanted a *PanicError", err)
+	}
+
+	// Panics at iterations 0, 2 and 4; the third exceeds the limit.
+	if calls != 5 || len(rp.Attempts) != 5 {
+		t.Errorf("Func called %d times (%d reported); wanted 5", calls, len(rp.Attempts))
+	}
+}
+
+func TestExecuteWithoutPanicRecovery(t *testing.T) {
+	defer func() {
+		if got := recover(); got != "fatal" {
+			t.Errorf("recovered %v; wanted %q", got, "fatal")
+		}
+	}()
+
+	New(3).WithoutPanicRecovery().WithRetryOnPanic(0).
+		WithFunction(func(uint) error { panic("fatal") }).
+		Execute(context.Background())
+
+	t.Errorf("Execute() returned normally; wanted a panic")
+}
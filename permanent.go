@@ -0,0 +1,29 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+// Permanent returns an error that a Func may return to stop Execute from
+// retrying, whatever err may be; Execute then returns err itself, unwrapped,
+// immediately. This allows a Func to insist on giving up even when a retry
+// predicate (see WithRetryIf) would otherwise classify err as retryable, or
+// when err wraps ErrDoRetry. If err is nil, Permanent returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// PermanentError is the error type returned by Permanent.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error given to Permanent.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,50 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/synctest"
+)
+
+func TestPermanent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errDenied := errors.New("access denied")
+
+		var reason Reason
+		r := New(5).
+			WithRetryIf(func(error) bool { return true }).
+			WithNotify(Hooks{OnDecision: func(_ context.Context, d Decision) { reason = d.Reason }})
+
+		for _, perm := range []error{
+			Permanent(errDenied),
+			fmt.Errorf("fetching: %w", Permanent(errDenied)),
+			Permanent(ErrDoRetry.Wrap(errDenied)),
+		} {
+			var calls int
+			err := r.WithFunction(func(i uint) error {
+				if calls++; i < 2 {
+					return errDenied
+				}
+				return perm
+			}).Execute(context.Background())
+
+			if !errors.Is(err, errDenied) || IsPermanent(err) || calls != 3 {
+				t.Errorf("Execute() == %v after %d calls; wanted %v after 3", err, calls, errDenied)
+			}
+
+			if reason != ReasonPermanent {
+				t.Errorf("Decision.Reason == %q; wanted %q", reason, ReasonPermanent)
+			}
+		}
+
+		if err := Permanent(nil); err != nil {
+			t.Errorf("Permanent(nil) == %v; wanted <nil>", err)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"slices"
+	"time"
+)
+
+// Deterministic is an optional interface that may be implemented by an
+// Algorithm to declare whether its Wait method always returns the same value
+// for any given iteration number. If Deterministic returns true, Rerun
+// calculates all of its waiting periods once, when the Algorithm is attached,
+// and Execute simply indexes into that precomputed schedule thereafter.
+//
+// Algorithms employing randomness (or otherwise depending on state beyond
+// their own fields) must not implement this interface or must return false.
+// The Algorithms provided by this package are Deterministic unless configured
+// with randomness (such as Fixed with a JitterFraction, ExponentialDelay
+// with a MultiplierMax, or WithJitter) or random by nature (such as
+// DecorrelatedJitter and WeibullDelay).
+type Deterministic interface {
+	Deterministic() bool
+}
+
+// maxPrecomputed is the largest number of iterations for which a schedule
+// will be precomputed. Reruns with more iterations than this call their
+// Algorithm's Wait method for every retry.
+const maxPrecomputed = 1024
+
+// precompute returns the waiting periods algo imposes before each of n-1
+// retries -- such that the value at index i-1 is the wait before retry i --
+// or nil if algo is not Deterministic, is invalid, or n is out of range.
+func precompute(algo Algorithm, n uint) []time.Duration {
+	if !isDeterministic(algo) {
+		return nil
+	}
+
+	if n < 2 || n > maxPrecomputed || algo.OK(n) != nil {
+		return nil
+	}
+
+	waits := make([]time.Duration, n-1)
+	for i := range waits {
+		waits[i] = algo.Wait(uint(i + 1))
+	}
+
+	return waits
+}
+
+// isDeterministic reports whether algo implements Deterministic and declares
+// itself to be so.
+func isDeterministic(algo Algorithm) bool {
+	d, ok := algo.(Deterministic)
+	return ok && d.Deterministic()
+}
+
+// Waits returns the waiting periods the receiver imposes before each retry,
+// as precomputed from a Deterministic Algorithm, such that the value at index
+// i-1 is the wait preceding retry i. Waits returns nil if no schedule was
+// precomputed. The returned slice is a copy and may be freely modified.
+func (r Rerun) Waits() []time.Duration {
+	return slices.Clone(r.waits)
+}
+
+// wait returns the waiting period preceding retry i, preferring the
+// receiver's precomputed schedule (if any) over calling its Algorithm.
+func (r Rerun) wait(i uint) time.Duration {
+	if i > 0 && int(i) <= len(r.waits) {
+		return r.waits[i-1]
+	}
+	return r.algorithm.Wait(i)
+}
+
+// Each of the Algorithms provided by this package, unless configured with
+// randomness, is Deterministic.
+
+func (FixedDelay) Deterministic() bool       { return true }
+func (LinearDelay) Deterministic() bool      { return true }
+func (LogarithmicDelay) Deterministic() bool { return true }
@@ -0,0 +1,74 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// countingDelay is a Deterministic LinearDelay that counts calls to Wait.
+type countingDelay struct {
+	LinearDelay
+	calls *int
+}
+
+func (cd countingDelay) Wait(n uint) time.Duration {
+	*cd.calls++
+	return cd.LinearDelay.Wait(n)
+}
+
+func TestPrecomputedWaits(t *testing.T) {
+	ld := LinearDelay{Base: 100 * time.Millisecond, Slope: float64(50 * time.Millisecond)}
+	want := []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 200 * time.Millisecond}
+
+	if got := New(4).WithAlgorithm(ld).Waits(); !slices.Equal(got, want) {
+		t.Errorf("Waits() == %v; wanted %v", got, want)
+	}
+
+	if got := New(3).Waits(); !slices.Equal(got, []time.Duration{time.Second, time.Second}) {
+		t.Errorf("New(3).Waits() == %v; wanted [1s 1s]", got)
+	}
+
+	for name, r := range map[string]*Rerun{
+		"invalid":  New(4).WithAlgorithm(FixedDelay(-1)),
+		"nil":      New(4).WithAlgorithm(nil),
+		"too-many": New(maxPrecomputed + 1).WithAlgorithm(ld),
+		"too-few":  New(1).WithAlgorithm(ld),
+		"opaque":   New(4).WithAlgorithm(struct{ Algorithm }{ld}),
+	} {
+		if got := r.Waits(); got != nil {
+			t.Errorf("%s: Waits() == %v; wanted nil", name, got)
+		}
+	}
+}
+
+func TestPrecomputedExecute(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls int
+		cd := countingDelay{LinearDelay{Base: time.Second, Slope: float64(time.Second)}, &calls}
+
+		r := New(4).WithAlgorithm(cd)
+		precomputed := calls
+
+		start := time.Now()
+		err := r.WithFunction(func(uint) error { return ErrDoRetry }).Execute(context.Background())
+		if !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if precomputed != 3 || calls != precomputed {
+			t.Errorf("Wait called %d times (%d precomputed); wanted 3 (all precomputed)", calls, precomputed)
+		}
+
+		if got, want := time.Since(start), 6*time.Second; got != want {
+			t.Errorf("Execute took %v; wanted %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import "fmt"
+
+// Progress returns an error that a Func may return to report that, although
+// it has not yet succeeded, it made meaningful progress -- e.g. a multi-step
+// operation completed some of its steps before failing. Execute treats this
+// as a retry signal but first resets its progression, as though the attempt
+// reporting progress had been its first: the full number of iterations is
+// once again available and the next waiting period is that calculated by
+// the Algorithm for iteration 1. Long operations are then not penalized for
+// failures early on. The error err (which may be nil) is the cause of the
+// failure. If err is (or wraps) an error returned by RetryAfter, its wait
+// period is honored as well.
+//
+// Take note that iteration numbers passed to the Func also begin anew and
+// that a Func reporting progress indefinitely keeps Execute retrying just as
+// long, so a deadline or RetryBudget should be used to bound the total time
+// spent.
+func Progress(err error) error {
+	return &ProgressError{Err: err}
+}
+
+// ProgressError is the error type returned by Progress.
+type ProgressError struct {
+	Err error
+}
+
+func (e *ProgressError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v after progress", ErrDoRetry)
+	}
+	return fmt.Sprintf("%v after progress: %v", ErrDoRetry, e.Err)
+}
+
+// Unwrap returns the cause given to Progress.
+func (e *ProgressError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrDoRetry, which allows a *ProgressError to
+// be recognized as a retry signal by errors.Is.
+func (e *ProgressError) Is(target error) bool {
+	return target == ErrDoRetry
+}
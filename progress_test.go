@@ -0,0 +1,62 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var (
+			iters []uint
+			at    []time.Duration
+			calls int
+		)
+
+		start := time.Now()
+		r := New(3).
+			WithAlgorithm(LinearDelay{Base: time.Second, SlopeDuration: time.Second}).
+			WithFunction(func(i uint) error {
+				iters = append(iters, i)
+				at = append(at, time.Since(start))
+
+				switch calls++; calls {
+				case 2:
+					return Progress(errors.New("step 1 complete"))
+				case 4:
+					return Progress(RetryAfter(time.Minute))
+				default:
+					return ErrDoRetry
+				}
+			})
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() returned %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		s := time.Second
+		wantIters := []uint{0, 1, 1, 2, 1, 2}
+		wantAt := []time.Duration{0, 1 * s, 2 * s, 4 * s, 64 * s, 66 * s}
+
+		if !slices.Equal(iters, wantIters) {
+			t.Errorf("Func called with iterations %v; wanted %v", iters, wantIters)
+		}
+
+		if !slices.Equal(at, wantAt) {
+			t.Errorf("Func called at %v; wanted %v", at, wantAt)
+		}
+	})
+
+	err := Progress(context.Canceled)
+	if !IsRetrySignal(err) || !errors.Is(err, context.Canceled) {
+		t.Errorf("%v is not a retry signal wrapping %v", err, context.Canceled)
+	}
+}
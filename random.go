@@ -0,0 +1,59 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// Random is the source of randomness used by randomized Algorithms (such as
+// those applying jitter). Each randomized Algorithm provided by this package
+// (or its sub-packages) accepts a Random so that tests and simulations may
+// reproduce a schedule exactly.
+//
+// Since an Algorithm may be shared by many concurrent calls to Execute,
+// implementations must be safe for concurrent use. Note that a *rand.Rand is
+// not; use NewRandom or LockedRandom to obtain one that is.
+type Random interface {
+	// Float64 returns a pseudo-random number in the half-open interval
+	// [0.0,1.0).
+	Float64() float64
+}
+
+// NewRandom returns a Random, safe for concurrent use, that deterministically
+// produces the same sequence of values for a given seed.
+func NewRandom(seed uint64) Random {
+	return LockedRandom(rand.New(rand.NewPCG(seed, seed)))
+}
+
+// LockedRandom returns a Random that serializes all access to r, making it
+// safe for concurrent use.
+func LockedRandom(r *rand.Rand) Random {
+	return &lockedRandom{r: r}
+}
+
+type lockedRandom struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (lr *lockedRandom) Float64() float64 {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.r.Float64()
+}
+
+// RandomFloat64 returns a pseudo-random number in the half-open interval
+// [0.0,1.0) drawn from r. If r is nil, the top-level Float64 function from
+// math/rand/v2 is used instead; it is safe for concurrent use and, unlike the
+// global source from math/rand, does not contend on a shared lock.
+//
+// RandomFloat64 is intended for use by randomized Algorithm implementations
+// exposing an optional Random.
+func RandomFloat64(r Random) float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+	return r.Float64()
+}
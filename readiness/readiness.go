@@ -0,0 +1,129 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package readiness provides helpers for waiting until a dependency (such as
+// a database or another service) is ready for use, polling according to a
+// rerun.Rerun policy -- e.g. "wait for Postgres before starting".
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// ErrNotReady is returned (wrapping the last failure observed) when a
+// dependency did not become ready within the Rerun policy's iterations.
+const ErrNotReady = rerun.Error("dependency not ready")
+
+// checkTimeout bounds each connection attempt made by WaitForTCP and each
+// request made by WaitForHTTP, lest a dependency that accepts connections but
+// never responds stall the wait indefinitely.
+const checkTimeout = 10 * time.Second
+
+// WaitForFunc repeatedly calls check, according to the policy defined by r,
+// until it returns nil. Each call is passed the Context of its attempt, which
+// carries any attempt timeout (see rerun.Rerun.WithAttemptTimeout). If ctx
+// becomes done first, its cause is returned. If check returns an error
+// wrapped by rerun.Permanent, waiting stops at once and the wrapped error is
+// returned. If r's iterations are exhausted, the returned error wraps both
+// ErrNotReady and the last error returned by check.
+func WaitForFunc(ctx context.Context, r *rerun.Rerun, check func(context.Context) error) error {
+	var (
+		mu   sync.Mutex // n.b. abandoned attempts may yet return
+		last error
+	)
+
+	err := r.WithContextFunction(func(ctx context.Context, _ uint) error {
+		err := check(ctx)
+
+		var perm *rerun.PermanentError
+		if err == nil || errors.As(err, &perm) {
+			return err
+		}
+
+		mu.Lock()
+		last = err
+		mu.Unlock()
+
+		return rerun.ErrDoRetry
+	}).Execute(ctx)
+
+	if errors.Is(err, rerun.ErrAttemptsExhausted) {
+		mu.Lock()
+		defer mu.Unlock()
+		return ErrNotReady.Wrap(last)
+	}
+
+	return err
+}
+
+// WaitForTCP waits until a TCP connection can be established with addr. The
+// connection is closed immediately after it is established. Each connection
+// attempt is abandoned after 10 seconds if not sooner.
+// See WaitForFunc for a discussion of the returned error.
+func WaitForTCP(ctx context.Context, r *rerun.Rerun, addr string) error {
+	d := net.Dialer{Timeout: checkTimeout}
+
+	return WaitForFunc(ctx, r, func(ctx context.Context) error {
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// WaitForHTTP waits until a GET request for url returns one of the status
+// codes listed in acceptStatus. If no status codes are provided, any 2xx
+// status is accepted. Each request is abandoned after 10 seconds if not
+// sooner. An invalid url is reported at once, without any request being
+// made. See WaitForFunc for a discussion of the returned error.
+func WaitForHTTP(ctx context.Context, r *rerun.Rerun, url string, acceptStatus ...int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	if u := req.URL; u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: not an absolute http or https URL", url)
+	}
+
+	client := &http.Client{Timeout: checkTimeout}
+
+	return WaitForFunc(ctx, r, func(ctx context.Context) error {
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if !accepted(resp.StatusCode, acceptStatus) {
+			return fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+		}
+
+		return nil
+	})
+}
+
+func accepted(code int, acceptStatus []int) bool {
+	if len(acceptStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+
+	for _, s := range acceptStatus {
+		if s == code {
+			return true
+		}
+	}
+
+	return false
+}
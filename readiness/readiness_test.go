@@ -0,0 +1,87 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package readiness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestWaitForHTTP(t *testing.T) {
+	var calls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls++; calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	r := rerun.New(5).WithAlgorithm(rerun.FixedDelay(time.Millisecond))
+
+	if err := WaitForHTTP(context.Background(), r, srv.URL); err != nil {
+		t.Errorf("WaitForHTTP() returned unexpected error: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("server called %d times; wanted 3", calls)
+	}
+}
+
+func TestWaitForFuncNotReady(t *testing.T) {
+	errDown := errors.New("down")
+	r := rerun.New(3).WithAlgorithm(rerun.FixedDelay(0))
+
+	err := WaitForFunc(context.Background(), r, func(context.Context) error { return errDown })
+
+	if !errors.Is(err, ErrNotReady) || !errors.Is(err, errDown) {
+		t.Errorf("WaitForFunc() returned %v; wanted error wrapping %v and %v", err, ErrNotReady, errDown)
+	}
+}
+
+func TestWaitForHTTPInvalidURL(t *testing.T) {
+	r := rerun.New(5).WithAlgorithm(rerun.FixedDelay(time.Hour))
+
+	for _, url := range []string{"://missing-scheme", "ftp://example.com/", "/relative"} {
+		if err := WaitForHTTP(context.Background(), r, url); err == nil {
+			t.Errorf("WaitForHTTP(%q) returned nil; wanted an error", url)
+		}
+	}
+}
+
+func TestWaitForFuncAttemptContext(t *testing.T) {
+	r := rerun.New(3).WithAlgorithm(rerun.FixedDelay(0)).WithAttemptTimeout(time.Minute)
+
+	var deadlines int
+	err := WaitForFunc(context.Background(), r, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); ok {
+			deadlines++
+		}
+		return errors.New("down")
+	})
+
+	if !errors.Is(err, ErrNotReady) || deadlines != 3 {
+		t.Errorf("WaitForFunc() returned %v after %d checks with deadlines; wanted %v after 3", err, deadlines, ErrNotReady)
+	}
+}
+
+func TestWaitForFuncPermanent(t *testing.T) {
+	errFatal := errors.New("fatal")
+	r := rerun.New(3).WithAlgorithm(rerun.FixedDelay(0))
+
+	var calls int
+	err := WaitForFunc(context.Background(), r, func(context.Context) error {
+		calls++
+		return rerun.Permanent(errFatal)
+	})
+
+	if err != errFatal || calls != 1 {
+		t.Errorf("WaitForFunc() returned %v after %d calls; wanted %v after 1", err, calls, errFatal)
+	}
+}
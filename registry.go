@@ -0,0 +1,116 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RunInfo describes a call to Execute that is currently underway, as
+// returned by ActiveRuns.
+type RunInfo struct {
+	// ID uniquely identifies the execution (see ExecutionIDFromContext).
+	ID string
+
+	// Name is the name of the executing Rerun (see WithName).
+	Name string
+
+	// Iteration is the iteration number of the current attempt or, while
+	// waiting, that of the attempt most recently made.
+	Iteration uint
+
+	// Iterations is the total number of attempts that may be made.
+	Iterations uint
+
+	// Started is the time at which Execute was called.
+	Started time.Time
+
+	// NextAttempt is the time at which the next attempt is due while Execute
+	// is pausing before it, and the zero Time otherwise.
+	NextAttempt time.Time
+
+	// LastError is the error returned by the most recent attempt, if any.
+	LastError error
+}
+
+var (
+	// tracking is set once TrackActiveRuns has been called; until then,
+	// executions are not recorded in registry.
+	tracking atomic.Bool
+
+	// registry holds every execution currently underway.
+	registry sync.Map // *execution -> struct{}
+)
+
+// TrackActiveRuns enables the bookkeeping behind ActiveRuns for every call to
+// Execute or ExecuteHedged made thereafter; it cannot be disabled. Programs
+// that never call it pay nothing for ActiveRuns. The rerundebug package calls
+// it when its handler is installed.
+func TrackActiveRuns() {
+	tracking.Store(true)
+}
+
+// ActiveRuns returns a snapshot describing each call to Execute currently
+// underway in the process, ordered by the time each was started. It is
+// intended for live diagnostics, such as the handler provided by the
+// rerundebug package, and reports nothing unless TrackActiveRuns has been
+// called.
+func ActiveRuns() []RunInfo {
+	var runs []RunInfo
+
+	registry.Range(func(k, _ any) bool {
+		runs = append(runs, k.(*execution).runInfo())
+		return true
+	})
+
+	slices.SortStableFunc(runs, func(a, b RunInfo) int {
+		return a.Started.Compare(b.Started)
+	})
+
+	return runs
+}
+
+// register records e in registry if TrackActiveRuns has been called.
+func (e *execution) register() {
+	if tracking.Load() {
+		e.registered = true
+		registry.Store(e, struct{}{})
+	}
+}
+
+func (e *execution) unregister() {
+	if e.registered {
+		registry.Delete(e)
+	}
+}
+
+func (e *execution) runInfo() RunInfo {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.info
+}
+
+// waiting records that the next attempt is due at time next.
+func (e *execution) waiting(next time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.info.NextAttempt = next
+}
+
+// attempting records that iteration i is underway.
+func (e *execution) attempting(i uint) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.info.Iteration = i
+	e.info.NextAttempt = time.Time{}
+}
+
+// attempted records the error returned by the current attempt.
+func (e *execution) attempted(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.info.LastError = err
+}
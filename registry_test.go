@@ -0,0 +1,40 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"testing"
+)
+
+func TestActiveRunsTracking(t *testing.T) {
+	t.Cleanup(func() { tracking.Store(false) })
+
+	var seen int
+	r := New(2).WithName("tracked").WithFunction(func(uint) error {
+		seen = len(ActiveRuns())
+		return nil
+	})
+
+	if err := r.Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if seen != 0 {
+		t.Errorf("ActiveRuns() listed %d run(s) before TrackActiveRuns; wanted none", seen)
+	}
+
+	TrackActiveRuns()
+
+	if err := r.Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if seen != 1 {
+		t.Errorf("ActiveRuns() listed %d run(s) after TrackActiveRuns; wanted 1", seen)
+	}
+
+	if runs := ActiveRuns(); len(runs) != 0 {
+		t.Errorf("ActiveRuns() == %v after Execute returned; wanted none", runs)
+	}
+}
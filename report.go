@@ -0,0 +1,128 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"slices"
+	"time"
+)
+
+// Report describes the attempts made during a single call to
+// Rerun.ExecuteReport.
+type Report struct {
+	// ExecutionID uniquely identifies the execution described by the Report
+	// (see ExecutionIDFromContext). It is empty if Execute failed before
+	// the execution began (e.g. because the Rerun is invalid).
+	ExecutionID string
+
+	// Attempts holds one entry for each call made to the Rerun's Func, in
+	// the order they were made.
+	Attempts []AttemptReport
+
+	// Slept is the total time spent in the warmup and waiting periods
+	// imposed by the execution.
+	Slept time.Duration
+
+	// Reason explains why the execution ended (see Decision). It is empty
+	// if Execute failed before the execution began.
+	Reason Reason
+
+	// Outcome summarizes how the execution ended.
+	Outcome Outcome
+}
+
+// Outcome summarizes how an execution ended, as recorded by a Report.
+type Outcome string
+
+// These are the Outcomes recorded by a Report.
+const (
+	OutcomeSuccess   Outcome = "success"   // the Func succeeded
+	OutcomeExhausted Outcome = "exhausted" // attempts or budget were exhausted
+	OutcomeCanceled  Outcome = "canceled"  // the Context became done
+	OutcomeFailed    Outcome = "failed"    // any other failure
+)
+
+// outcomeOf returns the Outcome of an execution ending for the given Reason.
+func outcomeOf(why Reason) Outcome {
+	switch why {
+	case ReasonSuccess:
+		return OutcomeSuccess
+	case ReasonExhausted, ReasonBudget:
+		return OutcomeExhausted
+	case ReasonContextDone:
+		return OutcomeCanceled
+	default:
+		return OutcomeFailed
+	}
+}
+
+// AttemptReport describes a single call to a Rerun's Func.
+type AttemptReport struct {
+	// Iteration is the value passed to the Func for this attempt.
+	Iteration uint
+
+	// Start is the time at which the Func was called.
+	Start time.Time
+
+	// End is the time at which the Func returned (or panicked).
+	End time.Time
+
+	// Latency is the wall time spent inside the Func for this attempt.
+	Latency time.Duration
+
+	// Err is the error returned by the Func (or recovered from its panic).
+	Err error
+
+	// Cost is the weight of this attempt, or zero if cost weighting is not
+	// configured (see WithCost).
+	Cost float64
+}
+
+// LatencyStats holds aggregate statistics over the latencies of all attempts
+// recorded in a Report. Percentiles are calculated using the nearest-rank
+// method. The zero value is returned for a Report having no attempts.
+type LatencyStats struct {
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+}
+
+// Latency returns aggregate latency statistics across all of the receiver's
+// attempts.
+func (rp *Report) Latency() LatencyStats {
+	if rp == nil || len(rp.Attempts) == 0 {
+		return LatencyStats{}
+	}
+
+	lat := make([]time.Duration, len(rp.Attempts))
+	for i, a := range rp.Attempts {
+		lat[i] = a.Latency
+	}
+	slices.Sort(lat)
+
+	return LatencyStats{
+		Count: len(lat),
+		Min:   lat[0],
+		Max:   lat[len(lat)-1],
+		P50:   percentile(lat, 50),
+		P95:   percentile(lat, 95),
+	}
+}
+
+func (rp *Report) record(ar AttemptReport) {
+	if rp != nil {
+		rp.Attempts = append(rp.Attempts, ar)
+	}
+}
+
+// percentile returns the p'th percentile value from the sorted slice s using
+// the nearest-rank method. s must not be empty.
+func percentile(s []time.Duration, p int) time.Duration {
+	rank := (p*len(s) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return s[rank-1]
+}
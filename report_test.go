@@ -0,0 +1,210 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"syscall"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestReportLatency(t *testing.T) {
+	rp := new(Report)
+	if got := rp.Latency(); got != (LatencyStats{}) {
+		t.Errorf("empty Report: Latency() == %+v; wanted zero value", got)
+	}
+
+	for i := uint(20); i > 0; i-- {
+		rp.record(AttemptReport{Iteration: i, Latency: time.Duration(i) * time.Millisecond})
+	}
+
+	want := LatencyStats{
+		Count: 20,
+		Min:   1 * time.Millisecond,
+		Max:   20 * time.Millisecond,
+		P50:   10 * time.Millisecond,
+		P95:   19 * time.Millisecond,
+	}
+
+	if got := rp.Latency(); got != want {
+		t.Errorf("Latency() == %+v; wanted %+v", got, want)
+	}
+}
+
+func TestExecuteReport(t *testing.T) {
+	r := New(5).WithAlgorithm(FixedDelay(0)).WithFunction(func(i uint) error {
+		if i < 2 {
+			return ErrDoRetry
+		}
+		return nil
+	})
+
+	rp, err := r.ExecuteReport(context.Background())
+	if err != nil {
+		t.Fatalf("ExecuteReport() returned unexpected error: %v", err)
+	}
+
+	if got, want := len(rp.Attempts), 3; got != want {
+		t.Fatalf("len(Report.Attempts) == %d; wanted %d", got, want)
+	}
+
+	for i, a := range rp.Attempts {
+		if a.Iteration != uint(i) {
+			t.Errorf("Attempts[%d].Iteration == %d; wanted %d", i, a.Iteration, i)
+		}
+	}
+}
+
+func TestExecuteReportOutcome(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errDenied := errors.New("denied")
+
+		tests := []struct {
+			name    string
+			fn      Func
+			cancel  bool
+			reason  Reason
+			outcome Outcome
+			slept   time.Duration
+		}{
+			{"success", func(i uint) error {
+				if i < 1 {
+					return ErrDoRetry
+				}
+				return nil
+			}, false, ReasonSuccess, OutcomeSuccess, 3 * time.Second},
+			{"exhausted", func(uint) error { return ErrDoRetry }, false, ReasonExhausted, OutcomeExhausted, 5 * time.Second},
+			{"failed", func(uint) error { return errDenied }, false, ReasonNonRetryable, OutcomeFailed, time.Second},
+			{"canceled", func(uint) error { return ErrDoRetry }, true, ReasonContextDone, OutcomeCanceled, 2 * time.Second},
+		}
+
+		for _, tc := range tests {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.cancel {
+				time.AfterFunc(2*time.Second, cancel)
+			}
+
+			r := New(3).WithAlgorithm(Fixed{Start: time.Second, Delay: 2 * time.Second}).WithFunction(func(i uint) error {
+				time.Sleep(100 * time.Millisecond)
+				return tc.fn(i)
+			})
+
+			rp, _ := r.ExecuteReport(ctx)
+			cancel()
+
+			if rp.Reason != tc.reason || rp.Outcome != tc.outcome {
+				t.Errorf("%s: Report ended with %q (%q); wanted %q (%q)", tc.name, rp.Outcome, rp.Reason, tc.outcome, tc.reason)
+			}
+
+			// n.b. A canceled wait ends early.
+			if want := tc.slept; tc.cancel {
+				if rp.Slept != want-100*time.Millisecond {
+					t.Errorf("%s: Report.Slept == %v; wanted %v", tc.name, rp.Slept, want-100*time.Millisecond)
+				}
+			} else if rp.Slept != want {
+				t.Errorf("%s: Report.Slept == %v; wanted %v", tc.name, rp.Slept, want)
+			}
+
+			for i, a := range rp.Attempts {
+				if got := a.End.Sub(a.Start); got != 100*time.Millisecond || a.Latency != got {
+					t.Errorf("%s: Attempts[%d] spanned %v (latency %v); wanted 100ms", tc.name, i, got, a.Latency)
+				}
+			}
+		}
+
+		rp, err := New(1).WithFunction(func(uint) error { return nil }).ExecuteReport(context.Background())
+		if err != ErrTooFewIterations || rp.Reason != "" || rp.Outcome != OutcomeFailed {
+			t.Errorf("ExecuteReport() == (%q, %q), %v; wanted (%q, %q), %v", rp.Outcome, rp.Reason, err, OutcomeFailed, "", ErrTooFewIterations)
+		}
+	})
+}
+
+func TestReportErrorGroups(t *testing.T) {
+	errBoom := errors.New("boom")
+	reset := &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}
+
+	rp := new(Report)
+	for _, err := range []error{
+		fmt.Errorf("call failed: %w", reset),
+		context.DeadlineExceeded,
+		errors.Join(ErrDoRetry, fmt.Errorf("attempt 3: %w", context.DeadlineExceeded)),
+		nil,
+		errBoom,
+		ErrDoRetry.Wrap(fmt.Errorf("deadline: %w", context.DeadlineExceeded)),
+		reset,
+	} {
+		rp.record(AttemptReport{Err: err})
+	}
+
+	want := []struct {
+		fp    string
+		count int
+	}{
+		{"context.deadlineExceededError", 3},
+		{"syscall.Errno: connection reset by peer", 2},
+		{"*errors.errorString: boom", 1},
+	}
+
+	got := rp.ErrorGroups(nil)
+	if len(got) != len(want) {
+		t.Fatalf("ErrorGroups() == %+v; wanted %+v", got, want)
+	}
+
+	for i, w := range want {
+		if got[i].Fingerprint != w.fp || got[i].Count != w.count {
+			t.Errorf("group %d == {%q %d}; wanted {%q %d}", i, got[i].Fingerprint, got[i].Count, w.fp, w.count)
+		}
+	}
+}
+
+func TestReportHistogram(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rp := new(Report)
+	for i, err := range []error{
+		syscall.ECONNRESET,
+		syscall.ECONNRESET,
+		context.DeadlineExceeded,
+		context.DeadlineExceeded,
+		context.DeadlineExceeded,
+		nil,
+	} {
+		rp.record(AttemptReport{Iteration: uint(i), Start: start.Add(time.Duration(i) * time.Second), Err: err})
+	}
+
+	reset, timeout := "syscall.Errno: connection reset by peer", "context.deadlineExceededError"
+
+	want := []map[string]int{
+		{reset: 2},
+		{timeout: 2},
+		{timeout: 1, "": 1},
+	}
+
+	got := rp.Histogram(3, nil)
+	if len(got) != len(want) {
+		t.Fatalf("Histogram() returned %d buckets; wanted %d", len(got), len(want))
+	}
+
+	for i, b := range got {
+		if !maps.Equal(b.Counts, want[i]) {
+			t.Errorf("bucket %d: Counts == %v; wanted %v", i, b.Counts, want[i])
+		}
+	}
+
+	if end := got[2].End; !end.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("final bucket ends at %v; wanted %v", end, start.Add(5*time.Second))
+	}
+
+	groups := rp.ErrorGroups(nil)
+	if g := groups[0]; !g.First.Equal(start.Add(2*time.Second)) || !g.Last.Equal(start.Add(4*time.Second)) {
+		t.Errorf("ErrorGroups()[0] spans %v to %v; wanted 2s to 4s after start", g.First, g.Last)
+	}
+}
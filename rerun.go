@@ -3,30 +3,102 @@
 // Package rerun implements logic to rerun a given function up to a set number
 // of times with configurable wait periods interleaved between each attempt.
 // XXX MORE XXX
+//
+// # Testing
+//
+// When using the default SystemClock, Execute imposes all waiting periods
+// and attempt timeouts using timers from the standard time package. This
+// makes it fully compatible with the testing/synctest package: when run
+// inside a synctest bubble, Execute observes the bubble's fake time and each
+// waiting period elapses instantly once every goroutine in the bubble is
+// durably blocked. No special configuration is needed for this mode; simply
+// avoid attaching a different Clock with WithClock.
+//
+// Execute calls its Func from the calling goroutine and starts goroutines
+// of its own only when configured to do so:
+//
+//   - Under WithAbandonHungAttempts, each attempt having a timeout calls the
+//     Func from a new goroutine. Should the attempt be abandoned, that
+//     goroutine runs on until the Func returns, perhaps long after Execute
+//     has returned.
+//
+//   - With a Clock attached that does not keep real time (i.e. other than
+//     SystemClock or a TimerWheel), each attempt timeout is watched by a
+//     new goroutine that ends along with its attempt.
+//
+// ExecuteHedged, by its nature, calls the Func from a new goroutine for each
+// attempt; those abandoned may likewise outlive it. Since goroutines started
+// inside a synctest bubble belong to it, a test using either must allow
+// abandoned attempts to return before its bubble ends.
+//
+// Tests not using testing/synctest may instead attach the FakeClock provided
+// by the reruntest package.
 package rerun
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-type delayUnits time.Duration
+// DelayUnits defines the resolution at which an Algorithm interprets values
+// having no time.Duration anchor of their own. Only the basic units defined
+// below are valid; see the OK method.
+type DelayUnits time.Duration
 
 const (
 	// The following values are available for use by types implementing
 	// the Algorithm interface where none of their underlying paramaters
-	// provide a time.Duration anchor value. They use an unexported type
-	// (wrapping time.Duration) to ensure that only basic unit values are
-	// used.  See LogarithmicDelay for an example of how this may be used.
-	Nanosecond  = delayUnits(time.Nanosecond)
-	Microsecond = delayUnits(time.Microsecond)
-	Millisecond = delayUnits(time.Millisecond)
-	Second      = delayUnits(time.Second)
-	Minute      = delayUnits(time.Minute)
-	Hour        = delayUnits(time.Hour)
+	// provide a time.Duration anchor value. An Algorithm using them should
+	// call DelayUnits.OK from its own OK method to ensure that only these
+	// basic unit values are used. See LogarithmicDelay for an example of how
+	// this may be done.
+	Nanosecond  = DelayUnits(time.Nanosecond)
+	Microsecond = DelayUnits(time.Microsecond)
+	Millisecond = DelayUnits(time.Millisecond)
+	Second      = DelayUnits(time.Second)
+	Minute      = DelayUnits(time.Minute)
+	Hour        = DelayUnits(time.Hour)
 )
 
+// ParseDelayUnits returns the DelayUnits named by s, which must be one of the
+// unit suffixes accepted by time.ParseDuration: "ns", "us" (or "µs"), "ms",
+// "s", "m" or "h".
+func ParseDelayUnits(s string) (DelayUnits, error) {
+	d, err := time.ParseDuration("1" + s)
+	if err != nil {
+		return 0, ErrInvalidUnits.Wrap(err)
+	}
+
+	u := DelayUnits(d)
+	if err := u.OK(); err != nil {
+		return 0, err
+	}
+
+	return u, nil
+}
+
+// OK returns ErrInvalidUnits unless the receiver is one of the basic units
+// defined by this package (Nanosecond, Microsecond, Millisecond, Second,
+// Minute or Hour).
+func (u DelayUnits) OK() error {
+	switch u {
+	case Nanosecond, Microsecond, Millisecond, Second, Minute, Hour:
+		return nil
+	}
+	return fieldError("DelayUnits", time.Duration(u), ErrInvalidUnits)
+}
+
+func (u DelayUnits) String() string {
+	return time.Duration(u).String()
+}
+
 // The Algorithm interface is implemented by types defining the waiting
 // periods Rerun.Execute will interleave between each call to a provided
 // Func.
@@ -34,7 +106,8 @@ type Algorithm interface {
 	// OK is called to ensure the underlying parameters defining this Algorithm
 	// implementation are valid and should only return nil if all calls to its
 	// Wait method (up to the provided uint value) will successfully calculate
-	// a valid wait time.
+	// a valid wait time. When called with Forever, OK should consider every
+	// possible iteration and so must not iterate over them one by one.
 	OK(uint) error
 
 	// Warmup should return a time.Duration for the waiting period to be
@@ -49,30 +122,123 @@ type Algorithm interface {
 	//
 	// Note that since wait times are interleaved between each retry attempt,
 	// the number of calls to Wait will always be 1 less than the number of
-	// configured iterations.
+	// configured iterations. Since no wait precedes the initial attempt
+	// (iteration zero), Wait should return zero if called with zero.
 	Wait(uint) time.Duration
 }
 
 // Rerun defines the behavior for running a given function up to a set number
 // of times with configurable waiting periods interleaved between each attempt.
 // The zero-value is unusable.
+//
+// A Rerun is immutable once constructed; each of its option methods returns
+// a modified copy rather than altering its receiver, and all per-execution
+// state is kept local to each call to Execute. Consequently, a single
+// configured Rerun may be executed concurrently from any number of goroutines
+// -- which is its natural usage in servers -- provided its Algorithm, Func and
+// Clock are themselves safe for concurrent use. All Algorithms and Clocks
+// provided by this module are.
 type Rerun struct {
+	name       string
 	iterations uint
 	algorithm  Algorithm
-	function   Func
+	function   ContextFunc
+	middleware []Middleware
 	err        error
+	waits      []time.Duration
+
+	onDeadline     func(*DeadlineError)
+	strictDeadline bool
+	firstAttempt   bool
+	attemptTimeout time.Duration
+	abandonHung    bool
+	maxElapsed     time.Duration
+	coldStart      *coldStart
+
+	smoothing *HintSmoothing
+	clock     Clock
+	noSleep   bool
+	timeScale *float64
+
+	retryPanics bool
+	maxPanics   uint
+	noRecover   bool
+
+	ctxErrFunc ContextErrorFunc
+	retryIf    func(error) bool
+	hooks      Hooks
+	logger     *slog.Logger
+	metrics    MetricsRecorder
+	nesting    NestingPolicy
+
+	budget *RetryBudget
+	cost   *costPolicy
+	stats  *counters
 }
 
 // DefaultAlgorithm is the default Algorithm used by Rerun.Execute if no other
-// Algorithm is specified. This default is a 1s FixedDelay algorithm with no
+// Algorithm is specified (and none has been established using
+// SetDefaultAlgorithm). This default is a 1s FixedDelay algorithm with no
 // warmup time and a fixed, 1s wait between each retry attempt.
 const DefaultAlgorithm = Fixed1s
 
+// defaultAlgorithm holds the Algorithm established by SetDefaultAlgorithm.
+var defaultAlgorithm atomic.Pointer[Algorithm]
+
+// SetDefaultAlgorithm establishes algo as the Algorithm used by all Reruns
+// subsequently created by New, in place of DefaultAlgorithm, allowing an
+// application to configure its house default once at startup. Reruns
+// created before the call are unaffected. An error is returned, leaving the
+// current default unchanged, if algo's OK method fails for the minimum of 2
+// iterations. Passing nil restores DefaultAlgorithm.
+//
+// SetDefaultAlgorithm is safe for concurrent use, though algo will be shared
+// by all Reruns created by New and must therefore be safe for concurrent use
+// itself.
+func SetDefaultAlgorithm(algo Algorithm) error {
+	if algo == nil {
+		defaultAlgorithm.Store(nil)
+		return nil
+	}
+
+	if err := algo.OK(2); err != nil {
+		return err
+	}
+
+	defaultAlgorithm.Store(&algo)
+
+	return nil
+}
+
+// currentDefault returns the Algorithm established by SetDefaultAlgorithm or
+// DefaultAlgorithm if there is none.
+func currentDefault() Algorithm {
+	if algo := defaultAlgorithm.Load(); algo != nil {
+		return *algo
+	}
+	return DefaultAlgorithm
+}
+
+// Forever may be passed to New in place of a number of iterations to create
+// a Rerun that never exhausts its attempts. Its Execute method retries until
+// the Func succeeds or returns an error calling for no retry, or until the
+// given Context becomes done -- so such a Rerun is generally executed with a
+// Context that is eventually canceled or has a deadline.
+const Forever uint = math.MaxUint
+
 // New returns a new Rerun object configured for the given number of
-// iterations using the DefaultAlgorithm. To employ a different Algorithm,
-// use the WithAlgorithm option method.
+// iterations (or Forever) using the default Algorithm (see
+// SetDefaultAlgorithm). To employ a different Algorithm, use the
+// WithAlgorithm option method.
 func New(i uint) *Rerun {
-	return &Rerun{iterations: i, algorithm: DefaultAlgorithm}
+	algo := currentDefault()
+
+	return &Rerun{
+		iterations: i,
+		algorithm:  algo,
+		waits:      precompute(algo, i),
+		stats:      new(counters),
+	}
 }
 
 // WithAlgorithm returns a pointer to its receiver after updating its attached
@@ -80,11 +246,16 @@ func New(i uint) *Rerun {
 // error subsequent calls to the receiver's Err method will return a non-nil
 // error. Note that since this method does not employ a pointer receiver,
 // only the return value will be updated (but not the caller's receiver value).
+//
+// If algo is Deterministic, its waiting periods are precomputed here (see
+// the Waits method).
 func (r Rerun) WithAlgorithm(algo Algorithm) *Rerun {
+	r.waits = nil
 	if algo == nil {
 		r.err = ErrNilAlgorithm
 	} else {
 		r.err = algo.OK(r.iterations)
+		r.waits = precompute(algo, r.iterations)
 	}
 	r.algorithm = algo
 
@@ -97,23 +268,171 @@ func (r Rerun) WithAlgorithm(algo Algorithm) *Rerun {
 // receiver's Func value (if any). Note that calling the Execute method with
 // a Rerun having a nil Func associated will always results in an error.
 func (r Rerun) WithFunction(function Func) *Rerun {
+	if function == nil {
+		r.function = nil
+	} else {
+		r.function = func(_ context.Context, i uint) error { return function(i) }
+	}
+	return &r
+}
+
+// WithContextFunction is similar to WithFunction but associates a ContextFunc
+// with the receiver. Such a function is passed a Context derived from the one
+// given to Execute, allowing it to observe cancellation and deadlines (as
+// well as any attempt timeout; see WithAttemptTimeout) while an attempt is
+// underway. Through this Context it may also access the current Attempt (see
+// AttemptFromContext) and the current execution's scratchpad (see
+// ScratchKey). See DoContext for a variant producing a value.
+func (r Rerun) WithContextFunction(function ContextFunc) *Rerun {
 	r.function = function
 	return &r
 }
 
+// WithClock returns a pointer to its receiver after attaching the given Clock
+// to be used by Execute for all time measurements, wait periods and attempt
+// timeouts. Passing a nil Clock restores the default, SystemClock. Tests may
+// attach the reruntest package's FakeClock, which moves only when advanced,
+// to exercise retry logic without real sleeps. Services running very many
+// concurrent executions may attach a shared TimerWheel to reduce timer
+// pressure.
+func (r Rerun) WithClock(clk Clock) *Rerun {
+	r.clock = clk
+	return &r
+}
+
+func (r Rerun) clk() Clock {
+	if r.clock == nil {
+		return SystemClock()
+	}
+	return r.clock
+}
+
+// WithName returns a pointer to its receiver after assigning it the given
+// name. A name identifies a retry policy (e.g. "payments-api" or "s3-upload")
+// so that the hooks, logs and metrics of a service employing several policies
+// may tell them apart. See also NameFromContext.
+func (r Rerun) WithName(name string) *Rerun {
+	r.name = name
+	return &r
+}
+
+// Name returns the name assigned to the receiver using WithName, if any.
+func (r Rerun) Name() string {
+	return r.name
+}
+
+// NameFromContext returns the name of the Rerun whose execution ctx belongs
+// to; that is, when ctx is passed to a ContextFunc or hook by Execute. An
+// empty string is returned if ctx did not originate from Execute or the
+// Rerun has no name.
+func NameFromContext(ctx context.Context) string {
+	if e := executionFrom(ctx); e != nil {
+		return e.name
+	}
+	return ""
+}
+
+// Iterations returns the number of iterations for which the receiver is
+// configured, which may be Forever.
+func (r Rerun) Iterations() uint {
+	return r.iterations
+}
+
+// unlimited reports whether the receiver was configured with Forever.
+func (r Rerun) unlimited() bool {
+	return r.iterations == Forever
+}
+
+// Algorithm returns the Algorithm attached to the receiver.
+func (r Rerun) Algorithm() Algorithm {
+	return r.algorithm
+}
+
 // Err returns any non-nil error that occurred during construction of its
 // receiver or if the OK method for the receiver's Algorithm returns an
-// error.
+// error. If several problems are found, they are all reported together
+// (using errors.Join).
 func (r Rerun) Err() error {
-	if r.err == nil {
-		r.err = r.algorithm.OK(r.iterations)
+	// n.b. Nothing is cached here; a Rerun is never modified after
+	// construction so that it may safely be shared between goroutines.
+	err := r.err
+	if err == nil {
+		err = r.algorithm.OK(r.iterations)
+	}
+
+	if r.smoothing != nil {
+		err = joinErrors(err, r.smoothing.OK())
+	}
+
+	if r.attemptTimeout < 0 {
+		err = joinErrors(err, fieldError("AttemptTimeout", r.attemptTimeout, ErrNegativeDuration))
+	}
+
+	if r.maxElapsed < 0 {
+		err = joinErrors(err, fieldError("MaxElapsed", r.maxElapsed, ErrNegativeDuration))
+	}
+
+	return joinErrors(err, r.coldStart.err(), r.timeScaleErr())
+}
+
+// String returns a concise, human-readable summary of the receiver's
+// configuration suitable for startup logs and error messages, such as
+// "5 attempts, exponential 200ms×2 cap 30s".
+func (r Rerun) String() string {
+	var sb strings.Builder
+
+	if r.name != "" {
+		fmt.Fprintf(&sb, "%s: ", r.name)
+	}
+
+	if r.unlimited() {
+		sb.WriteString("unlimited attempts, ")
+	} else {
+		fmt.Fprintf(&sb, "%d attempts, ", r.iterations)
+	}
+
+	if r.algorithm == nil {
+		sb.WriteString("no algorithm")
+	} else {
+		fmt.Fprint(&sb, r.algorithm)
+	}
+
+	if r.smoothing != nil {
+		fmt.Fprintf(&sb, ", hints smoothed α=%g", r.smoothing.Alpha)
+	}
+
+	if r.maxElapsed > 0 {
+		fmt.Fprintf(&sb, ", max elapsed %v", r.maxElapsed)
+	}
+
+	switch {
+	case r.noRecover:
+		sb.WriteString(", panics unrecovered")
+	case r.retryPanics && r.maxPanics > 0:
+		fmt.Fprintf(&sb, ", retry up to %d panics", r.maxPanics)
+	case r.retryPanics:
+		sb.WriteString(", retry panics")
+	}
+
+	return sb.String()
+}
+
+// warmupString returns the suffix used by the String methods of this
+// package's Algorithms to describe a warmup period of d (if any).
+func warmupString(d time.Duration) string {
+	if d == 0 {
+		return ""
 	}
-	return r.err
+	return ", warmup " + d.String()
 }
 
 // Func defines the signature for functions called by Rerun.Execute.
 type Func func(uint) error
 
+// ContextFunc is an alternative to Func for functions that also need access
+// to the Context given to Rerun.Execute. See WithContextFunction.
+type ContextFunc func(context.Context, uint) error
+
 // Execute is used to repeatedly execute the reciever's configured Func while
 // interleaving wait periods as defined by the Algorithm attached to the
 // receiver. Execute's behavior is goverened by the following rules:
@@ -127,24 +446,81 @@ type Func func(uint) error
 //   - If r.Err() returns a non-nil error, that error will be returned
 //     immediately.
 //
+//   - If ctx carries an attempt limit (see LimitAttempts and
+//     DisableRetries), no more than that many attempts are made.
+//
+//   - If Execute is called from within the Func of another Execute, the
+//     receiver's NestingPolicy may cause ErrNestedExecute to be returned
+//     immediately or may reduce the number of attempts made.
+//
+//   - If the receiver was configured using WithStrictDeadline and its
+//     minimum schedule cannot complete before ctx's deadline, a
+//     *DeadlineError is returned immediately.
+//
 //   - If the receiver's configure Func  returns a nil error, Execute
 //     returns immediately.  If the provided Context has not yet become
 //     done, then Execute returns a nil error. Otherwise, Execute will
 //     return ctx.Err().
 //
-//   - If the receiver's Func returns ErrDoRetry -- and Execute has not
-//     yet exhausted all of the receiver's configured iterations -- then
-//     Execute will pause for the Duration returned by Algorithm.Wait.
+//   - If the receiver's Func returns ErrDoRetry (or any error wrapping it,
+//     as determined by errors.Is, other than one returned by a nested
+//     Execute upon giving up; see IsRetrySignal) -- and Execute has not yet
+//     exhausted all of the receiver's configured iterations -- then Execute
+//     will pause for the Duration returned by Algorithm.Wait.
 //     If the given Context becomes done during this wait period, Execute
 //     will immediately return ctx.Err(). Otherwise, the receiver's Func
 //     will be rerun after the alotted wait time.
 //
+//   - If the receiver's Func returns an error created by Permanent (or any
+//     error wrapping one), Execute immediately returns the error given to
+//     Permanent, regardless of the rules below.
+//
+//   - If the receiver was configured using WithRetryIf and its predicate
+//     returns true for the error returned by the receiver's Func, Execute
+//     behaves as it does for ErrDoRetry.
+//
+//   - If the receiver's Func returns an error created by RetryAfter (or any
+//     error wrapping one, as determined by errors.As), Execute behaves as it
+//     does for ErrDoRetry except that the requested wait period (as smoothed
+//     according to WithHintSmoothing) is used in place of the one returned
+//     by Algorithm.Wait.
+//
+//   - If the receiver's Func returns an error created by Progress (or any
+//     error wrapping one), Execute behaves as it does for ErrDoRetry except
+//     that its progression is first reset, as though the Func had just
+//     made its first attempt.
+//
+//   - If a retry is called for but the receiver's RetryBudget (see
+//     WithBudget) has no tokens left, Execute returns ErrBudgetExhausted
+//     (wrapping the error returned by the final attempt) immediately. If
+//     cost weighting is configured (see WithCost), Execute instead returns
+//     a *CostError when the weight of the retry exceeds the tokens
+//     available or the receiver's per-execution limit.
+//
+//   - If the receiver was configured using WithMaxElapsed and a retry,
+//     following its waiting period, would exceed the configured limit on
+//     elapsed time, Execute returns an *ElapsedError (which wraps
+//     ErrBudgetExhausted) immediately.
+//
+//   - If the receiver's Algorithm implements AlgorithmErr and its WaitErr
+//     method returns an error, Execute returns that error immediately.
+//
+//   - If the receiver was configured using WithAttemptTimeout and an
+//     attempt fails after exceeding its timeout, Execute behaves as it does
+//     for ErrDoRetry.
+//
 //   - If the receiver's Func returns ErrDoRetry -- but all of the receiver's
 //     configured iterations, have been exhausted -- then no pause will be
-//     introduced and Execute instead ErrAttemptsExhausted immediately.
+//     introduced and Execute instead returns an *AttemptsError (which wraps
+//     ErrAttemptsExhausted) immediately. This never happens for a Rerun
+//     created with Forever (unless ctx carries an attempt limit).
 //
 //   - If the receiver's Func causes a panic, it will be recovered and
-//     returned as an error.
+//     returned as a *PanicError -- unless WithRetryOnPanic is in effect,
+//     in which case the panic is treated like ErrDoRetry until the
+//     configured number of panics has been exceeded. If panic recovery
+//     has been disabled using WithoutPanicRecovery, the panic propagates
+//     out of Execute normally.
 //
 //   - Otherwise, Execute returns the error returned by the receiver's Func.
 //
@@ -162,16 +538,54 @@ type Func func(uint) error
 //
 //   - If Warmup returns a negative value, Execute returns ErrNegativeDuration
 //
+// The warmup period is skipped entirely for a warm start if
+// WithColdStartWarmup is in effect.
+//
 // Generally, regardless of the error returned by the receiver's Func, if ctx
-// becomes done, Execute will err towards returning ctx.Err() as soon as that
-// can be detected -- even during waiting periods (albeit, no effort is made
-// to cover any race conditions so this is not guaranteed).
-func (r Rerun) Execute(ctx context.Context) (err error) {
+// becomes done, Execute will err towards returning context.Cause(ctx) (or the
+// error mapped from it by WithContextError) as soon as that can be detected --
+// even during waiting periods (albeit, no effort is made to cover any race
+// conditions so this is not guaranteed).
+func (r Rerun) Execute(ctx context.Context) error {
+	return r.execute(ctx, nil)
+}
+
+// ExecuteReport behaves exactly like Execute but also returns a Report
+// describing each call made to the receiver's Func (including when it began
+// and ended and the error it returned), the total time spent waiting between
+// them and how the execution ended -- the raw material for SLO accounting and
+// postmortems. The returned Report is never nil, although it will hold no
+// attempts if Execute would have failed before calling the Func.
+func (r Rerun) ExecuteReport(ctx context.Context) (*Report, error) {
+	rp := new(Report)
+	err := r.execute(ctx, rp)
+	return rp, err
+}
+
+func (r Rerun) execute(ctx context.Context, rp *Report) (err error) {
+	var (
+		last error      // the most recent error returned by the Func
+		exec *execution // the execution's state, once begun
+		made uint       // the iteration number of the most recent attempt
+		why  Reason     // the reason for the next decision (see Hooks)
+	)
+
+	r.stats.execution()
+
 	defer func() {
 		select {
 		default:
 		case <-ctx.Done():
-			err = context.Cause(ctx)
+			err, why = r.contextError(ctx, last), ReasonContextDone
+		}
+
+		if rp != nil {
+			rp.Reason, rp.Outcome = why, outcomeOf(why)
+		}
+
+		if exec != nil {
+			r.hooks.decide(ctx, Decision{Iteration: made, Reason: why, Err: err})
+			r.hooks.giveUp(ctx, err)
 		}
 	}()
 
@@ -187,41 +601,269 @@ func (r Rerun) Execute(ctx context.Context) (err error) {
 		return err
 	}
 
-	// n.b. If Warmup returns 0, sleep will immediately return a nil error.
-	if err = sleep(ctx, r.algorithm.Warmup()); err != nil {
+	if r.iterations, err = r.nesting.iterations(ctx, r.iterations); err != nil {
 		return err
 	}
 
-	for i := uint(0); i < r.iterations; i++ {
+	if m, ok := MaxAttemptsFromContext(ctx); ok {
+		r.iterations = min(r.iterations, m)
+	}
+
+	if err = r.checkDeadline(ctx); err != nil {
+		return err
+	}
+
+	// n.b. r is a copy so this affects only the current execution.
+	r.algorithm = reset(r.algorithm)
+	r.hooks = r.hooks.join(r.logHooks()).join(r.metricsHooks())
+	r.function = r.wrap(r.function)
+
+	clk := r.clk()
+	started := clk.Now()
+
+	ctx, exec = r.withExecution(ctx, started)
+	exec.register()
+	defer exec.unregister()
+
+	if rp != nil {
+		rp.ExecutionID = exec.id
+	}
+
+	// n.b. If Warmup returns 0, pause will immediately return a nil error.
+	warmup := r.warmup(ctx, started)
+	r.hooks.warmup(ctx, warmup)
+	exec.waiting(started.Add(r.delay(warmup)))
+	if err = r.pause(ctx, clk, rp, warmup); err != nil {
+		why = ReasonWaitError
+		return err
+	}
+
+	var (
+		hint     *RetryAfterError
+		smoother = newHintSmoother(r.smoothing)
+		panics   uint
+		panicked bool
+		spent    float64 // see WithCost
+		failures AttemptsError
+	)
+
+	for i := uint(0); i < r.iterations || r.unlimited(); i++ {
+		a := Attempt{
+			Iteration:  i,
+			Iterations: r.iterations,
+			Started:    started,
+			Last:       last,
+			r:          &r,
+		}
+		a.cost = r.cost.weight(a)
+
 		if i > 0 {
-			if err = sleep(ctx, r.algorithm.Wait(i)); err != nil {
+			var wait time.Duration
+			source := "algorithm"
+			exec.reserved(nil)
+			if hint != nil {
+				wait, source = smoother.next(hint.Delay), "retry-after"
+			} else if wait, err = r.waitContext(ctx, a); err != nil {
+				exec.refused()
+				why = ReasonWaitError
 				return err
 			}
+
+			// n.b. Since any shared RetryBudget is charged for each retry,
+			// that is done only once the retry is otherwise approved.
+			if err = r.checkElapsed(a, clk.Now().Sub(started), wait); err == nil {
+				err = r.charge(ctx, a, spent)
+			}
+
+			if err != nil {
+				exec.refused()
+				why = ReasonBudget
+				return err
+			}
+
+			r.hooks.decide(ctx, Decision{Retry: true, Iteration: made, Reason: why, Err: last, Wait: wait, Source: source})
+			r.hooks.retry(ctx, i, last, wait)
+
+			exec.waiting(clk.Now().Add(r.delay(wait)))
+			if err = r.pause(ctx, clk, rp, wait); err != nil {
+				why = ReasonWaitError
+				return err
+			}
+
+			a.Wait = wait
+		} else {
+			a.Wait = warmup
+		}
+
+		r.stats.attempt(i)
+		exec.attempting(i)
+
+		var (
+			ar       AttemptReport
+			timedOut bool
+			perm     *PermanentError
+		)
+
+		spent, made = spent+a.cost, i
+		ar, panicked, timedOut = r.attempt(ctx, clk, a)
+		rp.record(ar)
+		exec.attempted(ar.Err)
+		err, last = ar.Err, ar.Err
+
+		if err != nil {
+			failures.failed(ar)
+		}
+
+		if panicked && r.retryPanics {
+			if panics++; r.maxPanics == 0 || panics <= r.maxPanics {
+				hint, why = nil, ReasonPanic
+				continue
+			}
 		}
 
-		switch err = r.runFunction(i); err {
-		case nil:
+		// n.b. The error returned by a nested Execute upon giving up wraps
+		// the retry signals returned by its attempts but is not itself a
+		// retry signal (see IsRetrySignal); retrying it here as well would
+		// multiply the attempts made by every level of nesting.
+		signal := IsRetrySignal(err)
+
+		switch {
+		case err == nil:
+			r.coldStart.succeeded(clk.Now())
+			why = ReasonSuccess
 			return nil
 
-		case ErrDoRetry:
+		// n.b. A *PermanentError may wrap any of the retry signals below so
+		// it must be checked first.
+		case errors.As(err, &perm):
+			why = ReasonPermanent
+			return perm.Err
+
+		// n.b. A *PanicError may wrap a retry signal (if the Func panics
+		// with one) but is only retried under WithRetryOnPanic (see above).
+		case panicked:
+			why = ReasonPanic
+			return err
+
+		// n.b. The loop increments i such that the next retry is made as
+		// iteration 1.
+		case signal && errors.As(err, new(*ProgressError)):
+			i, hint, why = 0, nil, ReasonProgress
+			errors.As(err, &hint)
+			continue
+
+		// n.b. A *RetryAfterError also matches ErrDoRetry so it must be
+		// checked first. If it's not found, hint is left untouched.
+		case signal && errors.As(err, &hint):
+			why = ReasonRetryAfter
+			continue
+
+		case signal:
+			hint, why = nil, ReasonRetrySignal
+			continue
+
+		case timedOut:
+			hint, why = nil, ReasonAttemptTimeout
+			continue
+
+		case r.retryIf != nil && r.retryIf(err):
+			hint, why = nil, ReasonRetryable
 			continue
 
 		default:
+			why = ReasonNonRetryable
 			return err
 		}
 	}
 
-	return ErrAttemptsExhausted
+	r.stats.exhaustion()
+	why = ReasonExhausted
+
+	return &failures
+}
+
+// attempt makes a single, timed call to the receiver's Func and returns its
+// description along with whether the Func panicked or exceeded its attempt
+// timeout (see WithAttemptTimeout). The OnAttempt hook (if any) is called
+// just before the Func and the OnAttemptEnd hook (if any) before attempt
+// returns, or before an unrecovered panic continues on its way.
+func (r Rerun) attempt(ctx context.Context, clk Clock, a Attempt) (ar AttemptReport, panicked, timedOut bool) {
+	ar = AttemptReport{Iteration: a.Iteration, Cost: a.cost}
+	end := r.hooks.OnAttemptEnd
+
+	ctx, actx, cancel := r.attemptContext(ctx, a)
+	defer cancel()
+
+	if r.noRecover && end != nil {
+		defer func() {
+			if v := recover(); v != nil {
+				ar.End = clk.Now()
+				ar.Latency = ar.End.Sub(ar.Start)
+				ar.Err = &PanicError{Value: v, Stack: debug.Stack()}
+				end(ctx, ar)
+				panic(v)
+			}
+		}()
+	}
+
+	r.hooks.attempt(ctx, a.Iteration)
+
+	ar.Start = clk.Now()
+	panicked, ar.Err = r.callFunction(actx, a.Iteration)
+	ar.End = clk.Now()
+	ar.Latency = ar.End.Sub(ar.Start)
+	timedOut = actx.Err() != nil && ctx.Err() == nil
+
+	if end != nil {
+		end(ctx, ar)
+	}
+
+	return ar, panicked, timedOut
 }
 
-// runFunction executes the Func associated with the receiver. Any panic
-// caused by doing so will be recovered and returned as an error.
-func (r Rerun) runFunction(i uint) (err error) {
+// callFunction calls runFunction, giving up on the call once ctx is done if
+// the receiver was configured using WithAbandonHungAttempts (in which case
+// ctx's error is returned).
+func (r Rerun) callFunction(ctx context.Context, i uint) (panicked bool, err error) {
+	if !r.abandonHung || r.attemptTimeout <= 0 {
+		return r.runFunction(ctx, i)
+	}
+
+	type result struct {
+		panicked bool
+		err      error
+	}
+
+	// n.b. Buffered so that an abandoned call may still deliver its result.
+	done := make(chan result, 1)
+
+	go func() {
+		var res result
+		res.panicked, res.err = r.runFunction(ctx, i)
+		done <- res
+	}()
+
+	select {
+	case res := <-done:
+		return res.panicked, res.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// runFunction executes the Func associated with the receiver. Unless panic
+// recovery has been disabled, any panic caused by doing so will be recovered
+// and returned as a *PanicError, in which case panicked will be true.
+func (r Rerun) runFunction(ctx context.Context, i uint) (panicked bool, err error) {
+	if r.noRecover {
+		return false, r.function(ctx, i)
+	}
+
 	defer func() {
 		if perr := recover(); perr != nil {
-			err = fmt.Errorf("recovered from panic: %v", perr)
+			panicked, err = true, &PanicError{Value: perr, Stack: debug.Stack()}
 		}
 	}()
 
-	return r.function(i)
+	return false, r.function(ctx, i)
 }
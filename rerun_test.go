@@ -0,0 +1,223 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestExecuteWrappedSentinels(t *testing.T) {
+	errOther := errors.New("other")
+
+	for _, tc := range []struct {
+		name  string
+		retry error
+	}{
+		{"bare", ErrDoRetry},
+		{"fmt", fmt.Errorf("backend unavailable: %w", ErrDoRetry)},
+		{"joined", errors.Join(errOther, ErrDoRetry)},
+		{"wrapped", ErrDoRetry.Wrap(errOther)},
+		{"retryafter", fmt.Errorf("throttled: %w", RetryAfter(0))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+
+			err := New(3).WithAlgorithm(FixedDelay(0)).WithFunction(func(uint) error {
+				if calls++; calls < 3 {
+					return tc.retry
+				}
+				return nil
+			}).Execute(context.Background())
+
+			if err != nil {
+				t.Errorf("Execute() returned unexpected error: %v", err)
+			}
+
+			if calls != 3 {
+				t.Errorf("Func called %d times; wanted 3", calls)
+			}
+		})
+	}
+}
+
+func TestExecuteWrappedRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls []time.Duration
+
+		start := time.Now()
+		err := New(3).WithAlgorithm(FixedDelay(time.Hour)).WithFunction(func(i uint) error {
+			calls = append(calls, time.Since(start))
+			switch i {
+			case 0:
+				return fmt.Errorf("throttled: %w", RetryAfter(time.Minute))
+			case 1:
+				return fmt.Errorf("failed: %w", ErrDoRetry)
+			default:
+				return nil
+			}
+		}).Execute(context.Background())
+
+		if err != nil {
+			t.Errorf("Execute() returned unexpected error: %v", err)
+		}
+
+		want := []time.Duration{0, time.Minute, time.Minute + time.Hour}
+		if len(calls) != len(want) {
+			t.Fatalf("Func called %d times; wanted %d", len(calls), len(want))
+		}
+
+		for i, c := range calls {
+			if c != want[i] {
+				t.Errorf("call %d at %v; wanted %v", i, c, want[i])
+			}
+		}
+	})
+}
+
+func TestExecuteTimedOut(t *testing.T) {
+	errBackend := errors.New("backend unavailable")
+
+	synctest.Test(t, func(t *testing.T) {
+		r := New(5).
+			WithAlgorithm(FixedDelay(time.Hour)).
+			WithContextError(TimedOut).
+			WithFunction(func(uint) error { return ErrDoRetry.Wrap(errBackend) })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
+		defer cancel()
+
+		err := r.Execute(ctx)
+
+		var toe *TimedOutError
+		if !errors.As(err, &toe) {
+			t.Fatalf("Execute() returned %v; wanted a *TimedOutError", err)
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errBackend) {
+			t.Errorf("Execute() returned %v; wanted error wrapping both %v and %v", err, context.DeadlineExceeded, errBackend)
+		}
+
+		if errors.Is(err, ErrDoRetry) {
+			t.Errorf("errors.Is(%v, ErrDoRetry) == true; wanted false", err)
+		}
+
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(90*time.Minute, cancel)
+
+		if err := r.Execute(ctx); err != context.Canceled {
+			t.Errorf("Execute() returned %v; wanted %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestSetDefaultAlgorithm(t *testing.T) {
+	defer SetDefaultAlgorithm(nil)
+
+	if err := SetDefaultAlgorithm(FixedDelay(-1)); !errors.Is(err, ErrNegativeDuration) {
+		t.Errorf("SetDefaultAlgorithm(invalid) == %v; wanted %v", err, ErrNegativeDuration)
+	}
+
+	if got := New(3).Algorithm(); got != DefaultAlgorithm {
+		t.Errorf("New().Algorithm() == %v; wanted %v", got, DefaultAlgorithm)
+	}
+
+	if err := SetDefaultAlgorithm(Fixed100ms); err != nil {
+		t.Fatalf("SetDefaultAlgorithm(%v) == %v", Fixed100ms, err)
+	}
+
+	if got := New(3).Algorithm(); got != Fixed100ms {
+		t.Errorf("New().Algorithm() == %v; wanted %v", got, Fixed100ms)
+	}
+
+	SetDefaultAlgorithm(nil)
+
+	if got := New(3).Algorithm(); got != DefaultAlgorithm {
+		t.Errorf("New().Algorithm() after reset == %v; wanted %v", got, DefaultAlgorithm)
+	}
+}
+
+func TestLimitAttempts(t *testing.T) {
+	var calls uint
+	r := New(5).WithAlgorithm(FixedDelay(0)).WithFunction(func(uint) error {
+		calls++
+		return ErrDoRetry
+	})
+
+	for _, tc := range []struct {
+		ctx  context.Context
+		want uint
+	}{
+		{context.Background(), 5},
+		{DisableRetries(context.Background()), 1},
+		{LimitAttempts(context.Background(), 0), 1},
+		{LimitAttempts(context.Background(), 3), 3},
+		{LimitAttempts(context.Background(), 9), 5},
+		{LimitAttempts(DisableRetries(context.Background()), 3), 1},
+		{LimitAttempts(LimitAttempts(context.Background(), 4), 2), 2},
+	} {
+		calls = 0
+		if err := r.Execute(tc.ctx); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if calls != tc.want {
+			t.Errorf("Func called %d times; wanted %d", calls, tc.want)
+		}
+	}
+}
+
+func TestForever(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		algo := ExponentialDelay{Base: time.Millisecond, Multiplier: 2, Max: time.Second}
+
+		var calls uint
+		r := New(Forever).WithAlgorithm(algo).WithFunction(func(i uint) error {
+			if calls++; i < 1000 {
+				return ErrDoRetry
+			}
+			return nil
+		})
+
+		if err := r.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.Execute(context.Background()); err != nil || calls != 1001 {
+			t.Errorf("Execute() == %v after %d calls; wanted <nil> after 1001", err, calls)
+		}
+
+		if got, want := r.String(), "unlimited attempts, "+algo.String(); got != want {
+			t.Errorf("String() == %q; wanted %q", got, want)
+		}
+
+		if d, err := r.LatestGiveUp(time.Second); err != nil || d != math.MaxInt64 {
+			t.Errorf("LatestGiveUp() == (%v, %v); wanted (%v, <nil>)", d, err, time.Duration(math.MaxInt64))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		calls = 0
+		r = r.WithFunction(func(uint) error { calls++; return ErrDoRetry })
+		if err := r.Execute(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Execute() == %v; wanted %v", err, context.DeadlineExceeded)
+		}
+
+		if calls < 3600 {
+			t.Errorf("Func called %d times within an hour; wanted at least 3600", calls)
+		}
+
+		calls = 0
+		if err := r.Execute(LimitAttempts(context.Background(), 3)); !errors.Is(err, ErrAttemptsExhausted) || calls != 3 {
+			t.Errorf("Execute() == %v after %d calls; wanted %v after 3", err, calls, ErrAttemptsExhausted)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package rerundebug serves a live listing of the rerun.Execute calls
+// currently underway in a process -- each with its policy name, attempt
+// number, next attempt time and last error -- for use during incident
+// triage. In the manner of net/http/pprof, merely importing this package
+// registers its handler with http.DefaultServeMux:
+//
+//	import _ "github.com/olympiclabs/rerun/rerundebug"
+//
+// The listing is then available at /debug/rerun, as plain text or, with a
+// query of "?format=json", as JSON. Applications not using the default
+// ServeMux may instead mount the value returned by Handler wherever they
+// wish.
+package rerundebug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// Path is the pattern with which Handler is registered on
+// http.DefaultServeMux.
+const Path = "/debug/rerun"
+
+func init() {
+	http.Handle(Path, Handler())
+}
+
+// Handler returns an http.Handler listing the rerun.Execute calls currently
+// underway, as reported by rerun.ActiveRuns. It calls rerun.TrackActiveRuns
+// so that those calls are recorded.
+func Handler() http.Handler {
+	rerun.TrackActiveRuns()
+	return http.HandlerFunc(serve)
+}
+
+// run is the JSON representation of a rerun.RunInfo.
+type run struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name,omitempty"`
+	Iteration   uint       `json:"iteration"`
+	Iterations  uint       `json:"iterations"`
+	Started     time.Time  `json:"started"`
+	NextAttempt *time.Time `json:"next_attempt,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+func serve(w http.ResponseWriter, req *http.Request) {
+	runs := rerun.ActiveRuns()
+
+	if req.FormValue("format") == "json" {
+		out := make([]run, len(runs))
+		for i, ri := range runs {
+			out[i] = run{
+				ID:         ri.ID,
+				Name:       ri.Name,
+				Iteration:  ri.Iteration,
+				Iterations: ri.Iterations,
+				Started:    ri.Started,
+			}
+			if !ri.NextAttempt.IsZero() {
+				out[i].NextAttempt = &ri.NextAttempt
+			}
+			if ri.LastError != nil {
+				out[i].LastError = ri.LastError.Error()
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "%d active run(s)\n\n", len(runs))
+	if len(runs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tATTEMPT\tRUNNING\tNEXT ATTEMPT\tLAST ERROR")
+	for _, ri := range runs {
+		name := ri.Name
+		if name == "" {
+			name = "-"
+		}
+
+		next := "now"
+		if !ri.NextAttempt.IsZero() {
+			next = "in " + ri.NextAttempt.Sub(now).Round(time.Millisecond).String()
+		}
+
+		last := "-"
+		if ri.LastError != nil {
+			last = ri.LastError.Error()
+		}
+
+		of := "∞"
+		if ri.Iterations != rerun.Forever {
+			of = strconv.FormatUint(uint64(ri.Iterations), 10)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d/%s\t%v\t%s\t%s\n", ri.ID, name, ri.Iteration+1, of,
+			now.Sub(ri.Started).Round(time.Millisecond), next, last)
+	}
+	tw.Flush()
+}
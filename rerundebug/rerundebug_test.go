@@ -0,0 +1,70 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerundebug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestHandler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r := rerun.New(5).
+			WithName("payments-api").
+			WithAlgorithm(rerun.FixedDelay(time.Minute)).
+			WithFunction(func(uint) error {
+				return errors.Join(rerun.ErrDoRetry, errors.New("connection refused"))
+			})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			r.Execute(ctx)
+		}()
+
+		time.Sleep(90 * time.Second)
+		synctest.Wait()
+
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest("GET", Path, nil))
+
+		body := rec.Body.String()
+		for _, want := range []string{"1 active run(s)", "payments-api", "2/5", "in 30s", "connection refused"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("text listing lacks %q:\n%s", want, body)
+			}
+		}
+
+		rec = httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest("GET", Path+"?format=json", nil))
+
+		var runs []run
+		if err := json.Unmarshal(rec.Body.Bytes(), &runs); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(runs) != 1 || runs[0].Name != "payments-api" || runs[0].Iteration != 1 || runs[0].NextAttempt == nil {
+			t.Errorf("JSON listing == %+v", runs)
+		}
+
+		cancel()
+		<-done
+
+		if runs := rerun.ActiveRuns(); len(runs) != 0 {
+			t.Errorf("ActiveRuns() == %v after Execute returned; wanted none", runs)
+		}
+	})
+}
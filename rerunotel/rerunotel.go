@@ -0,0 +1,141 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package rerunotel provides OpenTelemetry tracing for rerun executions. It
+// lives in a package of its own so that only programs importing it depend
+// upon OpenTelemetry.
+//
+// Execute traces a complete execution with a span of its own, under which
+// each attempt is traced by a child span (see Middleware):
+//
+//	err := rerunotel.Execute(ctx, r)
+//
+// Attempt spans are annotated with their iteration number and the waiting
+// period that preceded them and, when an attempt fails, its error.
+package rerunotel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// ScopeName is the instrumentation scope name used by the Tracers of this
+// package.
+const ScopeName = "github.com/olympiclabs/rerun/rerunotel"
+
+// Attribute keys used by the spans of this package.
+const (
+	AttemptKey     = attribute.Key("rerun.attempt")      // the attempt's iteration number
+	AttemptsKey    = attribute.Key("rerun.attempts")     // the number of attempts made
+	ExecutionIDKey = attribute.Key("rerun.execution_id") // see rerun.ExecutionIDFromContext
+	IterationsKey  = attribute.Key("rerun.iterations")   // the Rerun's configured iterations
+	NameKey        = attribute.Key("rerun.name")         // see rerun.Rerun.WithName
+	WaitKey        = attribute.Key("rerun.wait")         // the preceding wait, in seconds
+)
+
+// Option configures the tracing provided by this package.
+type Option func(*config)
+
+type config struct {
+	tp trace.TracerProvider
+}
+
+// WithTracerProvider returns an Option causing spans to be created using the
+// given TracerProvider in place of the global TracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tp = tp
+	}
+}
+
+func (c *config) tracer() trace.Tracer {
+	tp := c.tp
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(ScopeName)
+}
+
+func newConfig(opts []Option) *config {
+	c := new(config)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Execute calls r.Execute under a new span, named "rerun.Execute", whose
+// attempts are traced by child spans (see Middleware). The span records the
+// execution's ID, the number of attempts made and, should Execute fail, the
+// error it returned.
+func Execute(ctx context.Context, r *rerun.Rerun, opts ...Option) error {
+	c := newConfig(opts)
+
+	attrs := []attribute.KeyValue{IterationsKey.Int64(int64(min(r.Iterations(), 1<<63-1)))}
+	if name := r.Name(); name != "" {
+		attrs = append(attrs, NameKey.String(name))
+	}
+
+	ctx, span := c.tracer().Start(ctx, "rerun.Execute", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	rp, err := r.WithMiddleware(c.middleware()).ExecuteReport(ctx)
+
+	span.SetAttributes(AttemptsKey.Int(len(rp.Attempts)))
+	if rp.ExecutionID != "" {
+		span.SetAttributes(ExecutionIDKey.String(rp.ExecutionID))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// Middleware returns a rerun.Middleware tracing each attempt with a span,
+// named "rerun.attempt", which is a child of any span carried by the Context
+// given to Execute. Each span records the attempt's iteration number and the
+// waiting period that preceded it and, should the attempt fail (or panic),
+// its error. Middleware is used by Execute and need only be attached
+// directly when r.Execute is called some other way.
+func Middleware(opts ...Option) rerun.Middleware {
+	return newConfig(opts).middleware()
+}
+
+func (c *config) middleware() rerun.Middleware {
+	tracer := c.tracer()
+
+	return func(next rerun.ContextFunc) rerun.ContextFunc {
+		return func(ctx context.Context, i uint) (err error) {
+			attrs := []attribute.KeyValue{AttemptKey.Int64(int64(i))}
+			if a, ok := rerun.AttemptFromContext(ctx); ok {
+				attrs = append(attrs, WaitKey.Float64(a.Wait.Seconds()))
+			}
+
+			ctx, span := tracer.Start(ctx, "rerun.attempt", trace.WithAttributes(attrs...))
+			defer span.End()
+
+			defer func() {
+				if v := recover(); v != nil {
+					span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", v))
+					panic(v)
+				}
+			}()
+
+			if err = next(ctx, i); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return err
+		}
+	}
+}
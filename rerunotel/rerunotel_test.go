@@ -0,0 +1,129 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerunotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestExecute(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		sr := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+		boom := errors.New("boom")
+		r := rerun.New(3).WithName("fetch").WithAlgorithm(rerun.Fixed{Start: time.Second, Delay: 2 * time.Second}).WithFunction(func(i uint) error {
+			if i < 2 {
+				return rerun.ErrDoRetry.Wrap(boom)
+			}
+			return nil
+		})
+
+		if err := Execute(context.Background(), r, WithTracerProvider(tp)); err != nil {
+			t.Fatalf("Execute() == %v", err)
+		}
+
+		spans := sr.Ended()
+		if len(spans) != 4 {
+			t.Fatalf("got %d spans; wanted 4", len(spans))
+		}
+
+		parent := spans[3]
+		if parent.Name() != "rerun.Execute" {
+			t.Fatalf("parent span named %q; wanted %q", parent.Name(), "rerun.Execute")
+		}
+
+		pattrs := attrMap(parent.Attributes())
+		if got := pattrs[NameKey].AsString(); got != "fetch" {
+			t.Errorf("%s == %q; wanted %q", NameKey, got, "fetch")
+		}
+		if got := pattrs[IterationsKey].AsInt64(); got != 3 {
+			t.Errorf("%s == %d; wanted 3", IterationsKey, got)
+		}
+		if got := pattrs[AttemptsKey].AsInt64(); got != 3 {
+			t.Errorf("%s == %d; wanted 3", AttemptsKey, got)
+		}
+		if got := pattrs[ExecutionIDKey].AsString(); got == "" {
+			t.Errorf("%s is empty", ExecutionIDKey)
+		}
+		if got := parent.Status().Code; got != codes.Unset {
+			t.Errorf("parent status == %v; wanted %v", got, codes.Unset)
+		}
+
+		waits := []float64{1, 2, 2}
+		for i, s := range spans[:3] {
+			if s.Name() != "rerun.attempt" {
+				t.Errorf("span %d named %q; wanted %q", i, s.Name(), "rerun.attempt")
+			}
+			if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span %d is not a child of the Execute span", i)
+			}
+
+			attrs := attrMap(s.Attributes())
+			if got := attrs[AttemptKey].AsInt64(); got != int64(i) {
+				t.Errorf("span %d: %s == %d; wanted %d", i, AttemptKey, got, i)
+			}
+			if got := attrs[WaitKey].AsFloat64(); got != waits[i] {
+				t.Errorf("span %d: %s == %v; wanted %v", i, WaitKey, got, waits[i])
+			}
+
+			want := codes.Error
+			if i == 2 {
+				want = codes.Unset
+			}
+			if got := s.Status().Code; got != want {
+				t.Errorf("span %d status == %v; wanted %v", i, got, want)
+			}
+		}
+	})
+}
+
+func TestExecuteFailure(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		sr := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+		r := rerun.New(2).WithAlgorithm(rerun.Fixed{Delay: time.Second}).WithFunction(func(uint) error {
+			panic("boom")
+		}).WithRetryOnPanic(0)
+
+		err := Execute(context.Background(), r, WithTracerProvider(tp))
+		if !errors.Is(err, rerun.ErrAttemptsExhausted) {
+			t.Fatalf("Execute() == %v; wanted %v", err, rerun.ErrAttemptsExhausted)
+		}
+
+		spans := sr.Ended()
+		if len(spans) != 3 {
+			t.Fatalf("got %d spans; wanted 3", len(spans))
+		}
+
+		for i, s := range spans {
+			if got := s.Status().Code; got != codes.Error {
+				t.Errorf("span %q (%d) status == %v; wanted %v", s.Name(), i, got, codes.Error)
+			}
+		}
+
+		if got := spans[0].Status().Description; got != "panic: boom" {
+			t.Errorf("attempt status == %q; wanted %q", got, "panic: boom")
+		}
+	})
+}
+
+func attrMap(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
@@ -0,0 +1,127 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package reruntest provides utilities for testing code that relies on the
+// rerun package.
+package reruntest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// FakeClock is a rerun.Clock whose time only moves when explicitly advanced,
+// allowing tests of code using Rerun.Execute to run instantly and
+// deterministically. Attach one to a Rerun using its WithClock method.
+//
+// A FakeClock is safe for concurrent use; the typical pattern is to run
+// Execute in one goroutine while the test goroutine calls BlockUntilTimers
+// followed by Advance to step through each waiting period.
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+var _ rerun.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a new FakeClock whose current time is t.
+func NewFakeClock(t time.Time) *FakeClock {
+	fc := &FakeClock{now: t}
+	fc.cond = sync.NewCond(&fc.mu)
+	return fc
+}
+
+// Now returns the receiver's current time.
+func (fc *FakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+// NewTimer returns a rerun.Timer that fires once the receiver has been
+// advanced by at least d. A Timer with a non-positive d fires immediately.
+func (fc *FakeClock) NewTimer(d time.Duration) rerun.Timer {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	ft := &fakeTimer{clock: fc, when: fc.now.Add(d), ch: make(chan time.Time, 1)}
+
+	if d <= 0 {
+		ft.ch <- fc.now
+		return ft
+	}
+
+	fc.timers = append(fc.timers, ft)
+	fc.cond.Broadcast()
+
+	return ft
+}
+
+// Advance moves the receiver's current time forward by d, firing all timers
+// whose deadlines have been reached.
+func (fc *FakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.now = fc.now.Add(d)
+
+	pending := fc.timers[:0]
+	for _, ft := range fc.timers {
+		if ft.when.After(fc.now) {
+			pending = append(pending, ft)
+		} else {
+			ft.ch <- fc.now
+		}
+	}
+
+	clear(fc.timers[len(pending):])
+	fc.timers = pending
+	fc.cond.Broadcast()
+}
+
+// BlockUntilTimers blocks until at least n timers are waiting to fire.
+func (fc *FakeClock) BlockUntilTimers(n int) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	for len(fc.timers) < n {
+		fc.cond.Wait()
+	}
+}
+
+// Timers returns the number of timers currently waiting to fire.
+func (fc *FakeClock) Timers() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return len(fc.timers)
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	when  time.Time
+	ch    chan time.Time
+}
+
+func (ft *fakeTimer) C() <-chan time.Time {
+	return ft.ch
+}
+
+func (ft *fakeTimer) Stop() bool {
+	fc := ft.clock
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	for i, t := range fc.timers {
+		if t == ft {
+			fc.timers = append(fc.timers[:i], fc.timers[i+1:]...)
+			fc.cond.Broadcast()
+			return true
+		}
+	}
+
+	return false
+}
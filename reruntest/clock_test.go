@@ -0,0 +1,116 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestFakeClockExecute(t *testing.T) {
+	var (
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		fc    = NewFakeClock(start)
+		calls []time.Time
+	)
+
+	r := rerun.New(4).
+		WithAlgorithm(rerun.LinearDelay{Start: time.Second, Base: time.Minute, Slope: float64(time.Minute)}).
+		WithClock(fc).
+		WithFunction(func(uint) error {
+			calls = append(calls, fc.Now())
+			return rerun.ErrDoRetry
+		})
+
+	done := make(chan error)
+	go func() { done <- r.Execute(context.Background()) }()
+
+	for _, d := range []time.Duration{time.Second, time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		fc.BlockUntilTimers(1)
+		fc.Advance(d)
+	}
+
+	if err := <-done; !errors.Is(err, rerun.ErrAttemptsExhausted) {
+		t.Errorf("Execute() returned %v; wanted %v", err, rerun.ErrAttemptsExhausted)
+	}
+
+	want := []time.Duration{time.Second, 61 * time.Second, 181 * time.Second, 361 * time.Second}
+	if len(calls) != len(want) {
+		t.Fatalf("Func called %d times; wanted %d", len(calls), len(want))
+	}
+
+	for i, c := range calls {
+		if got := c.Sub(start); got != want[i] {
+			t.Errorf("call %d at %v; wanted %v", i, got, want[i])
+		}
+	}
+}
+
+func TestFakeClockStop(t *testing.T) {
+	fc := NewFakeClock(time.Time{})
+	tm := fc.NewTimer(time.Second)
+
+	if !tm.Stop() {
+		t.Errorf("Stop() on pending timer returned false")
+	}
+
+	if fc.Timers() != 0 {
+		t.Errorf("Timers() == %d after Stop; wanted 0", fc.Timers())
+	}
+
+	if tm.Stop() {
+		t.Errorf("second Stop() returned true")
+	}
+}
+
+func TestFakeClockAttemptTimeout(t *testing.T) {
+	var (
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		fc    = NewFakeClock(start)
+		errs  []error
+	)
+
+	r := rerun.New(2).
+		WithAlgorithm(rerun.Fixed{Delay: time.Second}).
+		WithAttemptTimeout(time.Minute).
+		WithClock(fc).
+		WithContextFunction(func(ctx context.Context, i uint) error {
+			if dl, _ := ctx.Deadline(); !dl.Equal(fc.Now().Add(time.Minute)) {
+				t.Errorf("attempt %d: deadline %v; wanted %v", i, dl, fc.Now().Add(time.Minute))
+			}
+			<-ctx.Done()
+			errs = append(errs, ctx.Err())
+			return ctx.Err()
+		})
+
+	done := make(chan error)
+	go func() { done <- r.Execute(context.Background()) }()
+
+	// Each attempt's timeout, then the wait between them.
+	for _, d := range []time.Duration{time.Minute, time.Second, time.Minute} {
+		fc.BlockUntilTimers(1)
+		fc.Advance(d)
+	}
+
+	if err := <-done; !errors.Is(err, rerun.ErrAttemptsExhausted) {
+		t.Errorf("Execute() returned %v; wanted %v", err, rerun.ErrAttemptsExhausted)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("Func called %d times; wanted 2", len(errs))
+	}
+
+	for i, err := range errs {
+		if err != context.DeadlineExceeded {
+			t.Errorf("attempt %d: ctx.Err() == %v; wanted %v", i, err, context.DeadlineExceeded)
+		}
+	}
+
+	if got, want := fc.Now().Sub(start), 121*time.Second; got != want {
+		t.Errorf("Execute took %v; wanted %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"sync/atomic"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// FailNTimes returns a rerun.Func that returns err for its first n calls and
+// nil thereafter. If err is nil, rerun.ErrDoRetry is used instead. Calls are
+// counted across all uses of the returned Func, which is safe for concurrent
+// use.
+func FailNTimes(n uint, err error) rerun.Func {
+	if err == nil {
+		err = rerun.ErrDoRetry
+	}
+
+	var calls atomic.Uint64
+
+	return func(uint) error {
+		if calls.Add(1) <= uint64(n) {
+			return err
+		}
+		return nil
+	}
+}
+
+// Flaky returns a rerun.Func that succeeds (returning nil) with probability
+// successRate and otherwise returns rerun.ErrDoRetry. Outcomes are drawn from
+// rng which, if nil, uses the default source described by
+// rerun.RandomFloat64. Provide a seeded rng (see rerun.NewRandom) for a
+// reproducible sequence of outcomes.
+func Flaky(successRate float64, rng rerun.Random) rerun.Func {
+	return func(uint) error {
+		if rerun.RandomFloat64(rng) < successRate {
+			return nil
+		}
+		return rerun.ErrDoRetry
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestFailNTimes(t *testing.T) {
+	var attempts uint
+	f := FailNTimes(3, nil)
+
+	r := rerun.New(5).WithAlgorithm(rerun.FixedDelay(0)).WithFunction(func(i uint) error {
+		attempts++
+		return f(i)
+	})
+
+	if err := r.Execute(context.Background()); err != nil {
+		t.Errorf("Execute() returned unexpected error: %v", err)
+	}
+
+	if attempts != 4 {
+		t.Errorf("Execute() made %d attempts; wanted 4", attempts)
+	}
+
+	errFatal := errors.New("fatal")
+	if err := FailNTimes(1, errFatal)(0); err != errFatal {
+		t.Errorf("FailNTimes(1, %v)(0) == %v; wanted %v", errFatal, err, errFatal)
+	}
+}
+
+func TestFlaky(t *testing.T) {
+	f1 := Flaky(0.5, rerun.NewRandom(7))
+	f2 := Flaky(0.5, rerun.NewRandom(7))
+
+	for i := uint(0); i < 100; i++ {
+		if e1, e2 := f1(i), f2(i); e1 != e2 {
+			t.Fatalf("call %d: identically seeded Flaky funcs returned %v and %v", i, e1, e2)
+		}
+	}
+
+	if err := Flaky(1, nil)(0); err != nil {
+		t.Errorf("Flaky(1, nil)(0) == %v; wanted nil", err)
+	}
+
+	if err := Flaky(0, nil)(0); err != rerun.ErrDoRetry {
+		t.Errorf("Flaky(0, nil)(0) == %v; wanted %v", err, rerun.ErrDoRetry)
+	}
+}
+
+func TestAssertRun(t *testing.T) {
+	errFatal := errors.New("fatal")
+	r := rerun.New(5).WithAlgorithm(rerun.ExponentialDelay{Start: time.Second, Base: time.Second, Multiplier: 2})
+
+	AssertRun(t, r, Result{Attempts: 3, Waited: 4 * time.Second}, rerun.ErrDoRetry, rerun.ErrDoRetry, nil)
+	AssertRun(t, r, Result{Attempts: 2, Waited: 2 * time.Second, Err: errFatal}, rerun.ErrDoRetry, errFatal)
+	AssertRun(t, r, Result{Attempts: 5, Waited: 16 * time.Second, Err: rerun.ErrAttemptsExhausted},
+		rerun.ErrDoRetry, rerun.ErrDoRetry, rerun.ErrDoRetry, rerun.ErrDoRetry, rerun.ErrDoRetry)
+}
+
+func TestRunAttemptTimeout(t *testing.T) {
+	errFatal := errors.New("fatal")
+	r := rerun.New(3).WithAlgorithm(rerun.FixedDelay(time.Second)).WithAttemptTimeout(time.Millisecond)
+
+	// Scripted outcomes take no time at all, so no attempt ever times out.
+	for range 100 {
+		AssertRun(t, r, Result{Attempts: 1, Err: errFatal}, rerun.Permanent(errFatal))
+		AssertRun(t, r, Result{Attempts: 2, Waited: time.Second}, rerun.ErrDoRetry, nil)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// UpdateGoldenEnv names the environment variable that, when set to a
+// non-empty value, causes AssertGoldenSchedule to rewrite golden files
+// rather than compare against them.
+const UpdateGoldenEnv = "RERUN_UPDATE_GOLDEN"
+
+// RenderSchedule returns a stable, human-readable rendering of the schedule
+// algo would produce for a Rerun of n iterations: the result of OK, the
+// warmup period, each wait period, and the total of all of them.
+//
+// Randomized Algorithms should be given a seeded rerun.Random (see
+// rerun.NewRandom) so that their rendering is reproducible.
+func RenderSchedule(algo rerun.Algorithm, n uint) string {
+	var (
+		sb    strings.Builder
+		total time.Duration
+	)
+
+	fmt.Fprintf(&sb, "iterations: %d\n", n)
+	fmt.Fprintf(&sb, "ok: %v\n", algo.OK(n))
+
+	d := algo.Warmup()
+	total += d
+	fmt.Fprintf(&sb, "warmup: %v\n", d)
+
+	for i := uint(1); i < n; i++ {
+		d = algo.Wait(i)
+		total += d
+		fmt.Fprintf(&sb, "wait %d: %v\n", i, d)
+	}
+
+	fmt.Fprintf(&sb, "total: %v\n", total)
+
+	return sb.String()
+}
+
+// AssertGoldenSchedule compares the RenderSchedule output for algo and n
+// against the contents of the golden file at path, reporting a test error
+// if they differ. This ensures that changes to an Algorithm's curve are
+// caught during review rather than discovered in production.
+//
+// If the environment variable named by UpdateGoldenEnv is set, the golden
+// file (and any missing parent directories) is written instead.
+func AssertGoldenSchedule(t testing.TB, algo rerun.Algorithm, n uint, path string) {
+	t.Helper()
+
+	got := RenderSchedule(algo, n)
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden schedule (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+
+	if got != string(want) {
+		t.Errorf("schedule differs from %s (set %s=1 to update)\n--- got:\n%s--- wanted:\n%s", path, UpdateGoldenEnv, got, want)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// Call describes a single call to the Warmup or Wait method of an Algorithm
+// wrapped by a Recorder.
+type Call struct {
+	// Method is either "Warmup" or "Wait".
+	Method string
+
+	// N is the iteration number passed to Wait; it is always zero for
+	// calls to Warmup.
+	N uint
+
+	// Value is the Duration returned by the wrapped Algorithm.
+	Value time.Duration
+}
+
+// Recorder is a rerun.Algorithm that wraps another Algorithm, recording every
+// call made to its Warmup and Wait methods along with the values returned.
+// This makes it straightforward to verify that Execute consulted the schedule
+// as expected. A Recorder is safe for concurrent use.
+type Recorder struct {
+	algo rerun.Algorithm
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ rerun.Algorithm = (*Recorder)(nil)
+
+// NewRecorder returns a new Recorder wrapping algo.
+func NewRecorder(algo rerun.Algorithm) *Recorder {
+	return &Recorder{algo: algo}
+}
+
+// OK returns the result of calling OK on the wrapped Algorithm. Calls to OK
+// are not recorded.
+func (rec *Recorder) OK(n uint) error {
+	return rec.algo.OK(n)
+}
+
+// Warmup records and returns the result of calling Warmup on the wrapped
+// Algorithm.
+func (rec *Recorder) Warmup() time.Duration {
+	d := rec.algo.Warmup()
+	rec.record(Call{Method: "Warmup", Value: d})
+	return d
+}
+
+// Wait records and returns the result of calling Wait on the wrapped
+// Algorithm.
+func (rec *Recorder) Wait(n uint) time.Duration {
+	d := rec.algo.Wait(n)
+	rec.record(Call{Method: "Wait", N: n, Value: d})
+	return d
+}
+
+func (rec *Recorder) record(c Call) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.calls = append(rec.calls, c)
+}
+
+// Calls returns all calls recorded so far, in the order they were made.
+func (rec *Recorder) Calls() []Call {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return slices.Clone(rec.calls)
+}
+
+// Reset discards all calls recorded so far.
+func (rec *Recorder) Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.calls = nil
+}
+
+// Warmups returns the number of calls recorded to Warmup.
+func (rec *Recorder) Warmups() int {
+	var n int
+	for _, c := range rec.Calls() {
+		if c.Method == "Warmup" {
+			n++
+		}
+	}
+	return n
+}
+
+// Waits returns the iteration numbers passed to, and the values returned by,
+// each recorded call to Wait.
+func (rec *Recorder) Waits() ([]uint, []time.Duration) {
+	var (
+		iters []uint
+		waits []time.Duration
+	)
+
+	for _, c := range rec.Calls() {
+		if c.Method == "Wait" {
+			iters = append(iters, c.N)
+			waits = append(waits, c.Value)
+		}
+	}
+
+	return iters, waits
+}
+
+// AssertWarmups reports a test error if Warmup was not called exactly want
+// times.
+func (rec *Recorder) AssertWarmups(t testing.TB, want int) {
+	t.Helper()
+	if got := rec.Warmups(); got != want {
+		t.Errorf("Warmup called %d times; wanted %d", got, want)
+	}
+}
+
+// AssertWaitIterations reports a test error if Wait was not called with
+// exactly the given iteration numbers, in order.
+func (rec *Recorder) AssertWaitIterations(t testing.TB, want ...uint) {
+	t.Helper()
+	if got, _ := rec.Waits(); !slices.Equal(got, want) {
+		t.Errorf("Wait called for iterations %v; wanted %v", got, want)
+	}
+}
+
+// AssertWaits reports a test error if the values returned by Wait were not
+// exactly those given, in order.
+func (rec *Recorder) AssertWaits(t testing.TB, want ...time.Duration) {
+	t.Helper()
+	if _, got := rec.Waits(); !slices.Equal(got, want) {
+		t.Errorf("Wait returned %v; wanted %v", got, want)
+	}
+}
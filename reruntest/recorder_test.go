@@ -0,0 +1,24 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder(rerun.LinearDelay{Base: time.Nanosecond, Slope: 1})
+
+	r := rerun.New(4).WithAlgorithm(rec).WithFunction(FailNTimes(2, nil))
+	if err := r.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() returned unexpected error: %v", err)
+	}
+
+	rec.AssertWarmups(t, 1)
+	rec.AssertWaitIterations(t, 1, 2)
+	rec.AssertWaits(t, 1, 2)
+}
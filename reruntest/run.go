@@ -0,0 +1,163 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package reruntest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// ErrScriptExhausted is returned by a scripted Func called more times than it
+// has outcomes.
+const ErrScriptExhausted = rerun.Error("scripted outcomes exhausted")
+
+// InstantClock is a rerun.Clock whose timers fire immediately, advancing the
+// clock's time by their full duration as they do. This lets Execute run its
+// full schedule without any real delay while still accounting for the time
+// it would have spent waiting. An InstantClock is safe for concurrent use.
+//
+// Take note that an attempt timeout (see rerun.Rerun.WithAttemptTimeout)
+// measured by an InstantClock expires as soon as it is armed; use a FakeClock
+// to exercise attempt timeouts.
+type InstantClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	elapsed time.Duration
+}
+
+var _ rerun.Clock = (*InstantClock)(nil)
+
+// NewInstantClock returns a new InstantClock whose current time is t.
+func NewInstantClock(t time.Time) *InstantClock {
+	return &InstantClock{now: t}
+}
+
+// Now returns the receiver's current time.
+func (ic *InstantClock) Now() time.Time {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	return ic.now
+}
+
+// NewTimer advances the receiver's time by d and returns a Timer that has
+// already fired.
+func (ic *InstantClock) NewTimer(d time.Duration) rerun.Timer {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if d > 0 {
+		ic.now = ic.now.Add(d)
+		ic.elapsed += d
+	}
+
+	ft := make(firedTimer, 1)
+	ft <- ic.now
+
+	return ft
+}
+
+// firedTimer is a rerun.Timer that has already fired.
+type firedTimer chan time.Time
+
+func (ft firedTimer) C() <-chan time.Time {
+	return ft
+}
+
+func (firedTimer) Stop() bool {
+	return false
+}
+
+// Elapsed returns the total duration of all timers created by the receiver.
+func (ic *InstantClock) Elapsed() time.Duration {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	return ic.elapsed
+}
+
+// Script returns a rerun.Func returning each of the given outcomes in turn.
+// Once all outcomes have been returned, further calls return
+// ErrScriptExhausted.
+func Script(outcomes ...error) rerun.Func {
+	var (
+		mu   sync.Mutex
+		next int
+	)
+
+	return func(uint) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if next >= len(outcomes) {
+			return ErrScriptExhausted
+		}
+
+		next++
+		return outcomes[next-1]
+	}
+}
+
+// Result describes the outcome of a call to Run.
+type Result struct {
+	// Attempts is the number of times the Func was called.
+	Attempts int
+
+	// Waited is the total simulated time spent waiting (including any
+	// warmup period).
+	Waited time.Duration
+
+	// Err is the error returned by Execute.
+	Err error
+}
+
+// Run executes r against a Func returning the scripted outcomes (see Script)
+// using an InstantClock, so that it completes without any real delay, and
+// returns a Result describing what happened. Any Func or Clock previously
+// attached to r is ignored, as is any attempt timeout since scripted outcomes
+// take no time at all.
+func Run(r *rerun.Rerun, outcomes ...error) Result {
+	var (
+		res    Result
+		ic     = NewInstantClock(time.Time{})
+		script = Script(outcomes...)
+	)
+
+	// n.b. Any invalid attempt timeout is reported before it is discarded.
+	if res.Err = r.Err(); res.Err != nil {
+		return res
+	}
+
+	res.Err = r.WithClock(ic).WithAttemptTimeout(0).WithFunction(func(i uint) error {
+		res.Attempts++
+		return script(i)
+	}).Execute(context.Background())
+
+	res.Waited = ic.Elapsed()
+
+	return res
+}
+
+// AssertRun calls Run with r and outcomes, reporting a test error if the
+// number of attempts or total simulated wait differs from want, or if the
+// returned error does not match want.Err according to errors.Is.
+func AssertRun(t testing.TB, r *rerun.Rerun, want Result, outcomes ...error) {
+	t.Helper()
+
+	got := Run(r, outcomes...)
+
+	if got.Attempts != want.Attempts {
+		t.Errorf("Execute made %d attempts; wanted %d", got.Attempts, want.Attempts)
+	}
+
+	if got.Waited != want.Waited {
+		t.Errorf("Execute waited %v; wanted %v", got.Waited, want.Waited)
+	}
+
+	if !errors.Is(got.Err, want.Err) {
+		t.Errorf("Execute returned error %v; wanted %v", got.Err, want.Err)
+	}
+}
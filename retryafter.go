@@ -0,0 +1,152 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetryAfter returns an error that a Func may return to request a retry
+// after the given wait period instead of the one calculated by the Rerun's
+// Algorithm. This is typically used to honor hints provided by a server,
+// such as an HTTP Retry-After header.
+//
+// Unless hint smoothing is configured (see WithHintSmoothing), Execute waits
+// for exactly d before the next attempt -- returning early, as it does for
+// any other waiting period, if its Context becomes done. A negative d causes
+// Execute to return ErrNegativeDuration. See also RetryAfterCause.
+func RetryAfter(d time.Duration) error {
+	return &RetryAfterError{Delay: d}
+}
+
+// RetryAfterCause is like RetryAfter but also records err as the cause of
+// the failed attempt (e.g. the error describing an HTTP 429 response), so
+// that it remains available to errors.Is and errors.As, hooks and Reports.
+func RetryAfterCause(d time.Duration, err error) error {
+	return &RetryAfterError{Delay: d, Err: err}
+}
+
+// RetryAfterError is the error type returned by RetryAfter and
+// RetryAfterCause.
+type RetryAfterError struct {
+	// Delay is the requested waiting period.
+	Delay time.Duration
+
+	// Err is the cause given to RetryAfterCause, if any.
+	Err error
+}
+
+func (e *RetryAfterError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v after %v", ErrDoRetry, e.Delay)
+	}
+	return fmt.Sprintf("%v after %v: %v", ErrDoRetry, e.Delay, e.Err)
+}
+
+// Unwrap returns the cause given to RetryAfterCause, if any.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrDoRetry, which allows a *RetryAfterError
+// to be recognized as a retry signal by errors.Is.
+func (e *RetryAfterError) Is(target error) bool {
+	return target == ErrDoRetry
+}
+
+// HintSmoothing defines how Execute smooths the wait periods requested by
+// its Func through RetryAfter. Each hint is blended with those preceding it
+// using an exponentially weighted moving average and then clamped to the
+// range from Min to Max. This prevents wildly varying hints from a confused
+// upstream from driving pathological oscillation in the retry schedule.
+type HintSmoothing struct {
+	// Alpha is the weight given to each new hint, and must be greater than
+	// zero and no more than 1. An Alpha of 1 disables averaging, leaving
+	// only clamping in effect.
+	Alpha float64
+
+	// Min is the shortest wait period that may be derived from a hint. It
+	// cannot be negative.
+	Min time.Duration
+
+	// Max is the longest wait period that may be derived from a hint. A
+	// zero value imposes no upper bound; otherwise, Max cannot be less than
+	// Min.
+	Max time.Duration
+}
+
+// OK returns an error if the receiver's fields are invalid. All invalid
+// fields are reported together (using errors.Join).
+func (hs HintSmoothing) OK() error {
+	var errs []error
+
+	if hs.Alpha <= 0 || hs.Alpha > 1 {
+		errs = append(errs, fieldError("HintSmoothing.Alpha", hs.Alpha, ErrInvalidSmoothing))
+	}
+
+	if hs.Min < 0 {
+		errs = append(errs, fieldError("HintSmoothing.Min", hs.Min, ErrNegativeDuration))
+	}
+
+	switch {
+	case hs.Max < 0:
+		errs = append(errs, fieldError("HintSmoothing.Max", hs.Max, ErrNegativeDuration))
+
+	case hs.Max != 0 && hs.Max < hs.Min:
+		errs = append(errs, fieldError("HintSmoothing.Max", hs.Max, ErrInvalidSmoothing))
+	}
+
+	return joinErrors(errs...)
+}
+
+// WithHintSmoothing returns a pointer to its receiver after configuring it
+// to smooth any wait periods requested through RetryAfter according to hs.
+// If hs is invalid, subsequent calls to the receiver's Err method will
+// return a non-nil error.
+func (r Rerun) WithHintSmoothing(hs HintSmoothing) *Rerun {
+	r.smoothing = &hs
+	return &r
+}
+
+// hintSmoother holds the smoothing state for a single call to Execute.
+type hintSmoother struct {
+	HintSmoothing
+	avg  float64
+	seen bool
+}
+
+func newHintSmoother(hs *HintSmoothing) *hintSmoother {
+	if hs == nil {
+		return nil
+	}
+	return &hintSmoother{HintSmoothing: *hs}
+}
+
+// next returns the wait period to be used for the given hint. A nil receiver
+// returns the hint unaltered, as do negative hints (so that Execute may
+// reject them).
+func (s *hintSmoother) next(hint time.Duration) time.Duration {
+	if s == nil || hint < 0 {
+		return hint
+	}
+
+	if s.seen {
+		s.avg = s.Alpha*float64(hint) + (1-s.Alpha)*s.avg
+	} else {
+		s.avg = float64(hint)
+		s.seen = true
+	}
+
+	d := time.Duration(s.avg)
+
+	if d < s.Min {
+		d = s.Min
+	}
+
+	if s.Max != 0 && d > s.Max {
+		d = s.Max
+	}
+
+	return d
+}
@@ -0,0 +1,106 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRetryAfterIsRetry(t *testing.T) {
+	if err := RetryAfter(time.Second); !errors.Is(err, ErrDoRetry) {
+		t.Errorf("errors.Is(%v, ErrDoRetry) == false; wanted true", err)
+	}
+}
+
+func TestHintSmoother(t *testing.T) {
+	s := newHintSmoother(&HintSmoothing{
+		Alpha: 0.5,
+		Min:   100 * time.Millisecond,
+		Max:   5 * time.Second,
+	})
+
+	for i, tc := range []struct {
+		hint, want time.Duration
+	}{
+		{1 * time.Second, 1 * time.Second},
+		{3 * time.Second, 2 * time.Second},
+		{0, 1 * time.Second},
+		{0, 500 * time.Millisecond},
+		{0, 250 * time.Millisecond},
+		{0, 125 * time.Millisecond},
+		{0, 100 * time.Millisecond},
+		{time.Minute, 5 * time.Second},
+	} {
+		if got := s.next(tc.hint); got != tc.want {
+			t.Errorf("%d: next(%v) == %v; wanted %v", i, tc.hint, got, tc.want)
+		}
+	}
+}
+
+func TestHintSmoothingOK(t *testing.T) {
+	for _, tc := range []struct {
+		hs   HintSmoothing
+		want error
+	}{
+		{HintSmoothing{Alpha: 1}, nil},
+		{HintSmoothing{Alpha: 0.3, Min: time.Second}, nil},
+		{HintSmoothing{Alpha: 0}, ErrInvalidSmoothing},
+		{HintSmoothing{Alpha: 1.5}, ErrInvalidSmoothing},
+		{HintSmoothing{Alpha: 1, Min: time.Second, Max: time.Millisecond}, ErrInvalidSmoothing},
+		{HintSmoothing{Alpha: 1, Min: -time.Second}, ErrNegativeDuration},
+	} {
+		if got := tc.hs.OK(); !errors.Is(got, tc.want) {
+			t.Errorf("%+v.OK() == %v; wanted %v", tc.hs, got, tc.want)
+		}
+	}
+}
+
+func TestExecuteRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		errThrottled := errors.New("429 Too Many Requests")
+
+		var (
+			calls int
+			at    []time.Duration
+		)
+
+		start := time.Now()
+		hints := []error{RetryAfterCause(5*time.Second, errThrottled), RetryAfter(time.Minute), RetryAfter(-time.Second)}
+		r := New(5).WithAlgorithm(Fixed1s).WithFunction(func(i uint) error {
+			calls++
+			at = append(at, time.Since(start))
+			return hints[i]
+		})
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrNegativeDuration) {
+			t.Errorf("Execute() == %v; wanted %v", err, ErrNegativeDuration)
+		}
+
+		if want := []time.Duration{0, 5 * time.Second, 65 * time.Second}; !slices.Equal(at, want) {
+			t.Errorf("attempts made at %v; wanted %v", at, want)
+		}
+
+		if err := hints[0]; !errors.Is(err, errThrottled) || !IsRetrySignal(err) {
+			t.Errorf("%v does not match both its cause and ErrDoRetry", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		calls, at, start = 0, nil, time.Now()
+		if err := r.Execute(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Execute() == %v; wanted %v", err, context.DeadlineExceeded)
+		}
+
+		if d := time.Since(start); calls != 2 || d != 30*time.Second {
+			t.Errorf("Execute() made %d calls in %v; wanted 2 calls in %v", calls, d, 30*time.Second)
+		}
+	})
+}
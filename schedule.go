@@ -0,0 +1,70 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleDelay is an Algorithm imposing an explicit list of waiting
+// periods: the wait before retry n is the n'th element, with the final
+// element repeated for any retries beyond the end of the list. It suits
+// hand-tuned policies that no formula expresses neatly.
+type ScheduleDelay []time.Duration
+
+// Schedule returns a ScheduleDelay imposing the given waiting periods, e.g.
+//
+//	rerun.Schedule(100*time.Millisecond, 500*time.Millisecond, 2*time.Second, 10*time.Second)
+func Schedule(waits ...time.Duration) ScheduleDelay {
+	return ScheduleDelay(waits)
+}
+
+// OK returns an error if the receiver is empty or any of its waiting periods
+// is negative. All problems found are reported together (using errors.Join).
+// This method contributes to implementing the Algorithm interface.
+func (sd ScheduleDelay) OK(uint) error {
+	if len(sd) == 0 {
+		return ErrEmptySchedule
+	}
+
+	var errs []error
+
+	for i, d := range sd {
+		if d < 0 {
+			errs = append(errs, fieldError(fmt.Sprintf("ScheduleDelay[%d]", i), d, ErrNegativeDuration))
+		}
+	}
+
+	return joinErrors(errs...)
+}
+
+// Warmup always returns zero since a ScheduleDelay has no warmup period.
+// This method contributes to implementing the Algorithm interface.
+func (ScheduleDelay) Warmup() time.Duration {
+	return 0
+}
+
+// Wait returns the n'th element of the receiver, or its last element if n
+// exceeds its length.
+// Wait is part of the Algorithm interface.
+func (sd ScheduleDelay) Wait(n uint) time.Duration {
+	if n == 0 || len(sd) == 0 {
+		return 0
+	}
+	return sd[min(n, uint(len(sd)))-1]
+}
+
+// Deterministic always returns true.
+func (ScheduleDelay) Deterministic() bool {
+	return true
+}
+
+func (sd ScheduleDelay) String() string {
+	s := make([]string, len(sd))
+	for i, d := range sd {
+		s[i] = d.String()
+	}
+	return "schedule " + strings.Join(s, ", ")
+}
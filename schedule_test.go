@@ -0,0 +1,32 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScheduleDelay(t *testing.T) {
+	ms := time.Millisecond
+	sd := Schedule(100*ms, 500*ms, 2*time.Second, 10*time.Second)
+
+	if err := CheckAlgorithm(sd, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Duration{100 * ms, 500 * ms, 2 * time.Second, 10 * time.Second, 10 * time.Second, 10 * time.Second}
+	if got := New(7).WithAlgorithm(sd).Waits(); !slices.Equal(got, want) {
+		t.Errorf("Waits() == %v; wanted %v", got, want)
+	}
+
+	if err := Schedule().OK(2); err != ErrEmptySchedule {
+		t.Errorf("OK() for empty schedule == %v; wanted %v", err, ErrEmptySchedule)
+	}
+
+	if err := Schedule(ms, -ms).OK(2); !errors.Is(err, ErrNegativeDuration) {
+		t.Errorf("OK() for negative wait == %v; wanted %v", err, ErrNegativeDuration)
+	}
+}
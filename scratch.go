@@ -0,0 +1,100 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"sync"
+)
+
+// ScratchKey identifies a value of type T held in the scratchpad that
+// Execute creates for each of its calls. The scratchpad allows successive
+// attempts to share state -- such as a cursor position, partial results or a
+// refreshed credential -- without the caller having to capture a mutable
+// struct in a closure. Since each call to Execute has its own scratchpad,
+// a single Rerun may still be executed concurrently.
+//
+// The scratchpad is reached through the Context passed to a ContextFunc
+// (see WithContextFunction). Each ScratchKey is distinct from all others,
+// even those created with the same name, so a ScratchKey is typically
+// declared once as a package-level variable:
+//
+//	var cursor = rerun.NewScratchKey[string]("cursor")
+type ScratchKey[T any] struct {
+	name string
+}
+
+// NewScratchKey returns a new ScratchKey for values of type T. The given name
+// is used only for debugging.
+func NewScratchKey[T any](name string) *ScratchKey[T] {
+	return &ScratchKey[T]{name: name}
+}
+
+func (k *ScratchKey[T]) String() string {
+	return "rerun.ScratchKey(" + k.name + ")"
+}
+
+// Load returns the value stored for the receiver in the scratchpad reachable
+// from ctx. If no value has been stored (or ctx has no scratchpad because it
+// did not originate from Execute) the zero value of T and false are returned.
+func (k *ScratchKey[T]) Load(ctx context.Context) (T, bool) {
+	var zero T
+
+	sp := scratchpadFrom(ctx)
+	if sp == nil {
+		return zero, false
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	v, ok := sp.values[k]
+	if !ok {
+		return zero, false
+	}
+
+	return v.(T), true
+}
+
+// Store saves v for the receiver in the scratchpad reachable from ctx,
+// replacing any previous value. Store returns false, having done nothing,
+// if ctx has no scratchpad because it did not originate from Execute.
+func (k *ScratchKey[T]) Store(ctx context.Context, v T) bool {
+	sp := scratchpadFrom(ctx)
+	if sp == nil {
+		return false
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if sp.values == nil {
+		sp.values = make(map[any]any)
+	}
+	sp.values[k] = v
+
+	return true
+}
+
+// Delete removes any value stored for the receiver in the scratchpad
+// reachable from ctx.
+func (k *ScratchKey[T]) Delete(ctx context.Context) {
+	if sp := scratchpadFrom(ctx); sp != nil {
+		sp.mu.Lock()
+		delete(sp.values, k)
+		sp.mu.Unlock()
+	}
+}
+
+// scratchpad holds the per-execution values for all ScratchKeys.
+type scratchpad struct {
+	mu     sync.Mutex
+	values map[any]any
+}
+
+func scratchpadFrom(ctx context.Context) *scratchpad {
+	if e := executionFrom(ctx); e != nil {
+		return &e.scratch
+	}
+	return nil
+}
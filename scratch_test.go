@@ -0,0 +1,48 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestScratchpad(t *testing.T) {
+	cursor := NewScratchKey[int]("cursor")
+	other := NewScratchKey[int]("cursor")
+
+	var seen []int
+
+	r := New(3).WithAlgorithm(FixedDelay(0)).WithContextFunction(func(ctx context.Context, i uint) error {
+		c, ok := cursor.Load(ctx)
+		if ok != (i > 0) {
+			t.Errorf("attempt %d: Load() reported %v", i, ok)
+		}
+
+		if _, ok := other.Load(ctx); ok {
+			t.Errorf("attempt %d: distinct key with same name shares a value", i)
+		}
+
+		seen = append(seen, c)
+		cursor.Store(ctx, c+10)
+
+		return ErrDoRetry
+	})
+
+	for range 2 {
+		seen = nil
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Fatalf("Execute() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if want := []int{0, 10, 20}; !slices.Equal(seen, want) {
+			t.Errorf("cursor values == %v; wanted %v", seen, want)
+		}
+	}
+
+	if cursor.Store(context.Background(), 1) {
+		t.Error("Store() outside of Execute returned true")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"sync"
+	"time"
+)
+
+// Sequence is a stateful progression through the waiting periods of an
+// Algorithm, for long-lived code driving its own retry loop (e.g. a worker
+// that reconnects whenever its connection drops) rather than calling
+// Execute. Each call to Next reports a failure and returns the waiting period
+// to impose before trying again; the first returns Wait(1), the second
+// Wait(2), and so on without limit.
+//
+// Once Quiet has elapsed since the most recent failure, the progression is
+// considered healthy again and the next failure starts it over from Wait(1),
+// so that a blip after an hour of success does not immediately incur the
+// longest waiting period reached during some earlier outage:
+//
+//	seq := &rerun.Sequence{Algorithm: algo, Quiet: 10 * time.Minute}
+//
+//	for ctx.Err() == nil {
+//		if err := serve(ctx); err != nil {
+//			time.Sleep(seq.Next())
+//		}
+//	}
+//
+// The zero value is ready to use, following the default Algorithm and never
+// starting over on its own. A Sequence is safe for concurrent use but must
+// not be copied after first use.
+type Sequence struct {
+	// Algorithm provides the waiting periods. If nil, the default used by
+	// New is used (see SetDefaultAlgorithm). Its Warmup period is not
+	// consulted. A stateful Algorithm (see Resetter) is reset each time the
+	// progression starts over.
+	Algorithm Algorithm
+
+	// Quiet is the period without failures after which the progression
+	// starts over. A zero (or negative) Quiet disables this, leaving Reset
+	// as the only way to start over.
+	Quiet time.Duration
+
+	// Clock measures Quiet. If nil, SystemClock is used.
+	Clock Clock
+
+	mu   sync.Mutex
+	algo Algorithm // the Algorithm in use since the progression started
+	n    uint      // the iteration most recently returned by Next
+	last time.Time // the time of the most recent call to Next
+}
+
+// Next reports a failure and returns the waiting period to impose before the
+// next attempt.
+func (s *Sequence) Next() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock().Now()
+	if s.Quiet > 0 && s.n > 0 && now.Sub(s.last) >= s.Quiet {
+		s.n = 0
+	}
+
+	if s.n == 0 {
+		s.algo = reset(s.algorithm())
+	}
+
+	s.n++
+	s.last = now
+
+	return s.algo.Wait(s.n)
+}
+
+// Reset starts the receiver's progression over, so that the next call to
+// Next returns Wait(1).
+func (s *Sequence) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.n = 0
+}
+
+func (s *Sequence) algorithm() Algorithm {
+	if s.Algorithm == nil {
+		return currentDefault()
+	}
+	return s.Algorithm
+}
+
+func (s *Sequence) clock() Clock {
+	if s.Clock == nil {
+		return SystemClock()
+	}
+	return s.Clock
+}
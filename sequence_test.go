@@ -0,0 +1,92 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestSequence(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		seq := &Sequence{
+			Algorithm: LinearDelay{Base: time.Second, Slope: float64(time.Second)},
+			Quiet:     time.Hour,
+		}
+
+		var got []time.Duration
+		next := func() {
+			d := seq.Next()
+			got = append(got, d)
+			time.Sleep(d)
+		}
+
+		// An outage: failures in quick succession progress as usual.
+		next()
+		next()
+		next()
+
+		// Less than Quiet after the last failure, progression continues.
+		time.Sleep(59 * time.Minute)
+		next()
+
+		// A healthy hour starts over.
+		time.Sleep(time.Hour)
+		next()
+		next()
+
+		// As does Reset.
+		seq.Reset()
+		next()
+
+		want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second, time.Second, 2 * time.Second, time.Second}
+		if !slices.Equal(got, want) {
+			t.Errorf("Next() returned %v; wanted %v", got, want)
+		}
+	})
+}
+
+func TestSequenceNoQuiet(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var seq Sequence
+
+		for i := 0; i < 3; i++ {
+			if d := seq.Next(); d != time.Second {
+				t.Errorf("Next() == %v; wanted %v", d, time.Second)
+			}
+			time.Sleep(24 * time.Hour)
+		}
+
+		if seq.n != 3 {
+			t.Errorf("progression at %d after 3 failures; wanted 3", seq.n)
+		}
+	})
+}
+
+func TestSequenceResetter(t *testing.T) {
+	var resets int
+	seq := &Sequence{Algorithm: &testResetter{resets: &resets}}
+
+	seq.Next()
+	seq.Next()
+	seq.Reset()
+	seq.Next()
+
+	if resets != 2 {
+		t.Errorf("Algorithm reset %d times; wanted 2", resets)
+	}
+}
+
+// testResetter waits a fixed second, counting its resets.
+type testResetter struct {
+	resets *int
+}
+
+func (tr *testResetter) OK(uint) error           { return nil }
+func (tr *testResetter) Warmup() time.Duration   { return 0 }
+func (tr *testResetter) Wait(uint) time.Duration { return time.Second }
+func (tr *testResetter) Reset() Algorithm        { *tr.resets++; return tr }
@@ -0,0 +1,70 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SignalError is the cause of cancellation for a Context returned by
+// NotifyContext once one of its signals has arrived. Since Execute returns
+// context.Cause(ctx) when its Context becomes done, this is the error
+// returned by an Execute interrupted by a signal.
+//
+// SignalError wraps ErrTerminated so it may be detected using errors.Is as
+// well as errors.As.
+type SignalError struct {
+	// Signal is the signal that was received.
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return ErrTerminated.Error() + ": " + e.Signal.String()
+}
+
+func (e *SignalError) Unwrap() error {
+	return ErrTerminated
+}
+
+// NotifyContext is similar to signal.NotifyContext except that the returned
+// Context's cause, once canceled by the arrival of a signal, is a *SignalError
+// identifying that signal. If no signals are given, os.Interrupt (SIGINT) and
+// syscall.SIGTERM are used. This allows long waits made by Execute in CLIs
+// and workers to be interrupted promptly and with a clear error:
+//
+//	ctx, stop := rerun.NotifyContext(context.Background())
+//	defer stop()
+//
+//	if err := r.Execute(ctx); errors.Is(err, rerun.ErrTerminated) {
+//		// ...
+//	}
+//
+// The stop function unregisters the signals, restoring their default
+// behavior, and releases the resources associated with the Context. It
+// should be called as soon as the Context is no longer needed.
+func NotifyContext(parent context.Context, signals ...os.Signal) (context.Context, context.CancelFunc) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			cancel(&SignalError{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(ch)
+		cancel(context.Canceled)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build unix
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyContext(t *testing.T) {
+	ctx, stop := NotifyContext(context.Background(), syscall.SIGUSR1)
+	defer stop()
+
+	r := New(2).WithAlgorithm(FixedDelay(time.Hour)).WithFunction(func(uint) error {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatal(err)
+		}
+		return ErrDoRetry
+	})
+
+	err := r.Execute(ctx)
+
+	var se *SignalError
+	if !errors.Is(err, ErrTerminated) || !errors.As(err, &se) || se.Signal != syscall.SIGUSR1 {
+		t.Errorf("Execute() == %v; wanted %v", err, &SignalError{Signal: syscall.SIGUSR1})
+	}
+}
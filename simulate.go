@@ -0,0 +1,114 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"slices"
+	"time"
+)
+
+// Distribution summarizes a set of sampled durations. Percentiles are
+// calculated using the nearest-rank method.
+type Distribution struct {
+	Min time.Duration
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+func newDistribution(s []time.Duration) Distribution {
+	slices.Sort(s)
+	return Distribution{
+		Min: s[0],
+		P50: percentile(s, 50),
+		P95: percentile(s, 95),
+		P99: percentile(s, 99),
+		Max: s[len(s)-1],
+	}
+}
+
+// ScheduleStats holds the distributional statistics gathered by
+// SimulateSchedule.
+type ScheduleStats struct {
+	// Samples is the number of complete schedules sampled.
+	Samples int
+
+	// Total describes the time needed to exhaust all iterations -- i.e. the
+	// warmup period plus every wait period -- assuming each attempt fails
+	// instantly. Its P99 and Max values quantify the worst case latency a
+	// retry policy may add.
+	Total Distribution
+
+	// Warmup describes the warmup period.
+	Warmup Distribution
+
+	// Waits describes each wait period; Waits[i-1] describes the values
+	// returned by Wait(i).
+	Waits []Distribution
+}
+
+// SimulateSchedule samples the complete schedule of algo, for a Rerun of n
+// iterations, the given number of times and returns statistics describing
+// the distribution of the results. This is mostly useful for randomized
+// (e.g. jittered) Algorithms, where it allows teams to quantify the latency
+// their retry policy may add; for deterministic Algorithms every sample is
+// identical.
+//
+// An error is returned if algo.OK(n) fails, n is less than 2, or samples is
+// less than 1. Since the schedule of an unlimited Rerun never completes,
+// ErrUnlimited is returned if n is Forever.
+func SimulateSchedule(algo Algorithm, n uint, samples int) (*ScheduleStats, error) {
+	if algo == nil {
+		return nil, ErrNilAlgorithm
+	}
+
+	if n < 2 {
+		return nil, ErrTooFewIterations
+	}
+
+	if n == Forever {
+		return nil, ErrUnlimited
+	}
+
+	if samples < 1 {
+		return nil, ErrTooFewSamples
+	}
+
+	if err := algo.OK(n); err != nil {
+		return nil, err
+	}
+
+	var (
+		totals  = make([]time.Duration, samples)
+		warmups = make([]time.Duration, samples)
+		waits   = make([][]time.Duration, n-1)
+	)
+
+	for i := range waits {
+		waits[i] = make([]time.Duration, samples)
+	}
+
+	for s := range samples {
+		warmups[s] = algo.Warmup()
+		totals[s] = warmups[s]
+
+		for i := uint(1); i < n; i++ {
+			waits[i-1][s] = algo.Wait(i)
+			totals[s] += waits[i-1][s]
+		}
+	}
+
+	ss := &ScheduleStats{
+		Samples: samples,
+		Total:   newDistribution(totals),
+		Warmup:  newDistribution(warmups),
+		Waits:   make([]Distribution, len(waits)),
+	}
+
+	for i, w := range waits {
+		ss.Waits[i] = newDistribution(w)
+	}
+
+	return ss, nil
+}
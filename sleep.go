@@ -4,10 +4,11 @@ package rerun
 
 import (
 	"context"
+	"sync"
 	"time"
 )
 
-func sleep(ctx context.Context, d time.Duration) error {
+func sleep(ctx context.Context, clk Clock, d time.Duration) error {
 	if d == 0 {
 		return nil
 	}
@@ -16,7 +17,7 @@ func sleep(ctx context.Context, d time.Duration) error {
 		return ErrNegativeDuration
 	}
 
-	t := newTimer(d)
+	t := clk.NewTimer(d)
 	defer t.Stop()
 
 	select {
@@ -27,13 +28,43 @@ func sleep(ctx context.Context, d time.Duration) error {
 	}
 }
 
-var newTimer = func(d time.Duration) timer {
-	return realTimer{time.NewTimer(d)}
+// Clock defines the source of time used by Rerun.Execute for measuring
+// attempts and imposing wait periods. Alternate implementations may be
+// attached to a Rerun using WithClock, most often to allow code relying on
+// Execute to be tested deterministically without real delays.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a Timer that delivers the current time on its channel
+	// after at least Duration d has elapsed.
+	NewTimer(d time.Duration) Timer
 }
 
-type timer interface {
-	Stop() bool
+// Timer is the subset of a *time.Timer's behavior needed by Execute.
+type Timer interface {
+	// C returns the channel on which the Timer delivers its time.
 	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing, returning false if it has
+	// already expired or been stopped.
+	Stop() bool
+}
+
+// SystemClock returns the Clock used by a Rerun if no other has been
+// attached. It is backed by the standard time package.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
 }
 
 type realTimer struct {
@@ -43,3 +74,109 @@ type realTimer struct {
 func (rt realTimer) C() <-chan time.Time {
 	return rt.Timer.C
 }
+
+// withClockTimeout is like context.WithTimeout except that its deadline is
+// measured by clk, allowing attempt timeouts to follow a fake Clock. If clk
+// keeps real time, context.WithTimeout is used instead.
+func withClockTimeout(ctx context.Context, clk Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if realTime(clk) {
+		return context.WithTimeout(ctx, d)
+	}
+
+	done, cancel := context.WithCancel(context.Background())
+	tc := &timeoutContext{
+		Context:  context.WithoutCancel(ctx),
+		parent:   ctx,
+		done:     done,
+		cancel:   cancel,
+		deadline: clk.Now().Add(d),
+	}
+
+	stop := context.AfterFunc(ctx, func() { tc.finish(ctx.Err()) })
+
+	t := clk.NewTimer(d)
+	go func() {
+		select {
+		case <-t.C():
+			tc.finish(context.DeadlineExceeded)
+		case <-done.Done():
+			t.Stop()
+		}
+		stop()
+	}()
+
+	return tc, func() { tc.finish(context.Canceled) }
+}
+
+// realTime reports whether clk keeps the time of the standard time package.
+func realTime(clk Clock) bool {
+	switch clk.(type) {
+	case systemClock, *TimerWheel:
+		return true
+	}
+	return false
+}
+
+// timeoutContext is the Context returned by withClockTimeout. It reports
+// context.DeadlineExceeded once its deadline passes -- as do any Contexts
+// derived from it, since its own Err is consulted when they are canceled.
+// Its values are those of its parent, less the parent's cancellation, so that
+// the context package treats it as a distinct source of cancellation.
+type timeoutContext struct {
+	context.Context // the parent, without its cancellation
+
+	parent   context.Context
+	done     context.Context
+	cancel   context.CancelFunc
+	deadline time.Time
+
+	once sync.Once
+	err  error // set before done is canceled
+}
+
+// finish marks the receiver as done with the given error, unless it already
+// is.
+func (tc *timeoutContext) finish(err error) {
+	tc.once.Do(func() {
+		tc.err = err
+		tc.cancel()
+	})
+}
+
+func (tc *timeoutContext) Deadline() (time.Time, bool) {
+	if dl, ok := tc.parent.Deadline(); ok && dl.Before(tc.deadline) {
+		return dl, true
+	}
+	return tc.deadline, true
+}
+
+func (tc *timeoutContext) Done() <-chan struct{} {
+	return tc.done.Done()
+}
+
+func (tc *timeoutContext) Err() error {
+	select {
+	case <-tc.done.Done():
+		return tc.err
+	default:
+		return nil
+	}
+}
+
+// AfterFunc arranges for f to be called once the receiver is done. Its
+// presence allows the context package to propagate the receiver's
+// cancellation to derived Contexts without starting a goroutine for each.
+func (tc *timeoutContext) AfterFunc(f func()) func() bool {
+	return context.AfterFunc(tc.done, f)
+}
+
+// WithoutSleep returns a pointer to its receiver after configuring Execute to
+// skip all warmup and waiting periods. Attempts are otherwise made exactly as
+// they would be -- the same iterations, the same handling of each error, the
+// same validation of each waiting period -- which allows integration tests to
+// exercise real retry logic without taking wall-clock minutes to do so.
+// Skipped periods are not counted in the receiver's Stats.
+func (r Rerun) WithoutSleep() *Rerun {
+	r.noSleep = true
+	return &r
+}
@@ -0,0 +1,145 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DeadlineSpread is an Algorithm that spaces a Rerun's attempts across the
+// time remaining before a hard completion deadline -- such as an SLA -- so
+// that the final attempt always lands just before that deadline expires.
+// Waiting periods form a geometric progression with each exceeding the one
+// before it by a factor of Ratio.
+//
+// Since each waiting period is recalculated from the time actually remaining
+// (see AlgorithmCtx), as measured by the Rerun's Clock, the schedule
+// self-corrects for time spent inside the Func. When no deadline is known, or
+// the execution is unlimited (see Forever), the Fallback Algorithm is used
+// instead.
+type DeadlineSpread struct {
+	// Deadline is the time by which all attempts must have been made. If
+	// zero, the deadline of the Context given to Execute is used.
+	Deadline time.Time
+
+	// Margin is the time before the deadline at which the final attempt is
+	// scheduled, allowing for that attempt to complete. A negative value
+	// will cause the OK method to return ErrNegativeDuration.
+	Margin time.Duration
+
+	// Ratio is the factor by which each waiting period exceeds the one
+	// before it; values below 1 front-load the attempts nearer the deadline.
+	// A zero value is treated as 1 (even spacing) while negative values
+	// cause the OK method to return ErrInvalidMultiplier.
+	Ratio float64
+
+	// Fallback is the Algorithm used when attempts cannot be spread. If
+	// nil, the default Algorithm (see SetDefaultAlgorithm) is used.
+	Fallback Algorithm
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the Algorithm interface.
+func (ds DeadlineSpread) OK(n uint) error {
+	var errs []error
+
+	if ds.Margin < 0 {
+		errs = append(errs,
+			fieldError("DeadlineSpread.Margin", ds.Margin, ErrNegativeDuration))
+	}
+
+	if ds.Ratio < 0 || math.IsInf(ds.Ratio, 0) || math.IsNaN(ds.Ratio) {
+		errs = append(errs,
+			fieldError("DeadlineSpread.Ratio", ds.Ratio, ErrInvalidMultiplier))
+	}
+
+	errs = append(errs, ds.fallback().OK(n))
+
+	return joinErrors(errs...)
+}
+
+// Warmup always returns zero; DeadlineSpread imposes no warmup period.
+func (DeadlineSpread) Warmup() time.Duration {
+	return 0
+}
+
+// Wait returns the waiting period from the receiver's Fallback Algorithm
+// since, lacking an Attempt, it cannot know how many attempts remain.
+// Execute calls WaitContext instead.
+func (ds DeadlineSpread) Wait(n uint) time.Duration {
+	return ds.fallback().Wait(n)
+}
+
+// WaitContext returns the waiting period preceding the given Attempt such
+// that the remaining attempts are spread across the time left before the
+// deadline.
+func (ds DeadlineSpread) WaitContext(
+	ctx context.Context, a Attempt,
+) time.Duration {
+	dl := ds.Deadline
+	if dl.IsZero() {
+		var ok bool
+		if dl, ok = ctx.Deadline(); !ok {
+			return ds.fallback().Wait(a.Iteration)
+		}
+	}
+
+	// n.b. An unlimited execution has no last attempt to spread towards.
+	if a.Iterations == Forever {
+		return ds.fallback().Wait(a.Iteration)
+	}
+
+	r := a.r
+	if r == nil {
+		r = new(Rerun)
+	}
+
+	left := dl.Sub(r.clk().Now())
+	return ds.spread(left, r.timeFactor(), a.Iteration, a.Iterations)
+}
+
+// spread returns the first term of a geometric progression, of the
+// receiver's Ratio, having one term for each of the retries from iteration i
+// to n-1 and totalling the time left (less Margin). Since the returned
+// waiting period will itself be multiplied by the time scale factor f, it is
+// divided by f here so that the spread is made across real time.
+func (ds DeadlineSpread) spread(
+	left time.Duration, f float64, i, n uint,
+) time.Duration {
+	if i == 0 || i >= n {
+		return 0
+	}
+
+	remaining := float64(left-ds.Margin) / f
+	if remaining <= 0 {
+		return 0
+	}
+
+	k := float64(n - i)
+	g := ds.Ratio
+
+	if g == 0 || g == 1 {
+		return time.Duration(remaining / k)
+	}
+
+	return time.Duration(remaining * (g - 1) / (math.Pow(g, k) - 1))
+}
+
+func (ds DeadlineSpread) fallback() Algorithm {
+	if ds.Fallback == nil {
+		return currentDefault()
+	}
+	return ds.Fallback
+}
+
+func (ds DeadlineSpread) String() string {
+	ratio := ds.Ratio
+	if ratio == 0 {
+		ratio = 1
+	}
+	return fmt.Sprintf("spread to deadline ×%g, margin %v", ratio, ds.Margin)
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestDeadlineSpread(t *testing.T) {
+	for _, tc := range []struct {
+		ratio float64
+		scale float64
+		want  []time.Duration
+	}{
+		{0, 1, []time.Duration{0, 30 * time.Second, 60 * time.Second, 90 * time.Second}},
+		{2, 1, []time.Duration{0, 12857142857, 38571428571, 90 * time.Second}}, // 90s/7, then ×2, ×4
+		{0, 0.5, []time.Duration{0, 30 * time.Second, 60 * time.Second, 90 * time.Second}},
+	} {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+			defer cancel()
+
+			var calls []time.Duration
+			start := time.Now()
+
+			err := New(4).
+				WithTimeScale(tc.scale).
+				WithAlgorithm(DeadlineSpread{Margin: 10 * time.Second, Ratio: tc.ratio}).
+				WithFunction(func(uint) error {
+					calls = append(calls, time.Since(start))
+					return ErrDoRetry
+				}).
+				Execute(ctx)
+
+			if !errors.Is(err, ErrAttemptsExhausted) {
+				t.Errorf("ratio %g, scale %g: Execute() == %v; wanted %v", tc.ratio, tc.scale, err, ErrAttemptsExhausted)
+			}
+
+			if len(calls) != len(tc.want) {
+				t.Fatalf("ratio %g, scale %g: calls at %v; wanted %v", tc.ratio, tc.scale, calls, tc.want)
+			}
+
+			for i := range calls {
+				if calls[i] != tc.want[i] {
+					t.Errorf("ratio %g, scale %g: calls at %v; wanted %v", tc.ratio, tc.scale, calls, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
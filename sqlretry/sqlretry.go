@@ -0,0 +1,195 @@
+// Copyright © 2024 Timothy E. Peoples
+
+// Package sqlretry provides a database/sql handle that retries statements and
+// transactions failing for transient reasons -- such as deadlocks,
+// serialization failures and connection resets -- according to a rerun.Rerun
+// policy:
+//
+//	db := &sqlretry.DB{DB: sqldb, Rerun: rerun.New(5).WithAlgorithm(algo)}
+//
+//	err := db.InTx(ctx, nil, func(ctx context.Context, tx *sql.Tx) error {
+//		// ...
+//	})
+//
+// Which errors are transient is decided by a pluggable classifier; see
+// Transient for the default.
+package sqlretry
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// DefaultRerun is the policy used by a DB having none of its own: up to 5
+// attempts with exponential backoff (50ms, 100ms, 200ms, 400ms) and full
+// jitter.
+var DefaultRerun = rerun.New(5).WithAlgorithm(rerun.WithJitter(rerun.ExponentialDelay{
+	Base:       50 * time.Millisecond,
+	Multiplier: 2,
+	Max:        time.Second,
+}, rerun.FullJitter))
+
+// These SQLSTATE codes, as defined by the SQL standard and PostgreSQL,
+// identify transient failures.
+const (
+	SQLStateSerializationFailure = "40001" // serialization_failure
+	SQLStateDeadlockDetected     = "40P01" // deadlock_detected
+)
+
+// Transient is the default classifier used by a DB. It reports whether err
+// describes a condition that is likely to clear on retry:
+//
+//   - a serialization failure or deadlock, as reported by any driver error
+//     implementing a "SQLState() string" method (as do those of pgx and
+//     lib/pq) with one of the SQLSTATE codes above;
+//   - driver.ErrBadConn; or
+//   - a connection that was reset, refused or unexpectedly closed.
+//
+// Context errors are never transient.
+func Transient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var ss interface{ SQLState() string }
+	if errors.As(err, &ss) {
+		switch ss.SQLState() {
+		case SQLStateSerializationFailure, SQLStateDeadlockDetected:
+			return true
+		}
+	}
+
+	return connectionLost(err)
+}
+
+// connectionLost reports whether err describes a connection that failed.
+func connectionLost(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		interrupted(err)
+}
+
+// interrupted reports whether err describes a connection that failed after
+// it may already have carried a statement to the server; unlike
+// driver.ErrBadConn, which drivers return only when nothing was sent.
+func interrupted(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// DB wraps a *sql.DB such that its ExecContext and QueryContext methods, and
+// transactions run using InTx, are retried according to Rerun whenever they
+// fail with a transient error. All other methods are those of the embedded
+// *sql.DB and are not retried. Errors are returned as from Rerun.Execute;
+// e.g. when retries are exhausted, the returned error wraps both
+// rerun.ErrAttemptsExhausted and the error from each attempt.
+type DB struct {
+	*sql.DB
+
+	// Rerun is the retry policy. Its Func is disregarded and each execution
+	// uses the Context given to the method called. If nil, DefaultRerun is
+	// used.
+	Rerun *rerun.Rerun
+
+	// Retryable reports whether an error is transient. If nil, Transient is
+	// used.
+	Retryable func(error) bool
+
+	// RetryUnsafe permits ExecContext to retry a statement whose connection
+	// was reset or unexpectedly closed while it was in flight, in which case
+	// the server may already have executed it. Set this only when the
+	// statements executed are idempotent.
+	RetryUnsafe bool
+}
+
+// ExecContext executes a query without returning any rows, as described for
+// sql.DB.ExecContext, retrying transient failures. Since a statement may have
+// taken effect even though its connection was then lost, only those lost
+// connections reported as driver.ErrBadConn (meaning that nothing was sent)
+// are retried, unless RetryUnsafe is set.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return rerun.Do(ctx, db.rerun(), func(uint) (sql.Result, error) {
+		res, err := db.DB.ExecContext(ctx, query, args...)
+		if err != nil && !db.RetryUnsafe && interrupted(err) && !errors.Is(err, driver.ErrBadConn) {
+			return res, err
+		}
+		return res, db.classify(err)
+	})
+}
+
+// QueryContext executes a query returning rows, as described for
+// sql.DB.QueryContext, retrying transient failures. Only the query itself is
+// retried; errors encountered while iterating over the returned Rows are
+// left to the caller.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return rerun.Do(ctx, db.rerun(), func(uint) (*sql.Rows, error) {
+		rows, err := db.DB.QueryContext(ctx, query, args...)
+		return rows, db.classify(err)
+	})
+}
+
+// InTx runs fn within a transaction begun using opts, committing it if fn
+// returns nil and rolling it back otherwise. Whenever beginning the
+// transaction, fn or committing it fails with a transient error, the
+// transaction is rolled back and the whole sequence is retried with a new
+// transaction -- so fn must be prepared to run more than once and should
+// have no effects outside of tx (or only idempotent ones).
+//
+// A commit failing because its connection was lost is never retried, since
+// the transaction may nevertheless have been committed; its error is
+// returned immediately.
+func (db *DB) InTx(ctx context.Context, opts *sql.TxOptions, fn func(context.Context, *sql.Tx) error) error {
+	return db.rerun().WithFunction(func(uint) error {
+		tx, err := db.DB.BeginTx(ctx, opts)
+		if err != nil {
+			return db.classify(err)
+		}
+
+		// n.b. This is a no-op once the transaction has been committed.
+		defer tx.Rollback()
+
+		if err = fn(ctx, tx); err != nil {
+			return db.classify(err)
+		}
+
+		if err = tx.Commit(); err != nil && connectionLost(err) {
+			return rerun.Permanent(err)
+		}
+
+		return db.classify(err)
+	}).Execute(ctx)
+}
+
+// classify returns a retry signal wrapping err if it is transient, or err
+// itself otherwise.
+func (db *DB) classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	retryable := db.Retryable
+	if retryable == nil {
+		retryable = Transient
+	}
+
+	if retryable(err) {
+		return rerun.ErrDoRetry.Wrap(err)
+	}
+
+	return err
+}
+
+func (db *DB) rerun() *rerun.Rerun {
+	if db.Rerun != nil {
+		return db.Rerun
+	}
+	return DefaultRerun
+}
@@ -0,0 +1,248 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package sqlretry
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/olympiclabs/rerun"
+)
+
+// sqlStateError mimics the errors of drivers such as pgx and lib/pq.
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// fakeDriver fails each operation (named "exec", "query", "begin", "commit"
+// or a statement given to fn) with the next error queued for it, logging
+// every operation it performs.
+type fakeDriver struct {
+	mu   sync.Mutex
+	errs map[string][]error
+	log  []string
+}
+
+func (fd *fakeDriver) next(op string) error {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.log = append(fd.log, op)
+
+	q := fd.errs[op]
+	if len(q) == 0 {
+		return nil
+	}
+	fd.errs[op] = q[1:]
+
+	return q[0]
+}
+
+func (fd *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{fd}, nil }
+
+type fakeConn struct{ fd *fakeDriver }
+
+func (fc *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fc *fakeConn) Close() error                        { return nil }
+func (fc *fakeConn) Begin() (driver.Tx, error)           { return fc, fc.fd.next("begin") }
+func (fc *fakeConn) Commit() error                       { return fc.fd.next("commit") }
+func (fc *fakeConn) Rollback() error                     { fc.fd.next("rollback"); return nil }
+
+func (fc *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := fc.fd.next(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (fc *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := fc.fd.next(query); err != nil {
+		return nil, err
+	}
+	return &fakeRows{n: 2}, nil
+}
+
+type fakeRows struct{ n int }
+
+func (fr *fakeRows) Columns() []string { return []string{"n"} }
+func (fr *fakeRows) Close() error      { return nil }
+
+func (fr *fakeRows) Next(dest []driver.Value) error {
+	if fr.n == 0 {
+		return io.EOF
+	}
+	dest[0], fr.n = int64(fr.n), fr.n-1
+	return nil
+}
+
+var driverSeq int
+
+func newDB(t *testing.T, errs map[string][]error) (*DB, *fakeDriver) {
+	t.Helper()
+
+	fd := &fakeDriver{errs: errs}
+	driverSeq++
+	name := fmt.Sprintf("sqlretry-fake-%d", driverSeq)
+	sql.Register(name, fd)
+
+	sdb, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sdb.Close() })
+
+	return &DB{DB: sdb, Rerun: rerun.New(3).WithAlgorithm(rerun.FixedDelay(0))}, fd
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("syntax error"), false},
+		{sqlStateError("23505"), false},
+		{sqlStateError(SQLStateSerializationFailure), true},
+		{fmt.Errorf("update: %w", sqlStateError(SQLStateDeadlockDetected)), true},
+		{driver.ErrBadConn, true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{io.ErrUnexpectedEOF, true},
+		{context.DeadlineExceeded, false},
+	}
+
+	for _, tc := range tests {
+		if got := Transient(tc.err); got != tc.want {
+			t.Errorf("Transient(%v) == %t; wanted %t", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestExecContext(t *testing.T) {
+	db, fd := newDB(t, map[string][]error{
+		"UPDATE t": {sqlStateError(SQLStateDeadlockDetected), sqlStateError(SQLStateSerializationFailure)},
+		"DELETE t": {errors.New("permission denied")},
+	})
+
+	if _, err := db.ExecContext(context.Background(), "UPDATE t"); err != nil {
+		t.Errorf("ExecContext() failed: %v", err)
+	}
+
+	if _, err := db.ExecContext(context.Background(), "DELETE t"); err == nil || err.Error() != "permission denied" {
+		t.Errorf("ExecContext() == %v; wanted %q", err, "permission denied")
+	}
+
+	if got, want := strings.Join(fd.log, ","), "UPDATE t,UPDATE t,UPDATE t,DELETE t"; got != want {
+		t.Errorf("operations == %q; wanted %q", got, want)
+	}
+}
+
+func TestExecContextInterrupted(t *testing.T) {
+	reset := fmt.Errorf("write: %w", syscall.ECONNRESET)
+	db, fd := newDB(t, map[string][]error{
+		"UPDATE t": {reset, driver.ErrBadConn, reset},
+	})
+
+	// A statement interrupted mid-flight may have been executed, so it is
+	// not retried.
+	if _, err := db.ExecContext(context.Background(), "UPDATE t"); err != reset {
+		t.Errorf("ExecContext() == %v; wanted %v", err, reset)
+	}
+
+	if got, want := strings.Join(fd.log, ","), "UPDATE t"; got != want {
+		t.Errorf("operations == %q; wanted %q", got, want)
+	}
+
+	// Unless the caller says it is safe to do so.
+	db.RetryUnsafe = true
+	fd.log = nil
+
+	if _, err := db.ExecContext(context.Background(), "UPDATE t"); err != nil {
+		t.Errorf("ExecContext() failed: %v", err)
+	}
+
+	// n.b. database/sql itself retries driver.ErrBadConn on a new connection.
+	if got, want := strings.Join(fd.log, ","), "UPDATE t,UPDATE t,UPDATE t"; got != want {
+		t.Errorf("operations == %q; wanted %q", got, want)
+	}
+}
+
+func TestQueryContext(t *testing.T) {
+	deadlock := sqlStateError(SQLStateDeadlockDetected)
+	db, fd := newDB(t, map[string][]error{"SELECT n": {deadlock, deadlock, deadlock}})
+
+	if _, err := db.QueryContext(context.Background(), "SELECT n"); !errors.Is(err, rerun.ErrAttemptsExhausted) || !errors.Is(err, deadlock) {
+		t.Fatalf("QueryContext() == %v; wanted exhaustion wrapping %v", err, deadlock)
+	}
+
+	db.Retryable = func(error) bool { return false }
+	fd.errs["SELECT n"] = []error{deadlock}
+	if _, err := db.QueryContext(context.Background(), "SELECT n"); err != deadlock {
+		t.Errorf("QueryContext() == %v; wanted %v", err, deadlock)
+	}
+
+	rows, err := db.QueryContext(context.Background(), "SELECT n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var sum int
+	for rows.Next() {
+		var n int
+		rows.Scan(&n)
+		sum += n
+	}
+	if sum != 3 {
+		t.Errorf("sum of rows == %d; wanted 3", sum)
+	}
+}
+
+func TestInTx(t *testing.T) {
+	db, fd := newDB(t, map[string][]error{
+		"INSERT t": {sqlStateError(SQLStateSerializationFailure)},
+		"commit":   {sqlStateError(SQLStateSerializationFailure)},
+	})
+
+	var runs int
+	err := db.InTx(context.Background(), nil, func(ctx context.Context, tx *sql.Tx) error {
+		runs++
+		_, err := tx.ExecContext(ctx, "INSERT t")
+		return err
+	})
+	if err != nil || runs != 3 {
+		t.Errorf("InTx() == %v after %d runs; wanted <nil> after 3", err, runs)
+	}
+
+	// n.b. A failed commit ends the transaction without a rollback.
+	want := "begin,INSERT t,rollback,begin,INSERT t,commit,begin,INSERT t,commit"
+	if got := strings.Join(fd.log, ","); got != want {
+		t.Errorf("operations == %q; wanted %q", got, want)
+	}
+}
+
+func TestInTxAmbiguousCommit(t *testing.T) {
+	reset := fmt.Errorf("commit: %w", syscall.ECONNRESET)
+	db, fd := newDB(t, map[string][]error{"commit": {reset}})
+
+	var runs int
+	err := db.InTx(context.Background(), nil, func(context.Context, *sql.Tx) error {
+		runs++
+		return nil
+	})
+	if err != reset || runs != 1 {
+		t.Errorf("InTx() == %v after %d runs; wanted %v after 1", err, runs, reset)
+	}
+
+	if got, want := strings.Join(fd.log, ","), "begin,commit"; got != want {
+		t.Errorf("operations == %q; wanted %q", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the aggregate counters maintained by a Rerun across
+// all of its calls to Execute.
+type Stats struct {
+	// Executions is the number of calls made to Execute (or ExecuteReport).
+	Executions uint64
+
+	// Attempts is the total number of calls made to the Func.
+	Attempts uint64
+
+	// Retries is the number of attempts made after the first within each
+	// execution.
+	Retries uint64
+
+	// Exhaustions is the number of executions that returned
+	// ErrAttemptsExhausted.
+	Exhaustions uint64
+
+	// Waited is the cumulative time spent in warmup and waiting periods.
+	Waited time.Duration
+
+	// Budget is a snapshot of the RetryBudget attached to the Rerun (see
+	// WithBudget), or nil if there is none. Since a RetryBudget is usually
+	// shared, this reflects the retries of all Reruns using it.
+	Budget *BudgetStats
+}
+
+// Stats returns a snapshot of the receiver's aggregate counters, giving
+// services cheap per-policy observability without wiring up a metrics
+// backend. The counters are shared by all Reruns derived from the same call
+// to New through its option methods (e.g. WithAlgorithm or WithFunction).
+// A Rerun not created by New always returns zero counters.
+func (r Rerun) Stats() Stats {
+	var s Stats
+
+	if r.stats != nil {
+		s = Stats{
+			Executions:  r.stats.executions.Load(),
+			Attempts:    r.stats.attempts.Load(),
+			Retries:     r.stats.retries.Load(),
+			Exhaustions: r.stats.exhaustions.Load(),
+			Waited:      time.Duration(r.stats.waited.Load()),
+		}
+	}
+
+	if r.budget != nil {
+		bs := r.budget.Stats()
+		s.Budget = &bs
+	}
+
+	return s
+}
+
+// counters holds the atomic counters behind Rerun.Stats. All of its methods
+// are no-ops for a nil receiver.
+type counters struct {
+	executions  atomic.Uint64
+	attempts    atomic.Uint64
+	retries     atomic.Uint64
+	exhaustions atomic.Uint64
+	waited      atomic.Int64
+}
+
+func (c *counters) execution() {
+	if c != nil {
+		c.executions.Add(1)
+	}
+}
+
+func (c *counters) attempt(i uint) {
+	if c == nil {
+		return
+	}
+
+	c.attempts.Add(1)
+	if i > 0 {
+		c.retries.Add(1)
+	}
+}
+
+func (c *counters) exhaustion() {
+	if c != nil {
+		c.exhaustions.Add(1)
+	}
+}
+
+func (c *counters) wait(d time.Duration) {
+	if c != nil {
+		c.waited.Add(int64(d))
+	}
+}
+
+// pause calls sleep, accounting the time spent in the receiver's Stats and
+// in rp (if not nil). If the receiver was configured using WithoutSleep,
+// positive durations are skipped entirely; otherwise, they are first scaled
+// by the receiver's time scale factor (see WithTimeScale).
+func (r Rerun) pause(ctx context.Context, clk Clock, rp *Report, d time.Duration) error {
+	d = r.scale(d)
+
+	switch {
+	case d == 0:
+		return nil
+	case d > 0 && r.noSleep:
+		return ctx.Err()
+	}
+
+	start := clk.Now()
+	err := sleep(ctx, clk, d)
+	slept := clk.Now().Sub(start)
+
+	r.stats.wait(slept)
+	if rp != nil {
+		rp.Slept += slept
+	}
+
+	return err
+}
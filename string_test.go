@@ -0,0 +1,34 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"testing"
+	"time"
+)
+
+func TestString(t *testing.T) {
+	for _, tc := range []struct {
+		r    *Rerun
+		want string
+	}{
+		{New(3), "3 attempts, fixed 1s"},
+		{New(2).WithName("payments-api"), "payments-api: 2 attempts, fixed 1s"},
+		{
+			New(5).WithAlgorithm(ExponentialDelay{Base: 200 * time.Millisecond, Multiplier: 2, Max: 30 * time.Second}),
+			"5 attempts, exponential 200ms×2 cap 30s",
+		},
+		{
+			New(4).WithAlgorithm(LinearDelay{Start: time.Second, Base: 100 * time.Millisecond, Slope: float64(-25 * time.Millisecond)}).WithRetryOnPanic(2),
+			"4 attempts, linear 100ms-25ms/retry, warmup 1s, retry up to 2 panics",
+		},
+		{
+			New(6).WithAlgorithm(LogarithmicDelay{Units: Millisecond, Amplifier: 300, Coefficient: 20, Modifier: -14, VerticalOffset: -400}),
+			"6 attempts, logarithmic (300·ln(20·n-14)-400)/1×1ms",
+		},
+	} {
+		if got := tc.r.String(); got != tc.want {
+			t.Errorf("String() == %q; wanted %q", got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestExecuteSynctest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls []time.Duration
+
+		start := time.Now()
+		r := New(3).
+			WithAlgorithm(LinearDelay{Start: time.Minute, Base: time.Hour}).
+			WithFunction(func(uint) error {
+				calls = append(calls, time.Since(start))
+				return ErrDoRetry
+			})
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() returned %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		want := []time.Duration{time.Minute, 61 * time.Minute, 121 * time.Minute}
+		if len(calls) != len(want) {
+			t.Fatalf("Func called %d times; wanted %d", len(calls), len(want))
+		}
+
+		for i, c := range calls {
+			if c != want[i] {
+				t.Errorf("call %d at %v; wanted %v", i, c, want[i])
+			}
+		}
+	})
+}
+
+func TestExecuteSynctestDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		r := New(5).
+			WithAlgorithm(FixedDelay(time.Hour)).
+			WithFunction(func(uint) error { return ErrDoRetry })
+
+		if err := r.Execute(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Execute() returned %v; wanted %v", err, context.DeadlineExceeded)
+		}
+
+		if got := time.Since(start); got != 90*time.Minute {
+			t.Errorf("Execute() returned after %v; wanted %v", got, 90*time.Minute)
+		}
+	})
+}
+
+func TestExecuteWithoutSleep(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls int
+
+		start := time.Now()
+		r := New(4).
+			WithAlgorithm(LinearDelay{Start: time.Minute, Base: time.Hour}).
+			WithoutSleep().
+			WithFunction(func(uint) error {
+				calls++
+				return ErrDoRetry
+			})
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() returned %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if calls != 4 {
+			t.Errorf("Func called %d times; wanted 4", calls)
+		}
+
+		if elapsed := time.Since(start); elapsed != 0 {
+			t.Errorf("Execute took %v; wanted 0", elapsed)
+		}
+	})
+}
+
+func TestExecuteWithTimeScale(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		start := time.Now()
+		r := New(3).
+			WithAlgorithm(LinearDelay{Start: time.Minute, Base: time.Hour}).
+			WithTimeScale(0.01).
+			WithFunction(func(uint) error { return ErrDoRetry })
+
+		if err := r.Execute(context.Background()); !errors.Is(err, ErrAttemptsExhausted) {
+			t.Errorf("Execute() returned %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		if got, want := time.Since(start), (time.Minute+2*time.Hour)/100; got != want {
+			t.Errorf("Execute took %v; wanted %v", got, want)
+		}
+	})
+
+	if err := New(3).WithTimeScale(0).Err(); !errors.Is(err, ErrInvalidMultiplier) {
+		t.Errorf("WithTimeScale(0).Err() == %v; wanted %v", err, ErrInvalidMultiplier)
+	}
+}
+
+func TestExecuteFirstAttemptGuarantee(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		var calls int
+		r := New(3).
+			WithAlgorithm(LinearDelay{Start: time.Hour, Base: time.Hour}).
+			WithFunction(func(uint) error {
+				calls++
+				return nil
+			})
+
+		if err := r.Execute(ctx); err != context.DeadlineExceeded || calls != 0 {
+			t.Errorf("Execute() == %v with %d calls; wanted %v with 0 calls", err, calls, context.DeadlineExceeded)
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		if err := r.WithFirstAttemptGuarantee().Execute(ctx); err != nil || calls != 1 {
+			t.Errorf("Execute() == %v with %d calls; wanted nil with 1 call", err, calls)
+		}
+
+		if elapsed := time.Since(start); elapsed != 0 {
+			t.Errorf("first attempt made after %v; wanted 0", elapsed)
+		}
+	})
+}
+
+func TestExecuteColdStartWarmup(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var (
+			fail  bool
+			state WarmState
+		)
+
+		r := New(2).
+			WithAlgorithm(Fixed{Delay: time.Second, Start: time.Minute}).
+			WithColdStartWarmup(time.Hour, &state).
+			WithFunction(func(uint) error {
+				if fail {
+					return errors.New("failed")
+				}
+				return nil
+			})
+
+		for _, tc := range []struct {
+			fail bool
+			idle time.Duration
+			want time.Duration
+		}{
+			{want: time.Minute},                        // cold
+			{idle: 59 * time.Minute},                   // warm
+			{idle: 59 * time.Minute, fail: true},       // still warm; success not recorded
+			{idle: 2 * time.Minute, want: time.Minute}, // cold again
+		} {
+			time.Sleep(tc.idle)
+			fail = tc.fail
+
+			start := time.Now()
+			r.Execute(context.Background())
+
+			if got := time.Since(start); got != tc.want {
+				t.Errorf("after %v idle, first attempt made after %v; wanted %v", tc.idle, got, tc.want)
+			}
+		}
+
+		if got, want := state.LastSuccess(), time.Now(); !got.Equal(want) {
+			t.Errorf("LastSuccess() == %v; wanted %v", got, want)
+		}
+
+		if err := New(2).WithColdStartWarmup(-time.Second, nil).Err(); !errors.Is(err, ErrNegativeDuration) {
+			t.Errorf("Err() == %v; wanted %v", err, ErrNegativeDuration)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Ticker delivers the schedule of an Algorithm as "ticks" on a channel, for
+// code wishing to drive its own retry loop. Like a time.Ticker, except that
+// ticks follow the Algorithm's curve: the first is delivered after its
+// warmup period and each subsequent tick n after Wait(n).
+//
+// Every tick is delivered; the waiting period before the next tick begins
+// only once the previous one has been received, so a slow consumer delays
+// the schedule rather than missing attempts. Once the Ticker is exhausted or
+// stopped (or its Context becomes done), C is closed and Err reports why:
+//
+//	t := rerun.NewTicker(ctx, 5, algo)
+//	defer t.Stop()
+//
+//	for range t.C {
+//		if err := try(); err == nil {
+//			return nil
+//		}
+//	}
+//
+//	return t.Err()
+//
+// A Ticker must be created by NewTicker.
+type Ticker struct {
+	// C is the channel on which ticks are delivered, each holding the time
+	// at which it fell due.
+	C <-chan time.Time
+
+	cancel  context.CancelFunc
+	resets  chan struct{}
+	done    chan struct{}
+	stopped atomic.Bool
+	err     error
+}
+
+// NewTicker returns a new Ticker delivering up to iterations ticks (or an
+// unlimited number, given Forever) according to algo's schedule. The Ticker
+// stops when ctx becomes done. Should algo be nil or invalid for the given
+// iterations (see Algorithm.OK), or iterations be less than 2, C is closed
+// at once and Err reports the problem. As for Execute, a stateful algo (see
+// Resetter) is reset before use.
+func NewTicker(ctx context.Context, iterations uint, algo Algorithm) *Ticker {
+	c := make(chan time.Time)
+
+	t := &Ticker{
+		C:      c,
+		resets: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	ctx, t.cancel = context.WithCancel(ctx)
+	go t.run(ctx, c, iterations, algo)
+
+	return t
+}
+
+// Stop stops the receiver, closing C if it was not closed already. Unlike
+// the Stop method of a time.Ticker, it waits for the receiver to finish so
+// that no further ticks can be delivered once it returns. Err returns nil
+// after the receiver has been stopped (unless it had already stopped for
+// another reason).
+func (t *Ticker) Stop() {
+	t.stopped.Store(true)
+	t.cancel()
+	<-t.done
+}
+
+// Reset restarts the receiver's progression, as if its first tick had just
+// been received: the next tick is delivered after Wait(1) and the full
+// number of iterations remains. Any tick not yet received is discarded and a
+// stateful Algorithm is reset (see Resetter). This is useful after a period
+// of healthy operation, so that the next failure is retried promptly rather
+// than after the longest waiting period the receiver had reached. Reset has
+// no effect once C is closed.
+func (t *Ticker) Reset() {
+	select {
+	case t.resets <- struct{}{}:
+	default:
+		// n.b. A reset is already pending.
+	}
+}
+
+// Err returns nil until C is closed and then the reason it was closed:
+// ErrAttemptsExhausted once all ticks have been delivered, the Context's
+// error if it became done, an error describing an invalid configuration,
+// or nil if the receiver was stopped.
+func (t *Ticker) Err() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
+
+func (t *Ticker) run(ctx context.Context, c chan<- time.Time, iterations uint, algo Algorithm) {
+	defer close(t.done)
+	defer close(c)
+
+	err := t.tick(ctx, c, iterations, algo)
+	if t.stopped.Load() && ctx.Err() != nil {
+		err = nil
+	}
+
+	t.err = err
+}
+
+// tick delivers ticks on c until the receiver is exhausted, returning the
+// reason it stopped.
+func (t *Ticker) tick(ctx context.Context, c chan<- time.Time, iterations uint, algo Algorithm) error {
+	switch {
+	case algo == nil:
+		return ErrNilAlgorithm
+	case iterations < 2:
+		return ErrTooFewIterations
+	}
+
+	if err := algo.OK(iterations); err != nil {
+		return err
+	}
+
+	a := reset(algo)
+	d := a.Warmup()
+
+	for i := uint(0); i < iterations || iterations == Forever; i++ {
+		if i > 0 {
+			if d = a.Wait(i); d < 0 {
+				return waitError(i, d)
+			}
+		}
+
+		timer := time.NewTimer(d)
+
+		var due time.Time
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-t.resets:
+			timer.Stop()
+			a, i = reset(algo), 0
+			continue
+
+		case due = <-timer.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-t.resets:
+			a, i = reset(algo), 0
+
+		case c <- due:
+		}
+	}
+
+	return ErrAttemptsExhausted
+}
@@ -0,0 +1,105 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestTicker(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		start := time.Now()
+		tk := NewTicker(context.Background(), 4, LinearDelay{Start: time.Second, Base: time.Second, SlopeDuration: time.Second})
+
+		var got []time.Duration
+		for due := range tk.C {
+			// n.b. Receiving the second tick late delays those following it.
+			if got = append(got, due.Sub(start)); len(got) == 1 {
+				time.Sleep(1500 * time.Millisecond)
+			}
+		}
+
+		want := []time.Duration{time.Second, 2 * time.Second, 4500 * time.Millisecond, 7500 * time.Millisecond}
+		if !slices.Equal(got, want) {
+			t.Errorf("ticks at %v; wanted %v", got, want)
+		}
+
+		if err := tk.Err(); err != ErrAttemptsExhausted {
+			t.Errorf("Err() == %v; wanted %v", err, ErrAttemptsExhausted)
+		}
+
+		tk.Stop()
+		if err := tk.Err(); err != ErrAttemptsExhausted {
+			t.Errorf("Err() == %v after Stop; wanted %v", err, ErrAttemptsExhausted)
+		}
+	})
+}
+
+func TestTickerStop(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+		defer cancel()
+
+		tk := NewTicker(ctx, Forever, FixedDelay(time.Second))
+
+		var n int
+		for range tk.C {
+			n++
+		}
+		if n != 3 || !errors.Is(tk.Err(), context.DeadlineExceeded) {
+			t.Errorf("delivered %d ticks and Err() == %v; wanted 3 and %v", n, tk.Err(), context.DeadlineExceeded)
+		}
+
+		tk = NewTicker(context.Background(), Forever, FixedDelay(time.Second))
+		<-tk.C
+		tk.Stop()
+
+		if _, ok := <-tk.C; ok || tk.Err() != nil {
+			t.Errorf("after Stop: C open == %t, Err() == %v; wanted false, <nil>", ok, tk.Err())
+		}
+
+		if tk := NewTicker(context.Background(), 1, FixedDelay(time.Second)); readAll(tk.C) != 0 || tk.Err() != ErrTooFewIterations {
+			t.Errorf("Err() == %v; wanted %v", tk.Err(), ErrTooFewIterations)
+		}
+
+		if tk := NewTicker(context.Background(), 3, nil); readAll(tk.C) != 0 || tk.Err() != ErrNilAlgorithm {
+			t.Errorf("Err() == %v; wanted %v", tk.Err(), ErrNilAlgorithm)
+		}
+	})
+}
+
+func TestTickerReset(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		start := time.Now()
+		tk := NewTicker(context.Background(), 3, LinearDelay{Base: time.Second, SlopeDuration: 10 * time.Second})
+		defer tk.Stop()
+
+		var got []time.Duration
+		for due := range tk.C {
+			got = append(got, due.Sub(start))
+			if len(got) == 2 {
+				// n.b. The next tick would otherwise follow 21s later.
+				tk.Reset()
+			}
+		}
+
+		want := []time.Duration{0, time.Second, 2 * time.Second, 13 * time.Second}
+		if !slices.Equal(got, want) {
+			t.Errorf("ticks at %v; wanted %v", got, want)
+		}
+	})
+}
+
+func readAll(c <-chan time.Time) (n int) {
+	for range c {
+		n++
+	}
+	return n
+}
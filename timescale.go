@@ -0,0 +1,75 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TimeScaleEnv is the name of an environment variable that, when set to a
+// positive number, provides the time scale factor (see WithTimeScale) for
+// all Reruns not configured with one explicitly. Since it is read only once,
+// it must be set before the first call to Execute. Invalid values are ignored.
+//
+// This allows staging environments to run production retry policies at an
+// accelerated pace (e.g. RERUN_TIME_SCALE=0.01) without any change in code.
+const TimeScaleEnv = "RERUN_TIME_SCALE"
+
+var envTimeScale = sync.OnceValue(func() float64 {
+	f, err := strconv.ParseFloat(os.Getenv(TimeScaleEnv), 64)
+	if err != nil || !validTimeScale(f) {
+		return 1
+	}
+	return f
+})
+
+func validTimeScale(f float64) bool {
+	return f > 0 && !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// WithTimeScale returns a pointer to its receiver after configuring Execute to
+// multiply every warmup and waiting period by factor f. For example, a factor
+// of 0.01 turns a 10s wait into 100ms. The factor must be positive; otherwise,
+// subsequent calls to the receiver's Err method will return an error wrapping
+// ErrInvalidMultiplier. A factor configured here takes precedence over any
+// provided by the environment (see TimeScaleEnv).
+func (r Rerun) WithTimeScale(f float64) *Rerun {
+	r.timeScale = &f
+	return &r
+}
+
+func (r Rerun) timeScaleErr() error {
+	if r.timeScale == nil || validTimeScale(*r.timeScale) {
+		return nil
+	}
+	return fieldError("TimeScale", *r.timeScale, ErrInvalidMultiplier)
+}
+
+// timeFactor returns the receiver's time scale factor.
+func (r Rerun) timeFactor() float64 {
+	if r.timeScale != nil {
+		return *r.timeScale
+	}
+	return envTimeScale()
+}
+
+// scale returns d multiplied by the receiver's time scale factor. Values too
+// large to be represented by a time.Duration are truncated to the largest
+// possible Duration.
+func (r Rerun) scale(d time.Duration) time.Duration {
+	f := r.timeFactor()
+
+	if f == 1 || d <= 0 {
+		return d
+	}
+
+	if s := float64(d) * f; s < math.MaxInt64 {
+		return time.Duration(s)
+	}
+
+	return math.MaxInt64
+}
@@ -0,0 +1,101 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// WeibullDelay is a randomized Algorithm drawing each waiting period from a
+// Weibull distribution defined by two intuitive parameters: a Shape and a
+// Scale. A Shape below 1 produces front-loaded spacing (mostly short waits
+// with an occasional long one), a Shape of 1 is the exponential distribution,
+// and larger Shapes cluster waits ever more tightly around Scale -- becoming
+// tail-heavy, with few short waits, beyond about 3.6.
+type WeibullDelay struct {
+	// Start defines the warmup time Rerun uses before its first call to a Func.
+	// A negative value will cause the OK method to return ErrNegativeDuration.
+	Start time.Duration
+
+	// Shape is the Weibull shape parameter (often called k), which must be
+	// positive and finite; otherwise, the OK method returns ErrInvalidShape.
+	Shape float64
+
+	// Scale is the Weibull scale parameter (often called λ); roughly 63% of
+	// waits will be shorter than Scale. It must be positive.
+	Scale time.Duration
+
+	// Max, if non-zero, is the upper bound for any waiting period. A
+	// negative value causes the OK method to return ErrNegativeDuration.
+	Max time.Duration
+
+	// Rand is the source of randomness for all waiting periods. If nil,
+	// the top-level functions from math/rand/v2 are used.
+	Rand Random
+}
+
+// OK returns an error if any of the receiver's fields are invalid. All
+// invalid fields are reported together (using errors.Join).
+// This method contributes to implementing the Algorithm interface.
+func (wd WeibullDelay) OK(uint) error {
+	var errs []error
+
+	if wd.Start < 0 {
+		errs = append(errs, fieldError("WeibullDelay.Start", wd.Start, ErrNegativeDuration))
+	}
+
+	if !(wd.Shape > 0) || math.IsInf(wd.Shape, 0) {
+		errs = append(errs, fieldError("WeibullDelay.Shape", wd.Shape, ErrInvalidShape))
+	}
+
+	switch {
+	case wd.Scale < 0:
+		errs = append(errs, fieldError("WeibullDelay.Scale", wd.Scale, ErrNegativeDuration))
+	case wd.Scale == 0:
+		errs = append(errs, fieldError("WeibullDelay.Scale", wd.Scale, ErrZeroValue))
+	}
+
+	if wd.Max < 0 {
+		errs = append(errs, fieldError("WeibullDelay.Max", wd.Max, ErrNegativeDuration))
+	}
+
+	return joinErrors(errs...)
+}
+
+// Warmup returns the value of the receiver's Start field in order to satisfy
+// the Algorithm interface.
+func (wd WeibullDelay) Warmup() time.Duration {
+	return wd.Start
+}
+
+// Wait returns a waiting period drawn from the receiver's distribution for
+// any iteration but zero. Wait is part of the Algorithm interface.
+func (wd WeibullDelay) Wait(n uint) time.Duration {
+	if n == 0 {
+		return 0
+	}
+
+	// Inverse transform sampling; 1-u lies in (0,1] so the log is finite.
+	u := RandomFloat64(wd.Rand)
+	d := float64(wd.Scale) * math.Pow(-math.Log(1-u), 1/wd.Shape)
+
+	if wd.Max > 0 && d > float64(wd.Max) {
+		return wd.Max
+	}
+
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(d)
+}
+
+func (wd WeibullDelay) String() string {
+	var limit string
+	if wd.Max > 0 {
+		limit = " cap " + wd.Max.String()
+	}
+	return fmt.Sprintf("weibull k=%g λ=%v%s%s", wd.Shape, wd.Scale, limit, warmupString(wd.Start))
+}
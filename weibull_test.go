@@ -0,0 +1,48 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWeibullDelay(t *testing.T) {
+	wd := WeibullDelay{Shape: 1.5, Scale: time.Second, Rand: NewRandom(42)}
+
+	// The reproducibility promised by a seeded Random.
+	again := WeibullDelay{Shape: 1.5, Scale: time.Second, Rand: NewRandom(42)}
+	for i := uint(1); i < 10; i++ {
+		if a, b := wd.Wait(i), again.Wait(i); a != b {
+			t.Fatalf("Wait(%d) == %v and %v for the same seed", i, a, b)
+		}
+	}
+
+	if err := CheckAlgorithm(wd, 1000); err != nil {
+		t.Fatalf("CheckAlgorithm() == %v", err)
+	}
+
+	// For k=1.5, the median is λ·ln(2)^(1/k) ≈ 783ms.
+	stats, err := SimulateSchedule(wd, 2, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p50 := stats.Waits[0].P50; p50 < 740*time.Millisecond || p50 > 830*time.Millisecond {
+		t.Errorf("median wait == %v; wanted about 783ms", p50)
+	}
+
+	for _, bad := range []WeibullDelay{
+		{Shape: 0, Scale: time.Second},
+		{Shape: -1, Scale: time.Second},
+	} {
+		if err := bad.OK(3); !errors.Is(err, ErrInvalidShape) {
+			t.Errorf("%+v.OK() == %v; wanted %v", bad, err, ErrInvalidShape)
+		}
+	}
+
+	if err := (WeibullDelay{Shape: 1}).OK(3); !errors.Is(err, ErrZeroValue) {
+		t.Errorf("OK() with zero Scale returned %v; wanted %v", err, ErrZeroValue)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package rerun
+
+import (
+	"sync"
+	"time"
+)
+
+// TimerWheel is a Clock multiplexing the timers of any number of concurrent
+// executions onto a single, shared ticker. Services running thousands of
+// retry loops at once may attach one TimerWheel to all of their Reruns
+// (using WithClock) to relieve the pressure placed on the runtime by a
+// separate timer for every sleeping goroutine.
+//
+// Timers are kept in a ring of slots, each covering one tick, and expire
+// when the ticker reaches their slot. A timer therefore fires up to one tick
+// late, though never early, so the tick should be small relative to the
+// waiting periods in use. The ticker runs only while timers are pending.
+//
+// A TimerWheel is safe for concurrent use and must be created by
+// NewTimerWheel. Its time is that of the standard time package, making it
+// compatible with testing/synctest provided it is created within the bubble.
+type TimerWheel struct {
+	tick time.Duration
+
+	mu      sync.Mutex
+	slots   [][]*wheelTimer
+	pos     int       // the slot most recently expired
+	base    time.Time // the time at which slot pos expired
+	pending int       // timers neither fired nor stopped
+	running bool      // whether the ticker goroutine is running
+}
+
+// NewTimerWheel returns a new TimerWheel with the given tick and number of
+// slots. Waiting periods longer than a full rotation of the wheel (tick
+// times slots) are supported but are examined once per rotation. A tick
+// less than 1ms is increased to 1ms and fewer than 2 slots are increased to
+// 2.
+func NewTimerWheel(tick time.Duration, slots int) *TimerWheel {
+	return &TimerWheel{
+		tick:  max(tick, time.Millisecond),
+		slots: make([][]*wheelTimer, max(slots, 2)),
+	}
+}
+
+// Now returns the current time.
+// This method contributes to implementing the Clock interface.
+func (w *TimerWheel) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer returns a Timer, scheduled on the receiver, that delivers the
+// current time on its channel after at least d has elapsed.
+// This method contributes to implementing the Clock interface.
+func (w *TimerWheel) NewTimer(d time.Duration) Timer {
+	now := time.Now()
+	t := &wheelTimer{w: w, c: make(chan time.Time, 1), when: now.Add(d)}
+
+	if d <= 0 {
+		t.fire(now)
+		return t
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.running {
+		w.base, w.running = now, true
+		go w.run()
+	}
+
+	// n.b. Rounding up ensures the slot is expired no sooner than t.when.
+	ticks := int((t.when.Sub(w.base) + w.tick - 1) / w.tick)
+	n := (w.pos + ticks) % len(w.slots)
+	w.slots[n] = append(w.slots[n], t)
+	w.pending++
+
+	return t
+}
+
+// run drives the receiver's ticker until no timers remain pending.
+func (w *TimerWheel) run() {
+	tk := time.NewTicker(w.tick)
+	defer tk.Stop()
+
+	for now := range tk.C {
+		if !w.advance(now) {
+			return
+		}
+	}
+}
+
+// advance expires every slot due by now, firing its timers, and reports
+// whether any timers remain pending. If not, the receiver is marked as no
+// longer running.
+func (w *TimerWheel) advance(now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for !w.base.Add(w.tick).After(now) {
+		w.base = w.base.Add(w.tick)
+		w.pos = (w.pos + 1) % len(w.slots)
+
+		slot := w.slots[w.pos][:0]
+		for _, t := range w.slots[w.pos] {
+			switch {
+			case t.done:
+			case t.when.After(now):
+				slot = append(slot, t) // due in a later rotation
+			default:
+				t.fire(now)
+				w.pending--
+			}
+		}
+		clear(w.slots[w.pos][len(slot):])
+		w.slots[w.pos] = slot
+	}
+
+	if w.pending == 0 {
+		w.running = false
+	}
+
+	return w.running
+}
+
+// wheelTimer is the Timer returned by TimerWheel.NewTimer. Its done field is
+// guarded by the mutex of its TimerWheel.
+type wheelTimer struct {
+	w    *TimerWheel
+	c    chan time.Time
+	when time.Time
+	done bool
+}
+
+func (t *wheelTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *wheelTimer) Stop() bool {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+
+	if t.done {
+		return false
+	}
+
+	// n.b. The timer is discarded once its slot expires.
+	t.done = true
+	t.w.pending--
+
+	return true
+}
+
+// fire marks the receiver as done and delivers now on its channel.
+func (t *wheelTimer) fire(now time.Time) {
+	t.done = true
+	t.c <- now
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 Timothy E. Peoples
+
+//go:build go1.25
+
+package rerun
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestTimerWheel(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		w := NewTimerWheel(100*time.Millisecond, 8)
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for _, d := range []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond, 3 * time.Second} {
+			wg.Go(func() {
+				fired := (<-w.NewTimer(d).C()).Sub(start)
+				if fired < d || fired >= d+100*time.Millisecond {
+					t.Errorf("timer for %v fired after %v", d, fired)
+				}
+			})
+		}
+
+		stopped := w.NewTimer(time.Second)
+		if !stopped.Stop() || stopped.Stop() {
+			t.Error("Stop() did not return true then false")
+		}
+
+		wg.Wait()
+
+		select {
+		case <-stopped.C():
+			t.Error("stopped timer fired")
+		default:
+		}
+
+		// n.b. synctest.Test fails unless the ticker goroutine has exited.
+	})
+}
+
+func TestExecuteTimerWheel(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		w := NewTimerWheel(10*time.Millisecond, 64)
+
+		r := New(4).
+			WithAlgorithm(FixedDelay(time.Second)).
+			WithClock(w).
+			WithFunction(func(uint) error { return ErrDoRetry })
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for range 1000 {
+			wg.Go(func() { r.Execute(context.Background()) })
+		}
+		wg.Wait()
+
+		if got := time.Since(start); got < 3*time.Second || got > 3*time.Second+30*time.Millisecond {
+			t.Errorf("executions took %v; wanted ~3s", got)
+		}
+	})
+}